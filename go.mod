@@ -3,6 +3,8 @@ module spike-interpreter-go
 go 1.12
 
 require (
+	github.com/peterh/liner v1.2.2
 	github.com/pkg/errors v0.8.1
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/text v0.3.8
 )
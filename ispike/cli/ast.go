@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+)
+
+// printAST prints the tree a script parses to, as text or with --json as
+// JSON.
+func printAST(args []string, out io.Writer) int {
+	set, options := newFlagSet("ast", out)
+	asJSON := set.Bool("json", false, "")
+	paths, ok := parseInterleaved(set, options, args, out)
+	if !ok || len(paths) != 1 {
+		return usage(out)
+	}
+	path := paths[0]
+
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	program, err := parser.New(lexer.New(bytes.NewReader(source))).ParseProgram()
+	if err != nil {
+		fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), err))
+		return 1
+	}
+
+	if !*asJSON {
+		fmt.Fprint(out, ast.Dump(program))
+		return 0
+	}
+
+	serialized, err := ast.MarshalJSON(program)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	fmt.Fprintf(out, "%s\n", serialized)
+	return 0
+}
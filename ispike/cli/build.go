@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/object"
+	"strings"
+)
+
+const bytecodeExtension = ".spikec"
+
+// build compiles a script to a bytecode file that run can execute without
+// compiling it again.
+func build(args []string, out io.Writer) int {
+	set, options := newFlagSet("build", out)
+	output := set.String("o", "", "")
+	if !parseFlags(set, options, args, out) || set.NArg() != 1 {
+		return usage(out)
+	}
+	path := set.Arg(0)
+
+	if *output == "" {
+		*output = strings.TrimSuffix(path, ".spike") + bytecodeExtension
+	}
+
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return exitNoInput
+	}
+
+	_, c, err := compileSource(source)
+	if err != nil {
+		fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), err))
+		return exitCompileError
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+	defer file.Close()
+
+	err = compiler.WriteFile(file, c.Bytecode(), c.SymbolTable())
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	return 0
+}
+
+// disasm prints the instructions of a script or a built program, followed by
+// its constants and the instructions of every function among them.
+func disasm(args []string, out io.Writer) int {
+	set, options := newFlagSet("disasm", out)
+	if !parseFlags(set, options, args, out) || set.NArg() != 1 {
+		return usage(out)
+	}
+	path := set.Arg(0)
+
+	bytecode, _, source, err := load(path)
+	if err != nil {
+		fmt.Fprintf(out, "%s: %s\n", path, render(source, err))
+		return 1
+	}
+
+	fmt.Fprintf(out, "== main ==\n%s", bytecode.Instructions)
+	for i, constant := range bytecode.Constants {
+		function, ok := constant.(*object.CompiledFunction)
+		if !ok {
+			fmt.Fprintf(out, "\n== constant %d ==\n%s\n", i, constant.Inspect())
+			continue
+		}
+
+		name := function.Name
+		if name == "" {
+			name = "<anonymous>"
+		}
+		fmt.Fprintf(
+			out,
+			"\n== constant %d: fn %s, %d parameters, %d locals ==\n%s",
+			i,
+			name,
+			function.ParametersCount,
+			function.LocalsCount,
+			function.Instructions,
+		)
+	}
+
+	return 0
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+)
+
+// check compiles every file without running it and reports the first error of
+// each, or the hints about its recursive calls. It fails when any file does not
+// compile, with the exit code of the last failure.
+func check(args []string, out io.Writer) int {
+	set, options := newFlagSet("check", out)
+	if !parseFlags(set, options, args, out) || set.NArg() < 1 {
+		return usage(out)
+	}
+
+	status := 0
+	for _, path := range set.Args() {
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", path, err)
+			status = exitNoInput
+			continue
+		}
+
+		program, _, err := compileSource(source)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), err))
+			status = exitCompileError
+			continue
+		}
+
+		for _, hint := range compiler.TailCalls(program) {
+			fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), hint))
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/vm"
+)
+
+const (
+	vmEngine   = "vm"
+	evalEngine = "eval"
+)
+
+// Exit codes for failures besides 1, which is for anything else. They follow
+// sysexits.h so they stay apart from the small codes scripts usually pick
+// with exit or by returning them from main.
+const (
+	exitUsage        = 2
+	exitCompileError = 65
+	exitNoInput      = 66
+	exitRuntimeError = 70
+)
+
+const usageText = `usage: spike [flags] <command> [flags] [arguments]
+
+commands:
+  repl                    start an interactive session, the default
+  run <file> [args...]    run a script or a built program, - reads it from stdin
+  check <files...>        compile scripts without running them
+  build [-o out] <file>   compile a script to a bytecode file
+  fmt [-w] <files...>     format scripts
+  disasm <file>           print the bytecode of a script or a built program
+  ast <file> [--json]     print the syntax tree of a script
+  debug <file> [args...]  run a script on the vm, stopping at breakpoints
+  doc <files...>          print the documentation of scripts as markdown
+
+flags shared by all commands, before or after the command:
+  --engine=vm|eval        engine running scripts in run, vm by default
+  --max-steps=N           stop run and repl inputs after N steps, 0 for no limit
+  --overflow=MODE         wrap, saturate or error on integer overflow in run
+  --no-color              keep the repl output plain
+  --profile=FILE          write samples of where run spends its time to FILE
+  --trace=FILE            write the calls run makes to FILE as a chrome trace
+
+exit codes:
+  65                      the script does not parse or compile
+  66                      the script can not be read
+  70                      the script failed while running
+  exit(n) and main        whatever the script chose, from 0 to 255, best
+                          not one of the codes above
+`
+
+// Main runs the command named by the first argument after the shared flags,
+// reading input from in, writing what it prints to out and the errors of
+// scripts to stderr, and returns the process exit code. Flags before the
+// command are passed on to it. Without a command, or with flags only, it
+// starts the REPL.
+func Main(args []string, in io.Reader, out io.Writer, stderr io.Writer) int {
+	set, _ := newFlagSet("spike", ioutil.Discard)
+	err := set.Parse(args)
+	if err == flag.ErrHelp {
+		fmt.Fprint(out, usageText)
+		return 0
+	}
+	if err != nil {
+		// The REPL parses them again to report what is wrong.
+		return startREPL(args, in, out, stderr)
+	}
+
+	flags := args[:len(args)-set.NArg()]
+	if set.NArg() == 0 {
+		return startREPL(flags, in, out, stderr)
+	}
+	commandArgs := append(append([]string{}, flags...), set.Args()[1:]...)
+
+	switch set.Arg(0) {
+	case "help":
+		fmt.Fprint(out, usageText)
+		return 0
+	case "repl":
+		return startREPL(commandArgs, in, out, stderr)
+	case "run":
+		return run(commandArgs, in, out, stderr)
+	case "check":
+		return check(commandArgs, out)
+	case "build":
+		return build(commandArgs, out)
+	case "fmt":
+		return formatFiles(commandArgs, out)
+	case "disasm":
+		return disasm(commandArgs, out)
+	case "ast":
+		return printAST(commandArgs, out)
+	case "debug":
+		return debug(commandArgs, in, out, stderr)
+	case "doc":
+		return document(commandArgs, out)
+	default:
+		return usage(out)
+	}
+}
+
+func usage(out io.Writer) int {
+	fmt.Fprint(out, usageText)
+	return exitUsage
+}
+
+// options holds the flags every command accepts.
+type options struct {
+	engine   string
+	noColor  bool
+	maxSteps int
+	overflow string
+	profile  string
+	trace    string
+	// vmOptions are added to the options of VMs running scripts.
+	vmOptions []vm.Option
+}
+
+func newFlagSet(name string, out io.Writer) (*flag.FlagSet, *options) {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	set.SetOutput(out)
+	set.Usage = func() {}
+
+	options := &options{}
+	set.StringVar(&options.engine, "engine", vmEngine, "")
+	set.BoolVar(&options.noColor, "no-color", false, "")
+	set.IntVar(&options.maxSteps, "max-steps", 0, "")
+	set.StringVar(&options.overflow, "overflow", "wrap", "")
+	set.StringVar(&options.profile, "profile", "", "")
+	set.StringVar(&options.trace, "trace", "", "")
+
+	return set, options
+}
+
+// parseFlags parses args and tells whether the command can go on, reporting
+// what is wrong otherwise.
+func parseFlags(set *flag.FlagSet, options *options, args []string, out io.Writer) bool {
+	if set.Parse(args) != nil {
+		return false
+	}
+
+	if options.engine != vmEngine && options.engine != evalEngine {
+		fmt.Fprintf(out, "unknown engine %q, expected vm or eval\n", options.engine)
+		return false
+	}
+
+	if options.maxSteps < 0 {
+		fmt.Fprintf(out, "--max-steps must not be negative, got %d\n", options.maxSteps)
+		return false
+	}
+
+	overflow, ok := overflows[options.overflow]
+	if !ok {
+		fmt.Fprintf(out, "unknown overflow %q, expected wrap, saturate or error\n", options.overflow)
+		return false
+	}
+	if overflow != vm.OverflowWrap && options.engine != vmEngine {
+		fmt.Fprintln(out, "--overflow only works with the vm engine")
+		return false
+	}
+
+	return true
+}
+
+// overflows maps the values of --overflow to what the VM does on overflow.
+var overflows = map[string]vm.Overflow{
+	"wrap":     vm.OverflowWrap,
+	"saturate": vm.OverflowSaturate,
+	"error":    vm.OverflowError,
+}
+
+// parseInterleaved parses args like parseFlags but also accepts flags after
+// the arguments, as in `ast file.spike --json`. It returns the arguments.
+func parseInterleaved(set *flag.FlagSet, options *options, args []string, out io.Writer) ([]string, bool) {
+	arguments := []string{}
+	for {
+		if !parseFlags(set, options, args, out) {
+			return nil, false
+		}
+		if set.NArg() == 0 {
+			return arguments, true
+		}
+
+		arguments = append(arguments, set.Arg(0))
+		args = set.Args()[1:]
+	}
+}
+
+// load reads a script, or a program written by build, and compiles it.
+func load(path string) (*compiler.Bytecode, *compiler.SymbolTable, []byte, error) {
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if compiler.IsFile(source) {
+		bytecode, symbolTable, err := compiler.ReadFile(bytes.NewReader(source))
+		return bytecode, symbolTable, nil, err
+	}
+
+	_, c, err := compileSource(source)
+	if err != nil {
+		return nil, nil, source, err
+	}
+
+	return c.Bytecode(), c.SymbolTable(), source, nil
+}
+
+func compileSource(source []byte) (*ast.Program, *compiler.Compiler, error) {
+	program, err := parser.New(lexer.New(bytes.NewReader(source))).ParseProgram()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := compiler.New()
+	err = c.Compile(program)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return program, c, nil
+}
+
+// isTerminal tells whether in is an interactive terminal rather than a pipe
+// or a regular file.
+func isTerminal(in io.Reader) bool {
+	file, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,393 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closures matches how closures print, which differs between runs.
+var closures = regexp.MustCompile(`Closure\[0x[0-9a-f]+\]`)
+
+const mainScript = "let double = fn(x) { x * 2 };\nlet main = fn(args) {\n  println(double(len(args)));\n  3\n};\n"
+
+func Test_Main_run(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", mainScript)
+	expression := writeFile(t, dir, "expression.spike", "let xs = [1, 2];\nlen(xs) + 1")
+	failing := writeFile(t, dir, "failing.spike", "let x = 1;\nlen(x)")
+	arguments := writeFile(t, dir, "arguments.spike", "println(args())")
+
+	testCases := []struct {
+		name           string
+		args           []string
+		input          string
+		expectedCode   int
+		expectedOutput string
+		expectedErrors string
+	}{
+		{
+			name:           "main",
+			args:           []string{"run", path, "a", "b"},
+			expectedCode:   3,
+			expectedOutput: "4\n",
+		},
+		{
+			name:           "main with eval",
+			args:           []string{"run", "--engine=eval", path, "a"},
+			expectedCode:   3,
+			expectedOutput: "2\n",
+		},
+		{
+			name:           "flags before the command",
+			args:           []string{"--engine", "eval", "run", path, "a"},
+			expectedCode:   3,
+			expectedOutput: "2\n",
+		},
+		{
+			name: "result is not printed",
+			args: []string{"run", expression},
+		},
+		{
+			name: "result is not printed with eval",
+			args: []string{"run", "--engine=eval", expression},
+		},
+		{
+			name:           "args",
+			args:           []string{"run", arguments, "a", "--b"},
+			expectedOutput: "[\"a\", \"--b\"]\n",
+		},
+		{
+			name:           "args with eval",
+			args:           []string{"run", "--engine=eval", arguments, "a"},
+			expectedOutput: "[\"a\"]\n",
+		},
+		{
+			name:           "stdin",
+			args:           []string{"run", "-", "a"},
+			input:          "println(args()); 1 + 2",
+			expectedOutput: "[\"a\"]\n",
+		},
+		{
+			name:         "stdin with eval",
+			args:         []string{"run", "--engine=eval", "-"},
+			input:        "let main = fn() { 4 };",
+			expectedCode: 4,
+		},
+		{
+			name:           "runtime error",
+			args:           []string{"run", failing},
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: len: argument of type integer is not supported at 2:1\nlen(x)\n^\n",
+		},
+		{
+			name:           "step limit",
+			args:           []string{"run", "--max-steps=3", expression},
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: step limit of 3 exceeded at 2:1\nlen(xs) + 1\n^\n",
+		},
+		{
+			name:           "step limit with eval",
+			args:           []string{"run", "--engine=eval", "--max-steps=3", expression},
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: step limit of 3 exceeded at 1:10\nlet xs = [1, 2];\n         ^\n",
+		},
+		{
+			name:         "exit",
+			args:         []string{"run", "-"},
+			input:        "exit(5); 1",
+			expectedCode: 5,
+		},
+		{
+			name:           "exit code out of range",
+			args:           []string{"run", "-"},
+			input:          "exit(300)",
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: exit: code must be between 0 and 255, got 300 at 1:1\nexit(300)\n^\n",
+		},
+		{
+			name:           "main result out of range",
+			args:           []string{"run", "--engine=eval", "-"},
+			input:          "let main = fn() { -1 };",
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: main: exit code must be between 0 and 255, got -1\n",
+		},
+		{
+			name:           "parse error",
+			args:           []string{"run", "-"},
+			input:          "let = 1",
+			expectedCode:   exitCompileError,
+			expectedErrors: "Parser error: expected identifier, got assign at 1:5\nlet = 1\n    ^\n",
+		},
+		{
+			name:           "compile error",
+			args:           []string{"run", "-"},
+			input:          "x",
+			expectedCode:   exitCompileError,
+			expectedErrors: "Compile error: unable to resolve identifier: x at 1:1\nx\n^\n",
+		},
+		{
+			name:           "missing file",
+			args:           []string{"run", filepath.Join(dir, "missing.spike")},
+			expectedCode:   exitNoInput,
+			expectedErrors: "Read error: open " + filepath.Join(dir, "missing.spike") + ": no such file or directory\n",
+		},
+		{
+			name:           "overflow error",
+			args:           []string{"run", "--overflow=error", "-"},
+			input:          "9223372036854775807 + 1",
+			expectedCode:   exitRuntimeError,
+			expectedErrors: "Runtime error: integer overflow: 9223372036854775807 + 1 at 1:21\n9223372036854775807 + 1\n                    ^\n",
+		},
+		{
+			name:           "overflow saturate",
+			args:           []string{"run", "--overflow=saturate", "-"},
+			input:          "println(9223372036854775807 + 1)",
+			expectedOutput: "9223372036854775807\n",
+		},
+		{
+			name:           "overflow with eval",
+			args:           []string{"run", "--engine=eval", "--overflow=error", expression},
+			expectedCode:   exitUsage,
+			expectedOutput: "--overflow only works with the vm engine\n" + usageText,
+		},
+		{
+			name:           "unknown overflow",
+			args:           []string{"run", "--overflow=clamp", expression},
+			expectedCode:   exitUsage,
+			expectedOutput: "unknown overflow \"clamp\", expected wrap, saturate or error\n" + usageText,
+		},
+		{
+			name:           "unknown engine",
+			args:           []string{"run", "--engine=jit", expression},
+			expectedCode:   exitUsage,
+			expectedOutput: "unknown engine \"jit\", expected vm or eval\n" + usageText,
+		},
+		{
+			name:           "repl with eval",
+			args:           []string{"--engine=eval", "repl"},
+			expectedCode:   exitUsage,
+			expectedOutput: "the repl only runs on the vm engine\n",
+		},
+		{
+			name:           "unknown command",
+			args:           []string{"compile", expression},
+			expectedCode:   exitUsage,
+			expectedOutput: usageText,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			output := &strings.Builder{}
+			stderr := &strings.Builder{}
+
+			code := Main(testCase.args, strings.NewReader(testCase.input), output, stderr)
+
+			assert.Equal(t, testCase.expectedCode, code)
+			assert.Equal(t, testCase.expectedOutput, output.String())
+			assert.Equal(t, testCase.expectedErrors, stderr.String())
+		})
+	}
+}
+
+func Test_Main_build(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", mainScript)
+	output := &strings.Builder{}
+
+	assert.Equal(t, 0, Main([]string{"build", path}, strings.NewReader(""), output, output))
+	assert.Equal(t, 3, Main([]string{"run", filepath.Join(dir, "script.spikec"), "a", "b", "c"}, strings.NewReader(""), output, output))
+	assert.Equal(t, "6\n", output.String())
+
+	output.Reset()
+	assert.Equal(t, 0, Main([]string{"disasm", filepath.Join(dir, "script.spikec")}, strings.NewReader(""), output, output))
+	assert.True(t, strings.HasPrefix(output.String(), "== main ==\n0000 OpClosure 1 0\n"))
+	assert.Contains(t, output.String(), "\n== constant 1: fn double, 1 parameters, 1 locals ==\n0000 OpGetLocal 0\n")
+}
+
+func Test_Main_check(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	valid := writeFile(t, dir, "valid.spike", mainScript)
+	invalid := writeFile(t, dir, "invalid.spike", "let x = y;")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 0, Main([]string{"check", valid}, strings.NewReader(""), output, output))
+	assert.Equal(t, exitCompileError, Main([]string{"check", valid, invalid}, strings.NewReader(""), output, output))
+	assert.Equal(t, invalid+": unable to resolve identifier: y at 1:9\nlet x = y;\n        ^\n", output.String())
+
+	recursive := writeFile(t, dir, "recursive.spike", "let f = fn(n) { 1 + f(n) };")
+	output.Reset()
+	assert.Equal(t, 0, Main([]string{"check", recursive}, strings.NewReader(""), output, output))
+	assert.Equal(t, recursive+": warning: recursive call of f is not in tail position at 1:21\nlet f = fn(n) { 1 + f(n) };\n                    ^~~~\n", output.String())
+}
+
+func Test_Main_fmt(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", "let xs=[1,2]\nlen(xs)+  1")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 0, Main([]string{"fmt", path}, strings.NewReader(""), output, output))
+	assert.Equal(t, "let xs = [1, 2];\nlen(xs) + 1;\n", output.String())
+
+	output.Reset()
+	assert.Equal(t, 0, Main([]string{"fmt", "-w", path}, strings.NewReader(""), output, output))
+	assert.Empty(t, output.String())
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "let xs = [1, 2];\nlen(xs) + 1;\n", string(contents))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "spike")
+	assert.NoError(t, err)
+
+	return dir
+}
+
+func writeFile(t *testing.T, dir string, name string, contents string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+
+	return path
+}
+
+func Test_Main_ast(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", "x + 1")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 0, Main([]string{"ast", path}, strings.NewReader(""), output, output))
+	assert.Equal(t, `Program 1:1
+  statements[0]: ExpressionStatement 1:1
+    expression: InfixExpression 1:1 operator="+"
+      left: Identifier 1:1 value="x"
+      right: Integer 1:5 value=1
+`, output.String())
+
+	output.Reset()
+	assert.Equal(t, 0, Main([]string{"ast", path, "--json"}, strings.NewReader(""), output, output))
+	assert.True(t, strings.HasPrefix(output.String(), "{\n  \"end\": {"))
+	assert.Contains(t, output.String(), "\"operator\": \"+\"")
+}
+
+func Test_Main_debug(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", "let add = fn(a, b) {\n  let sum = a + b;\n  sum\n};\nlet x = add(1, 2);\nprintln(x);\n")
+
+	testCases := []struct {
+		name           string
+		input          string
+		expectedCode   int
+		expectedOutput string
+	}{
+		{
+			name:  "breakpoint",
+			input: "b 2\nc\nl\ng\nc\n",
+			expectedOutput: "type help for the debugger commands\nstopped at line 1\n   1  let add = fn(a, b) {\n" +
+				"(debug) breakpoint at line 2\n(debug) stopped at line 2\n   2    let sum = a + b;\n" +
+				"(debug)   a = 1\n  b = 2\n(debug)   add = CLOSURE\n(debug) 3\n",
+		},
+		{
+			name:  "next steps over calls",
+			input: "n\nn\nq\n",
+			expectedOutput: "type help for the debugger commands\nstopped at line 1\n   1  let add = fn(a, b) {\n" +
+				"(debug) stopped at line 5\n   5  let x = add(1, 2);\n(debug) stopped at line 6\n   6  println(x);\n(debug) ",
+		},
+		{
+			name:  "step into calls",
+			input: "s\ns\nw\nd 9\nq\n",
+			expectedOutput: "type help for the debugger commands\nstopped at line 1\n   1  let add = fn(a, b) {\n" +
+				"(debug) stopped at line 5\n   5  let x = add(1, 2);\n(debug) stopped at line 2\n   2    let sum = a + b;\n" +
+				"(debug)   add\n  <main>\n(debug) no line 9 in the script\n(debug) ",
+		},
+		{
+			name:         "end of input stops",
+			input:        "",
+			expectedCode: 1,
+			expectedOutput: "type help for the debugger commands\nstopped at line 1\n   1  let add = fn(a, b) {\n" +
+				"(debug) \nend of input, stopping the script\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			output := &strings.Builder{}
+
+			code := Main([]string{"debug", path}, strings.NewReader(testCase.input), output, output)
+
+			assert.Equal(t, testCase.expectedCode, code)
+			assert.Equal(t, testCase.expectedOutput, closures.ReplaceAllString(output.String(), "CLOSURE"))
+		})
+	}
+}
+
+func Test_Main_runProfile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", mainScript)
+	profile := filepath.Join(dir, "profile.folded")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 3, Main([]string{"run", "--profile=" + profile, path, "a"}, strings.NewReader(""), output, output))
+	assert.Equal(t, "2\n", output.String())
+	assert.FileExists(t, profile)
+
+	output.Reset()
+	assert.Equal(t, exitUsage, Main([]string{"run", "--engine=eval", "--profile=" + profile, path}, strings.NewReader(""), output, output))
+	assert.Equal(t, "--profile and --trace only work with the vm engine\n", output.String())
+}
+
+func Test_Main_runTrace(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", mainScript)
+	trace := filepath.Join(dir, "trace.json")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 3, Main([]string{"run", "--trace=" + trace, path, "a"}, strings.NewReader(""), output, output))
+	assert.Equal(t, "2\n", output.String())
+
+	contents, err := ioutil.ReadFile(trace)
+	require.NoError(t, err)
+	var decoded struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(contents, &decoded))
+	calls := []interface{}{}
+	for _, event := range decoded.TraceEvents {
+		if event["ph"] == "B" {
+			calls = append(calls, event["name"])
+		}
+	}
+	assert.Equal(t, []interface{}{"<main>", "main", "double"}, calls)
+}
+
+func Test_Main_doc(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "script.spike", "// Doubles x.\nlet double = fn(x) { x * 2 };\n")
+	invalid := writeFile(t, dir, "invalid.spike", "let = 1;")
+	output := &strings.Builder{}
+
+	assert.Equal(t, 0, Main([]string{"doc", path}, strings.NewReader(""), output, output))
+	assert.Equal(t, "# "+path+"\n\n## double\n\n```spike\nfn double(x)\n```\n\nDoubles x.\n", output.String())
+
+	output.Reset()
+	assert.Equal(t, exitCompileError, Main([]string{"doc", invalid}, strings.NewReader(""), output, output))
+	assert.Equal(t, invalid+": expected identifier, got assign at 1:5\nlet = 1;\n    ^\n", output.String())
+}
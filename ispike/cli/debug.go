@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/vm"
+	"strconv"
+	"strings"
+)
+
+const debugHelp = `commands:
+  break <line>, b     stop when the script reaches line
+  delete <line>, d    remove the breakpoint at line
+  step, s             run to the next line, stepping into calls
+  next, n             run to the next line, stepping over calls
+  continue, c         run to the next breakpoint
+  locals, l           print the variables of the current function
+  globals, g          print the global variables
+  where, w            print the calls the script is in
+  quit, q             stop the script
+`
+
+// debug runs a script on the VM, stopping at its first line and then as the
+// commands read from in ask. The script reads its own input from in too and
+// reports errors to stderr like run does.
+func debug(args []string, in io.Reader, out io.Writer, stderr io.Writer) int {
+	set, options := newFlagSet("debug", out)
+	if !parseFlags(set, options, args, out) || set.NArg() < 1 {
+		return usage(out)
+	}
+	path := set.Arg(0)
+
+	source, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "Read error: %s\n", err)
+		return exitNoInput
+	}
+
+	_, compilerInstance, err := compileSource(source)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", path, diagnostic.Render(string(source), err))
+		return exitCompileError
+	}
+
+	reader := bufio.NewReader(in)
+	debugger := &debugger{
+		lines:       strings.Split(string(source), "\n"),
+		in:          reader,
+		out:         out,
+		symbolTable: compilerInstance.SymbolTable(),
+		breakpoints: map[int]bool{},
+		stopDepth:   math.MaxInt32,
+	}
+	fmt.Fprint(out, "type help for the debugger commands\n")
+
+	scriptArgs := set.Args()[1:]
+	options.vmOptions = append(options.vmOptions, vm.WithDebugger(debugger))
+	return runCompiled(source, compilerInstance, scriptArgs, stderr, machineOptions(options, scriptArgs, reader, out, stderr)...)
+}
+
+// debugger is a vm.Debugger prompting for commands whenever the script stops.
+type debugger struct {
+	lines       []string
+	in          *bufio.Reader
+	out         io.Writer
+	symbolTable *compiler.SymbolTable
+	breakpoints map[int]bool
+	// stopDepth makes the script stop at the next line run at most that many
+	// calls deep, -1 only stops at breakpoints.
+	stopDepth int
+}
+
+func (debugger *debugger) Line(pause *vm.Pause) error {
+	line := pause.Position.Line
+	if pause.Depth > debugger.stopDepth && !debugger.breakpoints[line] {
+		return nil
+	}
+
+	fmt.Fprintf(debugger.out, "stopped at line %d\n", line)
+	debugger.list(line)
+
+	for {
+		fmt.Fprint(debugger.out, "(debug) ")
+		input, err := debugger.in.ReadString('\n')
+		if err != nil && input == "" {
+			// Running out of commands is not quitting, the script did not
+			// get to finish.
+			fmt.Fprint(debugger.out, "\nend of input, stopping the script\n")
+			return &object.ExitError{Code: 1}
+		}
+
+		fields := strings.Fields(input)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "break", "b":
+			if line, ok := debugger.lineArgument(fields); ok {
+				debugger.breakpoints[line] = true
+				fmt.Fprintf(debugger.out, "breakpoint at line %d\n", line)
+			}
+		case "delete", "d":
+			if line, ok := debugger.lineArgument(fields); ok {
+				delete(debugger.breakpoints, line)
+			}
+		case "step", "s":
+			debugger.stopDepth = math.MaxInt32
+			return nil
+		case "next", "n":
+			debugger.stopDepth = pause.Depth
+			return nil
+		case "continue", "c":
+			debugger.stopDepth = -1
+			return nil
+		case "locals", "l":
+			debugger.print(pause.Locals())
+		case "globals", "g":
+			debugger.print(debugger.globals(pause))
+		case "where", "w":
+			for _, name := range pause.StackTrace() {
+				fmt.Fprintf(debugger.out, "  %s\n", name)
+			}
+		case "quit", "q":
+			return &object.ExitError{Code: 0}
+		case "help", "h":
+			fmt.Fprint(debugger.out, debugHelp)
+		default:
+			fmt.Fprintf(debugger.out, "unknown command %q, type help for the commands\n", fields[0])
+		}
+	}
+}
+
+// list prints the source line the script stopped at.
+func (debugger *debugger) list(line int) {
+	if line < 1 || line > len(debugger.lines) {
+		return
+	}
+
+	fmt.Fprintf(debugger.out, "%4d  %s\n", line, strings.TrimRight(debugger.lines[line-1], "\r"))
+}
+
+func (debugger *debugger) lineArgument(fields []string) (int, bool) {
+	if len(fields) != 2 {
+		fmt.Fprintf(debugger.out, "%s expects a line number\n", fields[0])
+		return 0, false
+	}
+
+	line, err := strconv.Atoi(fields[1])
+	if err != nil || line < 1 || line > len(debugger.lines) {
+		fmt.Fprintf(debugger.out, "no line %s in the script\n", fields[1])
+		return 0, false
+	}
+
+	return line, true
+}
+
+// globals returns the globals assigned so far, sorted by name.
+func (debugger *debugger) globals(pause *vm.Pause) []vm.Variable {
+	globals := []vm.Variable{}
+	for _, symbol := range debugger.symbolTable.Symbols() {
+		if symbol.SymbolScope != compiler.GlobalScope {
+			continue
+		}
+		if value := pause.Global(symbol.Index); value != nil {
+			globals = append(globals, vm.Variable{Name: symbol.Name, Value: value})
+		}
+	}
+
+	return globals
+}
+
+func (debugger *debugger) print(variables []vm.Variable) {
+	if len(variables) == 0 {
+		fmt.Fprintln(debugger.out, "  none")
+	}
+	for _, variable := range variables {
+		fmt.Fprintf(debugger.out, "  %s = %s\n", variable.Name, variable.Value.Inspect())
+	}
+}
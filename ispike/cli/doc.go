@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/doc"
+)
+
+// document prints the documentation of every file as Markdown.
+func document(args []string, out io.Writer) int {
+	set, options := newFlagSet("doc", out)
+	if !parseFlags(set, options, args, out) || set.NArg() < 1 {
+		return usage(out)
+	}
+
+	status := 0
+	for i, path := range set.Args() {
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", path, err)
+			status = exitNoInput
+			continue
+		}
+
+		entries, err := doc.Source(string(source))
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), err))
+			status = exitCompileError
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprint(out, doc.Markdown(path, entries))
+	}
+
+	return status
+}
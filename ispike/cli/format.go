@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/format"
+)
+
+// formatFiles prints every file formatted, or rewrites them in place with
+// -w.
+func formatFiles(args []string, out io.Writer) int {
+	set, options := newFlagSet("fmt", out)
+	write := set.Bool("w", false, "")
+	if !parseFlags(set, options, args, out) || set.NArg() < 1 {
+		return usage(out)
+	}
+
+	status := 0
+	for _, path := range set.Args() {
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			status = 1
+			continue
+		}
+
+		formatted, err := format.Source(string(source))
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", path, diagnostic.Render(string(source), err))
+			status = 1
+			continue
+		}
+
+		if !*write {
+			fmt.Fprint(out, formatted)
+			continue
+		}
+
+		if formatted != string(source) {
+			err = ioutil.WriteFile(path, []byte(formatted), 0644)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				status = 1
+			}
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"spike-interpreter-go/ispike/repl"
+)
+
+const historyFile = ".spike_history"
+
+// startREPL reads from the terminal with line editing and history, or plainly
+// when the input is piped.
+func startREPL(args []string, in io.Reader, out io.Writer, stderr io.Writer) int {
+	set, options := newFlagSet("repl", out)
+	if !parseFlags(set, options, args, out) || set.NArg() > 0 {
+		return usage(out)
+	}
+
+	if options.engine != vmEngine {
+		fmt.Fprintln(out, "the repl only runs on the vm engine")
+		return 2
+	}
+
+	replOptions := []repl.Option{repl.WithMaxSteps(options.maxSteps), repl.WithStderr(stderr)}
+	if options.noColor {
+		replOptions = append(replOptions, repl.WithoutColors())
+	}
+
+	if !isTerminal(in) {
+		repl.Start(in, out, replOptions...)
+		return 0
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	err = repl.StartTerminal(filepath.Join(home, historyFile), replOptions...)
+	if err != nil {
+		fmt.Fprintln(out, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/eval"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/vm"
+
+	"github.com/pkg/errors"
+)
+
+// stdinPath makes run read the script from its input.
+const stdinPath = "-"
+
+// run executes a script with the chosen engine, or a program written by
+// build on the VM. Scripts read from in can not read input themselves. Only
+// what the script prints goes to out, why it could not run goes to stderr.
+func run(args []string, in io.Reader, out io.Writer, stderr io.Writer) int {
+	set, options := newFlagSet("run", out)
+	if !parseFlags(set, options, args, out) || set.NArg() < 1 {
+		return usage(out)
+	}
+
+	if options.profile == "" && options.trace == "" {
+		return runFile(set.Arg(0), set.Args()[1:], options, in, out, stderr)
+	}
+
+	if options.engine != vmEngine {
+		fmt.Fprintln(out, "--profile and --trace only work with the vm engine")
+		return exitUsage
+	}
+
+	profile := vm.NewProfile(vm.DefaultProfileInterval)
+	if options.profile != "" {
+		options.vmOptions = append(options.vmOptions, vm.WithProfile(profile))
+	}
+	trace := vm.NewTrace()
+	if options.trace != "" {
+		options.vmOptions = append(options.vmOptions, vm.WithTrace(trace))
+	}
+
+	code := runFile(set.Arg(0), set.Args()[1:], options, in, out, stderr)
+
+	if options.profile != "" {
+		err := writeTo(options.profile, profile.WriteFolded)
+		if err != nil {
+			fmt.Fprintf(stderr, "Profile error: %s\n", err)
+			return 1
+		}
+	}
+
+	if options.trace != "" {
+		err := writeTo(options.trace, trace.WriteJSON)
+		if err != nil {
+			fmt.Fprintf(stderr, "Trace error: %s\n", err)
+			return 1
+		}
+	}
+
+	return code
+}
+
+// writeTo creates the file at path and writes it with write.
+func writeTo(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// runFile runs the script or built program at path with args.
+func runFile(path string, args []string, options *options, in io.Reader, out io.Writer, stderr io.Writer) int {
+	var source []byte
+	var err error
+	if path == stdinPath {
+		source, err = ioutil.ReadAll(in)
+	} else {
+		source, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Read error: %s\n", err)
+		return exitNoInput
+	}
+
+	if compiler.IsFile(source) {
+		if options.engine != vmEngine {
+			fmt.Fprintf(stderr, "%s: built programs only run on the vm engine\n", path)
+			return exitUsage
+		}
+
+		return runBytecode(source, args, options, in, out, stderr)
+	}
+
+	program, err := parser.New(lexer.New(bytes.NewReader(source))).ParseProgram()
+	if err != nil {
+		fmt.Fprintf(stderr, "Parser error: %s\n", render(source, err))
+		return exitCompileError
+	}
+
+	if options.engine == evalEngine {
+		return runEval(source, program, args, options, in, out, stderr)
+	}
+
+	return runVM(source, program, args, options, in, out, stderr)
+}
+
+func runVM(source []byte, program *ast.Program, args []string, options *options, in io.Reader, out io.Writer, stderr io.Writer) int {
+	compilerInstance := compiler.New()
+	err := compilerInstance.Compile(program)
+	if err != nil {
+		fmt.Fprintf(stderr, "Compile error: %s\n", render(source, err))
+		return exitCompileError
+	}
+
+	return runCompiled(source, compilerInstance, args, stderr, machineOptions(options, args, in, out, stderr)...)
+}
+
+// runCompiled runs a compiled script on a VM made with vmOptions, then its
+// main function if it has one. The value the script ends with is not
+// printed, scripts print what they want to show.
+func runCompiled(source []byte, compilerInstance *compiler.Compiler, args []string, stderr io.Writer, vmOptions ...vm.Option) int {
+	machine := vm.New(compilerInstance.Bytecode(), vmOptions...)
+	err := machine.Run()
+	if err != nil {
+		return runtimeError(source, machine, err, stderr)
+	}
+
+	mainResult, hasMain, err := machine.CallMain(compilerInstance.SymbolTable(), args)
+	if err != nil {
+		return runtimeError(source, machine, err, stderr)
+	}
+
+	if hasMain {
+		return exitCode(mainResult, stderr)
+	}
+
+	return 0
+}
+
+// runBytecode runs a program written by build. Its source is gone, so
+// errors are reported by position only.
+func runBytecode(contents []byte, args []string, options *options, in io.Reader, out io.Writer, stderr io.Writer) int {
+	bytecode, symbolTable, err := compiler.ReadFile(bytes.NewReader(contents))
+	if err != nil {
+		fmt.Fprintf(stderr, "Load error: %s\n", err)
+		return exitCompileError
+	}
+
+	machine := vm.New(bytecode, machineOptions(options, args, in, out, stderr)...)
+	err = machine.Run()
+	if err != nil {
+		return runtimeError(nil, machine, err, stderr)
+	}
+
+	mainResult, hasMain, err := machine.CallMain(symbolTable, args)
+	if err != nil {
+		return runtimeError(nil, machine, err, stderr)
+	}
+
+	if hasMain {
+		return exitCode(mainResult, stderr)
+	}
+
+	return 0
+}
+
+// machineOptions configures a VM running a script with args as the flags in
+// options ask.
+func machineOptions(options *options, args []string, in io.Reader, out io.Writer, stderr io.Writer) []vm.Option {
+	return append([]vm.Option{
+		vm.WithStdin(in),
+		vm.WithStdout(out),
+		vm.WithStderr(stderr),
+		vm.WithMaxSteps(options.maxSteps),
+		vm.WithOverflow(overflows[options.overflow]),
+		vm.WithArgs(args),
+	}, options.vmOptions...)
+}
+
+func runEval(source []byte, program *ast.Program, args []string, options *options, in io.Reader, out io.Writer, stderr io.Writer) int {
+	evaluator := eval.New(eval.WithStdin(in), eval.WithStdout(out), eval.WithStderr(stderr), eval.WithMaxSteps(options.maxSteps), eval.WithArgs(args))
+	environment := object.NewEnvironment()
+
+	_, err := evaluator.Eval(program, environment)
+	if err != nil {
+		return evalError(source, evaluator.RuntimeError(err), stderr)
+	}
+
+	mainResult, hasMain, err := evaluator.CallMain(environment, args)
+	if err != nil {
+		return evalError(source, evaluator.RuntimeError(err), stderr)
+	}
+
+	if hasMain {
+		return exitCode(mainResult, stderr)
+	}
+
+	return 0
+}
+
+// exitCode is the exit code for what main returned, reporting codes out of
+// range like runtime errors.
+func exitCode(mainResult object.Object, stderr io.Writer) int {
+	code, err := eval.ExitCode(mainResult)
+	if err != nil {
+		return evalError(nil, err, stderr)
+	}
+
+	return code
+}
+
+// runtimeError reports err at the position machine failed at and returns the
+// exit code for it. A script calling exit is not an error and ends with the
+// requested code.
+func runtimeError(source []byte, machine *vm.VM, err error, stderr io.Writer) int {
+	return evalError(source, machine.RuntimeError(err), stderr)
+}
+
+// evalError reports err, which a run on either engine failed with, exiting
+// with the code of an *object.ExitError.
+func evalError(source []byte, err error, stderr io.Writer) int {
+	if exit, ok := errors.Cause(err).(*object.ExitError); ok {
+		return exit.Code
+	}
+
+	fmt.Fprintf(stderr, "Runtime error: %s\n", render(source, err))
+	return exitRuntimeError
+}
+
+// render formats err with diagnostic.Render, or on its own for programs
+// loaded without their source.
+func render(source []byte, err error) string {
+	if source == nil {
+		return err.Error()
+	}
+
+	return diagnostic.Render(string(source), err)
+}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"os"
-	"spike-interpreter-go/ispike/repl"
+	"spike-interpreter-go/ispike/cli"
 )
 
 func main() {
-	repl.Start(os.Stdin, os.Stdout)
+	os.Exit(cli.Main(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
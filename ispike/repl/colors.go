@@ -0,0 +1,67 @@
+package repl
+
+import (
+	"io"
+	"os"
+	"spike-interpreter-go/spike/object"
+)
+
+const (
+	reset  = "\x1b[0m"
+	bold   = "\x1b[1m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	cyan   = "\x1b[36m"
+	gray   = "\x1b[90m"
+)
+
+// colors paints output with ANSI escape codes. The zero value leaves output
+// plain.
+type colors struct {
+	enabled bool
+}
+
+// colorsFor enables colors when out is a terminal, unless NO_COLOR is set or
+// the terminal is dumb.
+func colorsFor(out io.Writer) colors {
+	file, ok := out.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return colors{}
+	}
+
+	info, err := file.Stat()
+	return colors{enabled: err == nil && info.Mode()&os.ModeCharDevice != 0}
+}
+
+func (colors colors) paint(color string, text string) string {
+	if !colors.enabled || text == "" {
+		return text
+	}
+
+	return color + text + reset
+}
+
+func (colors colors) prompt(text string) string {
+	return colors.paint(bold, text)
+}
+
+func (colors colors) error(text string) string {
+	return colors.paint(red, text)
+}
+
+// value inspects value, colored by its type.
+func (colors colors) value(value object.Object) string {
+	switch value.(type) {
+	case *object.String:
+		return colors.paint(green, value.Inspect())
+	case *object.Integer, *object.BigInt:
+		return colors.paint(cyan, value.Inspect())
+	case *object.Boolean:
+		return colors.paint(yellow, value.Inspect())
+	case *object.Null:
+		return colors.paint(gray, value.Inspect())
+	default:
+		return value.Inspect()
+	}
+}
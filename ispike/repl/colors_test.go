@@ -0,0 +1,33 @@
+package repl
+
+import (
+	"spike-interpreter-go/spike/object"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_colors_value(t *testing.T) {
+	testCases := []struct {
+		value    object.Object
+		expected string
+	}{
+		{value: &object.String{Value: "a"}, expected: "\x1b[32m\"a\"\x1b[0m"},
+		{value: &object.Integer{Value: 1}, expected: "\x1b[36m1\x1b[0m"},
+		{value: &object.Boolean{Value: true}, expected: "\x1b[33mtrue\x1b[0m"},
+		{value: &object.NullObject, expected: "\x1b[90mnull\x1b[0m"},
+		{value: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}, expected: "[1]"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value.Inspect(), func(t *testing.T) {
+			assert.Equal(t, testCase.expected, colors{enabled: true}.value(testCase.value))
+			assert.Equal(t, testCase.value.Inspect(), colors{}.value(testCase.value))
+		})
+	}
+}
+
+func Test_colorsFor(t *testing.T) {
+	assert.False(t, colorsFor(&strings.Builder{}).enabled)
+}
@@ -0,0 +1,63 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+// input reads the lines of a session. ReadLine returns io.EOF once the
+// session is over.
+type input interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// scannerInput reads lines without editing, writing prompts to out.
+type scannerInput struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+	colors  colors
+}
+
+func (input *scannerInput) ReadLine(prompt string) (string, error) {
+	_, err := fmt.Fprint(input.out, input.colors.prompt(prompt))
+	if err != nil {
+		return "", err
+	}
+
+	if !input.scanner.Scan() {
+		if err := input.scanner.Err(); err != nil {
+			return "", err
+		}
+
+		return "", io.EOF
+	}
+
+	return input.scanner.Text(), nil
+}
+
+// terminalInput reads lines with line editing and records them in history.
+// Ctrl-C and Ctrl-D both end the session. Prompts stay plain, the editor
+// rejects prompts with escape codes.
+type terminalInput struct {
+	state *liner.State
+}
+
+func (input *terminalInput) ReadLine(prompt string) (string, error) {
+	line, err := input.state.Prompt(prompt)
+	if err == liner.ErrPromptAborted {
+		return "", io.EOF
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(line) != "" {
+		input.state.AppendHistory(line)
+	}
+
+	return line, nil
+}
@@ -2,70 +2,319 @@ package repl
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
 	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
 	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
 	"spike-interpreter-go/spike/vm"
 	"strings"
 )
 
-const prompt = ">> "
+const (
+	prompt             = ">> "
+	continuationPrompt = ".. "
+	helpPrefix         = ":help "
+	lastResult         = "_"
+	loadPrefix         = ":load "
+	timeCommand        = ":time"
+	pasteCommand       = ":paste"
+	pasteEnd           = ":end"
+	savePrefix         = ":save "
+)
+
+// Option configures a REPL session.
+type Option func(config *config)
+
+type config struct {
+	noColor  bool
+	maxSteps int
+	stderr   io.Writer
+}
+
+// WithoutColors keeps the output plain even on terminals.
+func WithoutColors() Option {
+	return func(config *config) {
+		config.noColor = true
+	}
+}
+
+// WithMaxSteps limits how many instructions each input may execute, see
+// vm.WithMaxSteps.
+func WithMaxSteps(steps int) Option {
+	return func(config *config) {
+		config.maxSteps = steps
+	}
+}
+
+// WithStderr sets where scripts print errors to and where the session reports
+// failing to read or write, instead of the process's standard error.
+func WithStderr(stderr io.Writer) Option {
+	return func(config *config) {
+		config.stderr = stderr
+	}
+}
+
+func newConfig(options []Option) *config {
+	config := &config{stderr: os.Stderr}
+	for _, option := range options {
+		option(config)
+	}
+
+	return config
+}
 
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+func (config *config) colorsFor(out io.Writer) colors {
+	if config.noColor {
+		return colors{}
+	}
+
+	return colorsFor(out)
+}
 
-	constants := []object.Object{}
-	globals := make([]object.Object, vm.GlobalsSize)
+// Start runs the REPL on lines read from in, as when input is piped.
+func Start(in io.Reader, out io.Writer, options ...Option) {
+	config := newConfig(options)
+	colors := config.colorsFor(out)
+	err := run(&scannerInput{scanner: bufio.NewScanner(in), out: out, colors: colors}, out, colors, config)
+	if err != nil {
+		fmt.Fprintln(config.stderr, err)
+	}
+}
+
+// run reads and runs inputs until the input ends or a script exits. It fails
+// when reading the input or writing the output does.
+func run(in input, out io.Writer, colors colors, config *config) error {
 	symbolTable := compiler.NewSymbolTable()
+	for i, builtin := range object.Builtins {
+		symbolTable.DefineBuiltin(i, builtin.Name)
+	}
+	session := vm.NewSession(symbolTable)
+	session.Globals[symbolTable.Define(lastResult).Index] = &object.NullObject
 
+	source := ""
+	help := false
+	timer := &timer{}
+	transcript := []string{}
 	for {
-		_, err := fmt.Fprint(out, prompt)
+		currentPrompt := prompt
+		if source != "" {
+			currentPrompt = continuationPrompt
+		}
+
+		line, err := in.ReadLine(currentPrompt)
+		if err == io.EOF {
+			return nil
+		}
 		if err != nil {
-			fmt.Print(err)
-			return
+			return err
+		}
+
+		if fields := strings.Fields(line); source == "" && len(fields) > 0 && fields[0] == timeCommand {
+			switch strings.Join(fields[1:], " ") {
+			case "on":
+				timer.enabled = true
+			case "off":
+				timer.enabled = false
+			default:
+				err = report(out, colors.error("usage: :time on|off"))
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if source == "" && strings.HasPrefix(line, savePrefix) {
+			path := strings.TrimSpace(strings.TrimPrefix(line, savePrefix))
+			err := ioutil.WriteFile(path, []byte(strings.Join(transcript, "\n")+"\n"), 0644)
+			if err != nil {
+				err = report(out, colors.error(err.Error()))
+				if err != nil {
+					return err
+				}
+			}
+			continue
 		}
 
-		scanned := scanner.Scan()
-		if !scanned {
-			return
+		// Loaded files and pasted blocks are run as they are, without waiting
+		// for more lines.
+		block := false
+		if source == "" && strings.HasPrefix(line, loadPrefix) {
+			contents, err := ioutil.ReadFile(strings.TrimSpace(strings.TrimPrefix(line, loadPrefix)))
+			if err != nil {
+				err = report(out, colors.error(err.Error()))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			line = string(contents)
+			block = true
+		} else if source == "" && strings.TrimSpace(line) == pasteCommand {
+			line, err = readPaste(in)
+			if err != nil {
+				return err
+			}
+
+			block = true
 		}
 
-		l := lexer.New(strings.NewReader(scanner.Text()))
-		p := parser.New(l)
-		program, err := p.ParseProgram()
+		if source == "" && !block {
+			help = strings.HasPrefix(line, helpPrefix)
+			if help {
+				line = "doc(" + strings.TrimPrefix(line, helpPrefix) + ")"
+			}
+		}
 
+		if !block && incomplete(source+line) {
+			source += line + "\n"
+			continue
+		}
+		line = source + line
+		source = ""
+
+		timer.reset()
+
+		var program *ast.Program
+		err = timer.measure("parse", func() error {
+			var err error
+			program, err = parser.New(lexer.New(strings.NewReader(line))).ParseProgram()
+			return err
+		})
 		if err != nil {
-			fmt.Print(err)
-			return
+			err = report(out, colors.error(diagnostic.Render(line, err)))
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
-		c := compiler.NewWithState(symbolTable, constants)
-		err = c.Compile(program)
+		// Names the input defines are only kept once it ran, so a failing
+		// input does not leave them bound to nothing.
+		var compiled *vm.Compiled
+		err = timer.measure("compile", func() error {
+			var err error
+			compiled, err = session.Compile(program)
+			return err
+		})
 		if err != nil {
-			fmt.Print(err)
-			return
+			err = report(out, colors.error(diagnostic.Render(line, err)))
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
-		v := vm.NewWithGlobalStore(c.Bytecode(), globals)
-		err = v.Run()
+		var v *vm.VM
+		err = timer.measure("run", func() error {
+			var err error
+			v, err = session.Run(context.Background(), compiled, vm.WithStdout(out), vm.WithStderr(config.stderr), vm.WithMaxSteps(config.maxSteps))
+			return err
+		})
+		if _, ok := err.(*object.ExitError); ok {
+			return nil
+		}
 		if err != nil {
-			fmt.Print(err)
-			return
+			err = report(out, colors.error(err.Error()))
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
-		_, err = fmt.Fprint(out, v.LastPoppedStackElement().Inspect())
+		if !help && strings.TrimSpace(line) != "" {
+			transcript = append(transcript, line)
+		}
+
+		// Nothing is popped for blank input.
+		result := v.LastPoppedStackElement()
+		if result == nil {
+			continue
+		}
+
+		if help {
+			_, err = fmt.Fprint(out, helpText(result))
+		} else {
+			// Looked up each time, `let _ = ...` defines a new global.
+			symbol, _ := session.SymbolTable.Resolve(lastResult)
+			session.Globals[symbol.Index] = result
+			_, err = fmt.Fprint(out, colors.value(result))
+		}
 		if err != nil {
-			fmt.Print(err)
-			return
+			return err
 		}
 
 		_, err = fmt.Fprint(out, "\n")
 		if err != nil {
-			fmt.Print(err)
-			return
+			return err
+		}
+
+		if timer.enabled {
+			err = report(out, timer.String())
+			if err != nil {
+				return err
+			}
 		}
 	}
 }
+
+// readPaste reads lines up to `:end` or the end of input.
+func readPaste(in input) (string, error) {
+	lines := []string{}
+	for {
+		line, err := in.ReadLine("")
+		if err == io.EOF || strings.TrimSpace(line) == pasteEnd {
+			return strings.Join(lines, "\n"), nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines, line)
+	}
+}
+
+// incomplete tells whether source leaves braces, brackets or parentheses open,
+// so the next lines belong to it too. Errors are left for the parser to report.
+func incomplete(source string) bool {
+	l := lexer.New(strings.NewReader(source))
+	depth := 0
+	for {
+		token, err := l.NextToken()
+		if err != nil {
+			return false
+		}
+
+		switch token.Type {
+		case lexer.LeftParenthesis, lexer.LeftBrace, lexer.LeftBracket:
+			depth++
+		case lexer.RightParenthesis, lexer.RightBrace, lexer.RightBracket:
+			depth--
+		case lexer.Eof:
+			return depth > 0
+		}
+	}
+}
+
+// report prints message on its own line.
+func report(out io.Writer, message string) error {
+	_, err := fmt.Fprintf(out, "%s\n", message)
+	return err
+}
+
+// helpText shows the result of doc for `:help name`.
+func helpText(doc object.Object) string {
+	if doc, ok := doc.(*object.String); ok {
+		return doc.Value
+	}
+
+	return "no documentation"
+}
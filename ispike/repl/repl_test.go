@@ -1,9 +1,14 @@
 package repl
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,3 +21,236 @@ func TestStart(t *testing.T) {
 
 	assert.Equal(t, expectedOutput, output.String())
 }
+
+func TestStart_builtins(t *testing.T) {
+	input := strings.NewReader("len(\"spike\")\n")
+	expectedOutput := ">> 5\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_printsToOutput(t *testing.T) {
+	input := strings.NewReader("println(\"hi\")\n")
+	expectedOutput := ">> hi\nnull\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_stderr(t *testing.T) {
+	input := strings.NewReader("eprintln(\"oops\"); 1\n")
+	output := &strings.Builder{}
+	stderr := &strings.Builder{}
+
+	Start(input, output, WithStderr(stderr))
+
+	assert.Equal(t, ">> 1\n>> ", output.String())
+	assert.Equal(t, "oops\n", stderr.String())
+}
+
+func TestStart_reportsOutputErrorsToStderr(t *testing.T) {
+	stderr := &strings.Builder{}
+
+	Start(strings.NewReader("1\n"), failingWriter{}, WithStderr(stderr))
+
+	assert.Equal(t, "closed\n", stderr.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestStart_exit(t *testing.T) {
+	input := strings.NewReader("exit()\n10\n")
+	expectedOutput := ">> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_help(t *testing.T) {
+	input := strings.NewReader("let f = fn(x) { \"Doubles x.\"; x * 2 }; 1\n:help f\n:help len\n")
+	expectedOutput := ">> 1\n>> Doubles x.\n>> no documentation\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_keepsState(t *testing.T) {
+	input := strings.NewReader("let x = 2;\nlet double = fn(a) { a * 2 }; 0\ndouble(x)\nlet y = \"spike\"; len(y) + double(x)\n")
+	expectedOutput := ">> 2\n>> 0\n>> 4\n>> 9\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_continuesAfterErrors(t *testing.T) {
+	input := strings.NewReader("let x = 1;\nx +\nfoo\nlen(1)\nx + 1\n")
+	expectedOutput := ">> 1\n" +
+		">> \"\" is not a valid prefix expression at 1:4\nx +\n   ^\n" +
+		">> unable to resolve identifier: foo at 1:1\nfoo\n^~~\n" +
+		">> len: argument of type integer is not supported\n" +
+		">> 2\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_forgetsNamesOfFailedInput(t *testing.T) {
+	input := strings.NewReader("let x = 1;\nlet y = x / 0;\ny\nlet x = x / 0;\nx + 1\nlet z = y;\nz\n")
+	expectedOutput := ">> 1\n" +
+		">> division by zero\n" +
+		">> unable to resolve identifier: y at 1:1\ny\n^\n" +
+		">> division by zero\n" +
+		">> 2\n" +
+		">> unable to resolve identifier: y at 1:9\nlet z = y;\n        ^\n" +
+		">> unable to resolve identifier: z at 1:1\nz\n^\n" +
+		">> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_continuesUnbalancedInput(t *testing.T) {
+	input := strings.NewReader("let add = fn(a, b) {\n  a + b\n}; 0\nadd(\n1,\n2)\n[1, \"(\"]\n")
+	expectedOutput := ">> .. .. 0\n>> .. .. 3\n>> [1, \"(\"]\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_reportsErrorsInContinuedInput(t *testing.T) {
+	input := strings.NewReader("fn(a) {\n  a +\n}\n1\n")
+	expectedOutput := ">> .. .. \"}\" is not a valid prefix expression at 3:1\n}\n^\n>> 1\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spike")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lib.spike")
+	err = ioutil.WriteFile(path, []byte("let double = fn(x) {\n  x * 2\n};\nlet ten = double(5);\n"), 0644)
+	assert.NoError(t, err)
+
+	input := strings.NewReader(":load " + path + "\nten + double(1)\n")
+	expectedOutput := ">> 10\n>> 12\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_loadReportsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spike")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "broken.spike")
+	err = ioutil.WriteFile(path, []byte("let x = 1;\nx + y\n"), 0644)
+	assert.NoError(t, err)
+
+	input := strings.NewReader(":load " + path + "\n:load " + filepath.Join(dir, "missing.spike") + "\n1\n")
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, []string{">> unable to resolve identifier: y at 2:5", "x + y", "    ^"}, lines[:3])
+	assert.Contains(t, lines[3], "missing.spike")
+	assert.Equal(t, []string{">> 1", ">> "}, lines[4:])
+}
+
+func TestStart_time(t *testing.T) {
+	input := strings.NewReader(":time on\n1 + 2\n:time off\n3\n:time\n")
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Regexp(
+		t,
+		regexp.MustCompile(`^>> >> 3\nparse \S+, \d+ allocs; compile \S+, \d+ allocs; run \S+, \d+ allocs\n>> >> 3\n>> usage: :time on\|off\n>> $`),
+		output.String(),
+	)
+}
+
+func TestStart_lastResult(t *testing.T) {
+	input := strings.NewReader("_\n2 * 3\n_ + 1\n:help len\n[_, _]\nlet _ = 10; _\n_ * 2\n")
+	expectedOutput := ">> null\n>> 6\n>> 7\n>> no documentation\n>> [7, 7]\n>> 10\n>> 20\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_paste(t *testing.T) {
+	input := strings.NewReader(":paste\nlet add = fn(a, b) {\n  a + b\n};\n\nadd(1,\n2)\n:end\nadd(2, 2)\n:paste\nlet x = 5;\nx * 2\n")
+	expectedOutput := ">> 3\n>> 4\n>> 10\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_blankInput(t *testing.T) {
+	input := strings.NewReader("\n// note\n:paste\n:end\n1\n")
+	expectedOutput := ">> >> >> >> 1\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	assert.Equal(t, expectedOutput, output.String())
+}
+
+func TestStart_save(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spike")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "session.spike")
+	input := strings.NewReader("let double = fn(x) {\n  x * 2\n};\n\nfoo\n:help len\nlen(1)\ndouble(2)\n:save " + path + "\n")
+	output := &strings.Builder{}
+
+	Start(input, output)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "let double = fn(x) {\n  x * 2\n};\ndouble(2)\n", string(contents))
+}
+
+func TestStart_maxSteps(t *testing.T) {
+	input := strings.NewReader("let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } }; 0\nf(500)\nf(5)\n")
+	expectedOutput := ">> 0\n>> step limit of 100 exceeded\n>> 0\n>> "
+	output := &strings.Builder{}
+
+	Start(input, output, WithMaxSteps(100))
+
+	assert.Equal(t, expectedOutput, output.String())
+}
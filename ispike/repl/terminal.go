@@ -0,0 +1,43 @@
+package repl
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/peterh/liner"
+	"github.com/pkg/errors"
+)
+
+// StartTerminal runs the REPL on the terminal with line editing. History is
+// read from historyPath when the session starts and saved there when it ends.
+func StartTerminal(historyPath string, options ...Option) error {
+	config := newConfig(options)
+
+	state := liner.NewLiner()
+	defer state.Close()
+	state.SetCtrlCAborts(true)
+
+	history, err := os.Open(historyPath)
+	if err == nil {
+		_, err = state.ReadHistory(history)
+		history.Close()
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "unable to read history")
+	}
+
+	err = run(&terminalInput{state: state}, os.Stdout, config.colorsFor(os.Stdout), config)
+	if err != nil {
+		fmt.Fprintln(config.stderr, err)
+	}
+	fmt.Fprintln(os.Stdout)
+
+	history, err = os.Create(historyPath)
+	if err != nil {
+		return errors.Wrap(err, "unable to save history")
+	}
+	defer history.Close()
+
+	_, err = state.WriteHistory(history)
+	return errors.Wrap(err, "unable to save history")
+}
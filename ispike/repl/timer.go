@@ -0,0 +1,41 @@
+package repl
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// timer measures how long each phase of running an input takes and how many
+// allocations it makes, when enabled with `:time on`.
+type timer struct {
+	enabled bool
+	phases  []string
+}
+
+func (timer *timer) reset() {
+	timer.phases = timer.phases[:0]
+}
+
+func (timer *timer) measure(name string, phase func() error) error {
+	if !timer.enabled {
+		return phase()
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	err := phase()
+
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+	timer.phases = append(timer.phases, fmt.Sprintf("%s %s, %d allocs", name, duration, after.Mallocs-before.Mallocs))
+
+	return err
+}
+
+func (timer *timer) String() string {
+	return strings.Join(timer.phases, "; ")
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"spike-interpreter-go/spike/lsp"
+)
+
+func main() {
+	err := lsp.Serve(os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "spike-lsp: %s\n", err)
+		os.Exit(1)
+	}
+}
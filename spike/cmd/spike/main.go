@@ -0,0 +1,10 @@
+package main
+
+import (
+	"os"
+	"spike-interpreter-go/ispike/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
@@ -23,6 +23,7 @@ const (
 	OpEqual
 	OpNotEqual
 	OpGreaterThan
+	OpLessThan
 	OpMinus
 	OpBang
 	OpJumpNotTrue
@@ -41,6 +42,12 @@ const (
 	OpGetBuiltin
 	OpClosure
 	OpGetFreeVar
+	OpTuple
+	OpDestructure
+	OpMember
+	OpYield
+	OpSetIndex
+	OpImport
 )
 
 type Definition struct {
@@ -93,6 +100,10 @@ var definitions = map[Opcode]*Definition{
 		Name:          "OpGreaterThan",
 		OperandWidths: []int{},
 	},
+	OpLessThan: {
+		Name:          "OpLessThan",
+		OperandWidths: []int{},
+	},
 	OpMinus: {
 		Name:          "OpMinus",
 		OperandWidths: []int{},
@@ -165,6 +176,30 @@ var definitions = map[Opcode]*Definition{
 		Name:          "OpGetFreeVar",
 		OperandWidths: []int{1 * Byte},
 	},
+	OpTuple: {
+		Name:          "OpTuple",
+		OperandWidths: []int{2 * Byte},
+	},
+	OpDestructure: {
+		Name:          "OpDestructure",
+		OperandWidths: []int{2 * Byte},
+	},
+	OpMember: {
+		Name:          "OpMember",
+		OperandWidths: []int{},
+	},
+	OpYield: {
+		Name:          "OpYield",
+		OperandWidths: []int{},
+	},
+	OpSetIndex: {
+		Name:          "OpSetIndex",
+		OperandWidths: []int{},
+	},
+	OpImport: {
+		Name:          "OpImport",
+		OperandWidths: []int{},
+	},
 }
 
 type Instructions []byte
@@ -180,6 +215,7 @@ func (instructions Instructions) String() string {
 			if err != nil {
 				panic(err)
 			}
+			i++
 			continue
 		}
 
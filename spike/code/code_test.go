@@ -1,6 +1,7 @@
 package code
 
 import (
+	"spike-interpreter-go/spike/lexer"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,7 @@ func Test_Instructions_String(t *testing.T) {
 		Make(OpGetBuiltin, 255).
 		Make(OpClosure, 65535, 255).
 		Make(OpGetFreeVar, 255).
+		Make(OpLessThan).
 		Build()
 
 	expectedOutput := `0000 OpConstant 2
@@ -93,6 +95,7 @@ func Test_Instructions_String(t *testing.T) {
 0041 OpGetBuiltin 255
 0043 OpClosure 65535 255
 0047 OpGetFreeVar 255
+0049 OpLessThan
 `
 
 	assert.Equal(t, expectedOutput, instructions.String())
@@ -113,3 +116,26 @@ func Test_ReadOperands(t *testing.T) {
 	assert.Equal(t, expectedOperandBytes, operandBytes)
 	assert.Equal(t, expectedOperands, operandsRead)
 }
+
+func Test_SourceMap_Lookup(t *testing.T) {
+	sourceMap := SourceMap{}.
+		Add(0, lexer.Position{Line: 1, Column: 1}).
+		Add(3, lexer.Position{Line: 1, Column: 1}).
+		Add(4, lexer.Position{Line: 2, Column: 5}).
+		Truncate(6).
+		Add(7, lexer.Position{Line: 3, Column: 2})
+
+	for offset, expected := range map[int]lexer.Position{
+		0: {Line: 1, Column: 1},
+		3: {Line: 1, Column: 1},
+		6: {Line: 2, Column: 5},
+		9: {Line: 3, Column: 2},
+	} {
+		position, ok := sourceMap.Lookup(offset)
+		assert.True(t, ok)
+		assert.Equal(t, expected, position)
+	}
+
+	_, ok := SourceMap{}.Lookup(0)
+	assert.False(t, ok)
+}
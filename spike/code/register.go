@@ -0,0 +1,166 @@
+package code
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// RegisterOpcode is an instruction of the experimental register machine.
+// Register instructions are 32 bits wide: the opcode and three one byte
+// operands A, B and C, or A and a two byte operand Bx in place of B and C.
+type RegisterOpcode byte
+
+const (
+	// RegLoadConstant loads constant Bx into register A.
+	RegLoadConstant RegisterOpcode = iota
+	RegLoadTrue
+	RegLoadFalse
+	RegLoadNull
+	// RegMove copies register B into register A.
+	RegMove
+	// RegGetGlobal loads global Bx into register A.
+	RegGetGlobal
+	// RegSetGlobal stores register A in global Bx.
+	RegSetGlobal
+	// RegAdd and the other binary operators store B op C in register A.
+	RegAdd
+	RegSub
+	RegMul
+	RegDiv
+	RegEqual
+	RegNotEqual
+	RegGreaterThan
+	RegLessThan
+	// RegMinus and RegBang store op B in register A.
+	RegMinus
+	RegBang
+	// RegJump continues at instruction Bx.
+	RegJump
+	// RegJumpNotTrue continues at instruction Bx unless register A is truthy.
+	RegJumpNotTrue
+	// RegCall calls register B with the C registers after it as arguments and
+	// stores the result in register A.
+	RegCall
+	// RegReturn returns register A.
+	RegReturn
+)
+
+// MaxRegisters is how many registers a function compiled for the register
+// machine may use.
+const MaxRegisters = 256
+
+type registerFormat int
+
+const (
+	formatA registerFormat = iota
+	formatAB
+	formatABC
+	formatABx
+	formatBx
+)
+
+type registerDefinition struct {
+	name   string
+	format registerFormat
+}
+
+var registerDefinitions = map[RegisterOpcode]registerDefinition{
+	RegLoadConstant: {"RegLoadConstant", formatABx},
+	RegLoadTrue:     {"RegLoadTrue", formatA},
+	RegLoadFalse:    {"RegLoadFalse", formatA},
+	RegLoadNull:     {"RegLoadNull", formatA},
+	RegMove:         {"RegMove", formatAB},
+	RegGetGlobal:    {"RegGetGlobal", formatABx},
+	RegSetGlobal:    {"RegSetGlobal", formatABx},
+	RegAdd:          {"RegAdd", formatABC},
+	RegSub:          {"RegSub", formatABC},
+	RegMul:          {"RegMul", formatABC},
+	RegDiv:          {"RegDiv", formatABC},
+	RegEqual:        {"RegEqual", formatABC},
+	RegNotEqual:     {"RegNotEqual", formatABC},
+	RegGreaterThan:  {"RegGreaterThan", formatABC},
+	RegLessThan:     {"RegLessThan", formatABC},
+	RegMinus:        {"RegMinus", formatAB},
+	RegBang:         {"RegBang", formatAB},
+	RegJump:         {"RegJump", formatBx},
+	RegJumpNotTrue:  {"RegJumpNotTrue", formatABx},
+	RegCall:         {"RegCall", formatABC},
+	RegReturn:       {"RegReturn", formatA},
+}
+
+func (opcode RegisterOpcode) String() string {
+	if definition, ok := registerDefinitions[opcode]; ok {
+		return definition.name
+	}
+
+	return fmt.Sprintf("RegisterOpcode(%d)", byte(opcode))
+}
+
+type RegisterInstruction uint32
+
+// MakeRegister encodes an instruction with operands A, B and C.
+func MakeRegister(opcode RegisterOpcode, a, b, c int) RegisterInstruction {
+	return RegisterInstruction(opcode) | RegisterInstruction(byte(a))<<8 | RegisterInstruction(byte(b))<<16 | RegisterInstruction(byte(c))<<24
+}
+
+// MakeRegisterWide encodes an instruction with operands A and Bx.
+func MakeRegisterWide(opcode RegisterOpcode, a, bx int) RegisterInstruction {
+	return RegisterInstruction(opcode) | RegisterInstruction(byte(a))<<8 | RegisterInstruction(uint16(bx))<<16
+}
+
+func (instruction RegisterInstruction) Opcode() RegisterOpcode {
+	return RegisterOpcode(instruction)
+}
+
+func (instruction RegisterInstruction) A() int {
+	return int(byte(instruction >> 8))
+}
+
+func (instruction RegisterInstruction) B() int {
+	return int(byte(instruction >> 16))
+}
+
+func (instruction RegisterInstruction) C() int {
+	return int(byte(instruction >> 24))
+}
+
+func (instruction RegisterInstruction) Bx() int {
+	return int(uint16(instruction >> 16))
+}
+
+func (instruction RegisterInstruction) String() string {
+	definition, ok := registerDefinitions[instruction.Opcode()]
+	if !ok {
+		return fmt.Sprintf("ERROR: register opcode %d undefined", instruction.Opcode())
+	}
+
+	switch definition.format {
+	case formatA:
+		return fmt.Sprintf("%s %d", definition.name, instruction.A())
+	case formatAB:
+		return fmt.Sprintf("%s %d %d", definition.name, instruction.A(), instruction.B())
+	case formatABC:
+		return fmt.Sprintf("%s %d %d %d", definition.name, instruction.A(), instruction.B(), instruction.C())
+	case formatABx:
+		return fmt.Sprintf("%s %d %d", definition.name, instruction.A(), instruction.Bx())
+	}
+
+	return fmt.Sprintf("%s %d", definition.name, instruction.Bx())
+}
+
+// RegisterInstructions is code for the register machine. Jumps address
+// instructions by their index.
+type RegisterInstructions []RegisterInstruction
+
+func (instructions RegisterInstructions) String() string {
+	var result bytes.Buffer
+
+	for i, instruction := range instructions {
+		_, err := fmt.Fprintf(&result, "%04d %s\n", i, instruction)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return result.String()
+}
@@ -0,0 +1,47 @@
+package code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MakeRegister(t *testing.T) {
+	instruction := MakeRegister(RegAdd, 2, 255, 1)
+
+	assert.Equal(t, RegAdd, instruction.Opcode())
+	assert.Equal(t, 2, instruction.A())
+	assert.Equal(t, 255, instruction.B())
+	assert.Equal(t, 1, instruction.C())
+
+	wide := MakeRegisterWide(RegLoadConstant, 3, 65534)
+
+	assert.Equal(t, RegLoadConstant, wide.Opcode())
+	assert.Equal(t, 3, wide.A())
+	assert.Equal(t, 65534, wide.Bx())
+}
+
+func Test_RegisterInstructions_String(t *testing.T) {
+	instructions := RegisterInstructions{
+		MakeRegisterWide(RegLoadConstant, 0, 256),
+		MakeRegister(RegLoadNull, 1, 0, 0),
+		MakeRegister(RegMove, 1, 0, 0),
+		MakeRegister(RegLessThan, 2, 0, 1),
+		MakeRegisterWide(RegJumpNotTrue, 2, 6),
+		MakeRegisterWide(RegJump, 0, 7),
+		MakeRegister(RegCall, 0, 1, 2),
+		MakeRegister(RegReturn, 0, 0, 0),
+	}
+
+	expected := `0000 RegLoadConstant 0 256
+0001 RegLoadNull 1
+0002 RegMove 1 0
+0003 RegLessThan 2 0 1
+0004 RegJumpNotTrue 2 6
+0005 RegJump 7
+0006 RegCall 0 1 2
+0007 RegReturn 0
+`
+
+	assert.Equal(t, expected, instructions.String())
+}
@@ -0,0 +1,49 @@
+package code
+
+import (
+	"sort"
+	"spike-interpreter-go/spike/lexer"
+)
+
+// SourceMapping marks the instructions from Offset up to the next mapping as
+// compiled from the source at Position.
+type SourceMapping struct {
+	Offset   int
+	Position lexer.Position
+}
+
+// SourceMap maps instructions back to the source, ordered by offset.
+type SourceMap []SourceMapping
+
+// Lookup returns the position the instruction at offset was compiled from.
+func (sourceMap SourceMap) Lookup(offset int) (lexer.Position, bool) {
+	i := sort.Search(len(sourceMap), func(i int) bool {
+		return sourceMap[i].Offset > offset
+	})
+	if i == 0 {
+		return lexer.Position{}, false
+	}
+
+	return sourceMap[i-1].Position, true
+}
+
+// Add maps the instructions from offset on to position.
+func (sourceMap SourceMap) Add(offset int, position lexer.Position) SourceMap {
+	if len(sourceMap) > 0 && sourceMap[len(sourceMap)-1].Position == position {
+		return sourceMap
+	}
+	if len(sourceMap) > 0 && sourceMap[len(sourceMap)-1].Offset == offset {
+		return append(sourceMap[:len(sourceMap)-1], SourceMapping{Offset: offset, Position: position})
+	}
+
+	return append(sourceMap, SourceMapping{Offset: offset, Position: position})
+}
+
+// Truncate drops the mappings of instructions past length.
+func (sourceMap SourceMap) Truncate(length int) SourceMap {
+	i := sort.Search(len(sourceMap), func(i int) bool {
+		return sourceMap[i].Offset >= length
+	})
+
+	return sourceMap[:i]
+}
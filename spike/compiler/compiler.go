@@ -1,13 +1,11 @@
 package compiler
 
 import (
-	"fmt"
-	"sort"
 	"spike-interpreter-go/spike/code"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
 	"spike-interpreter-go/spike/parser/ast"
-
-	"github.com/pkg/errors"
 )
 
 type EmittedInstruction struct {
@@ -19,6 +17,7 @@ type CompilationScope struct {
 	instructions        code.Instructions
 	lastInstruction     EmittedInstruction
 	previousInstruction EmittedInstruction
+	sourceMap           code.SourceMap
 }
 
 type Compiler struct {
@@ -27,9 +26,17 @@ type Compiler struct {
 
 	scopes     []CompilationScope
 	scopeIndex int
+
+	// position is where the node being compiled starts, it is recorded in the
+	// source map for every instruction emitted.
+	position lexer.Position
+
+	registers *registerGenerator
 }
 
-func New() *Compiler {
+type Option func(compiler *Compiler)
+
+func New(options ...Option) *Compiler {
 	mainScope := CompilationScope{
 		instructions:        code.Instructions{},
 		lastInstruction:     EmittedInstruction{},
@@ -41,12 +48,17 @@ func New() *Compiler {
 		symbolTable.DefineBuiltin(i, builtin.Name)
 	}
 
-	return &Compiler{
+	compiler := &Compiler{
 		constants:   []object.Object{},
 		symbolTable: symbolTable,
 		scopes:      []CompilationScope{mainScope},
 		scopeIndex:  0,
 	}
+	for _, option := range options {
+		option(compiler)
+	}
+
+	return compiler
 }
 
 func NewWithState(symbolTable *SymbolTable, constants []object.Object) *Compiler {
@@ -58,6 +70,16 @@ func NewWithState(symbolTable *SymbolTable, constants []object.Object) *Compiler
 }
 
 func (compiler *Compiler) Compile(node ast.Node) error {
+	if compiler.registers != nil {
+		return compiler.registers.compile(node)
+	}
+
+	if position := sourcePosition(node); position.Line > 0 {
+		outer := compiler.position
+		compiler.position = position
+		defer func() { compiler.position = outer }()
+	}
+
 	switch node := node.(type) {
 	case *ast.Program:
 		for _, statement := range node.Statements {
@@ -83,28 +105,12 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 		}
 
 	case *ast.InfixExpression:
-		if node.Operator == "<" {
-			err := compiler.Compile(node.Right)
-			if err != nil {
-				return err
-			}
-
-			err = compiler.Compile(node.Left)
-			if err != nil {
-				return err
-			}
-
-			compiler.emit(code.OpGreaterThan)
-
-			return nil
-		}
-
-		err := compiler.Compile(node.Left)
+		err := compiler.compileOperand(node.Left)
 		if err != nil {
 			return err
 		}
 
-		err = compiler.Compile(node.Right)
+		err = compiler.compileOperand(node.Right)
 		if err != nil {
 			return err
 		}
@@ -124,8 +130,10 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 			compiler.emit(code.OpNotEqual)
 		case ">":
 			compiler.emit(code.OpGreaterThan)
+		case "<":
+			compiler.emit(code.OpLessThan)
 		default:
-			return fmt.Errorf("unknown operator: %s", node.Operator)
+			return diagnostic.NewCompileError(node, diagnostic.UnknownOperator, "unknown operator: %s", node.Operator)
 		}
 
 	case *ast.PrefixExpression:
@@ -140,13 +148,16 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 		case "-":
 			compiler.emit(code.OpMinus)
 		default:
-			return errors.Errorf("invalid prefix operator: %s", node.Operator)
+			return diagnostic.NewCompileError(node, diagnostic.UnknownOperator, "invalid prefix operator: %s", node.Operator)
 		}
 
 	case *ast.Integer:
 		integer := &object.Integer{Value: node.Value}
 		compiler.emit(code.OpConstant, compiler.addConstant(integer))
 
+	case *ast.Float:
+		return diagnostic.NewCompileError(node, diagnostic.Unsupported, "float literals are not supported yet: %s", node.Token.Literal)
+
 	case *ast.String:
 		str := &object.String{Value: node.Value}
 		compiler.emit(code.OpConstant, compiler.addConstant(str))
@@ -171,9 +182,7 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 			return err
 		}
 
-		if compiler.lastInstructionIs(code.OpPop) {
-			compiler.removeLastInstruction()
-		}
+		compiler.keepBlockValue()
 
 		if node.Else == nil {
 			jumpIndex := compiler.emit(code.OpJump, -1)
@@ -194,36 +203,117 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 				return err
 			}
 
-			if compiler.lastInstructionIs(code.OpPop) {
-				compiler.removeLastInstruction()
-			}
+			compiler.keepBlockValue()
 
 			afterElseIndex := len(compiler.scopes[compiler.scopeIndex].instructions)
 			compiler.changeOperand(jumpIndex, afterElseIndex)
 		}
 
 	case *ast.LetStatement:
-		symbol := compiler.symbolTable.Define(node.Name.Value)
+		// A function calls itself by the name it is bound to, any other value
+		// sees the binding the name shadows, if there is one.
+		var symbol Symbol
+		_, function := node.Value.(*ast.FunctionExpression)
+		if function {
+			symbol = compiler.symbolTable.Define(node.Name.Value)
+		}
+
+		err := compiler.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+
+		if !function {
+			symbol = compiler.symbolTable.Define(node.Name.Value)
+		}
+
+		if symbol.SymbolScope == GlobalScope {
+			compiler.emit(code.OpSetGlobal, symbol.Index)
+		} else {
+			compiler.emit(code.OpSetLocal, symbol.Index)
+		}
+
+	case *ast.DestructuringLetStatement:
 		err := compiler.Compile(node.Value)
 		if err != nil {
 			return err
 		}
 
+		compiler.emit(code.OpDestructure, len(node.Names))
+		for _, name := range node.Names {
+			symbol := compiler.symbolTable.Define(name.Value)
+			if symbol.SymbolScope == GlobalScope {
+				compiler.emit(code.OpSetGlobal, symbol.Index)
+			} else {
+				compiler.emit(code.OpSetLocal, symbol.Index)
+			}
+		}
+
+	case *ast.EnumStatement:
+		members := make([]string, len(node.Members))
+		for i, member := range node.Members {
+			members[i] = member.Value
+		}
+
+		enum := object.NewEnum(node.Name.Value, members)
+		compiler.emit(code.OpConstant, compiler.addConstant(enum))
+
+		symbol := compiler.symbolTable.Define(node.Name.Value)
 		if symbol.SymbolScope == GlobalScope {
 			compiler.emit(code.OpSetGlobal, symbol.Index)
 		} else {
 			compiler.emit(code.OpSetLocal, symbol.Index)
 		}
 
+	case *ast.ImportStatement:
+		compiler.emit(code.OpConstant, compiler.addConstant(&object.String{Value: node.Module.Value}))
+		compiler.emit(code.OpImport)
+
+		symbol := compiler.symbolTable.Define(node.Module.Value)
+		if symbol.SymbolScope == GlobalScope {
+			compiler.emit(code.OpSetGlobal, symbol.Index)
+		} else {
+			compiler.emit(code.OpSetLocal, symbol.Index)
+		}
+
+	case *ast.MemberExpression:
+		err := compiler.Compile(node.Object)
+		if err != nil {
+			return err
+		}
+
+		compiler.emit(code.OpConstant, compiler.addConstant(&object.String{Value: node.Member.Value}))
+		compiler.emit(code.OpMember)
+
+	case *ast.AssignExpression:
+		return compiler.compileAssignment(node)
+
+	case *ast.Tuple:
+		for _, element := range node.Elements {
+			err := compiler.Compile(element)
+			if err != nil {
+				return err
+			}
+		}
+
+		compiler.emit(code.OpTuple, len(node.Elements))
+
 	case *ast.Identifier:
 		symbol, ok := compiler.symbolTable.Resolve(node.Value)
 		if !ok {
-			return errors.Errorf("unable to resolve identifier: %s", node.Value)
+			return diagnostic.NewCompileError(node, diagnostic.UnresolvedIdentifier, "unable to resolve identifier: %s", node.Value)
 		}
 
 		compiler.loadSymbol(symbol)
 
 	case *ast.Array:
+		if len(node.Elements) > 0 {
+			if array, ok := constantLiteral(node); ok {
+				compiler.emit(code.OpConstant, compiler.addConstant(array))
+				return nil
+			}
+		}
+
 		for _, element := range node.Elements {
 			err := compiler.Compile(element)
 			if err != nil {
@@ -234,15 +324,14 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 		compiler.emit(code.OpArray, len(node.Elements))
 
 	case *ast.Hash:
-		keys := make([]ast.Expression, 0)
-		for key := range node.Pairs {
-			keys = append(keys, key)
+		if len(node.Pairs) > 0 {
+			if hash, ok := constantLiteral(node); ok {
+				compiler.emit(code.OpConstant, compiler.addConstant(hash))
+				return nil
+			}
 		}
-		sort.Slice(keys, func(i, j int) bool {
-			return keys[i].String() < keys[j].String()
-		})
 
-		for _, key := range keys {
+		for _, key := range node.Keys {
 			err := compiler.Compile(key)
 			if err != nil {
 				return err
@@ -257,7 +346,7 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 		compiler.emit(code.OpHash, len(node.Pairs)*2)
 
 	case *ast.IndexExpression:
-		err := compiler.Compile(node.Array)
+		err := compiler.compileOperand(node.Array)
 		if err != nil {
 			return err
 		}
@@ -294,7 +383,8 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 
 		freeSymbols := compiler.symbolTable.FreeSymbols
 		localCount := compiler.symbolTable.numDefinitions
-		instructions := compiler.leaveScope()
+		localNames := compiler.symbolTable.localNames()
+		instructions, sourceMap := compiler.leaveScope()
 
 		for _, symbol := range freeSymbols {
 			compiler.loadSymbol(symbol)
@@ -304,16 +394,34 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 			Instructions:    instructions,
 			LocalsCount:     localCount,
 			ParametersCount: len(node.Parameters),
+			Name:            node.Name,
+			Generator:       node.Generator,
+			Doc:             node.Doc(),
+			SourceMap:       sourceMap,
+			LocalNames:      localNames,
+			Line:            node.Pos().Line,
 		}
 		index := compiler.addConstant(compiledFunction)
 		compiler.emit(code.OpClosure, index, len(freeSymbols))
 
-	case *ast.ReturnStatement:
-		err := compiler.Compile(node.Result)
+	case *ast.YieldExpression:
+		err := compiler.Compile(node.Value)
 		if err != nil {
 			return err
 		}
 
+		compiler.emit(code.OpYield)
+
+	case *ast.ReturnStatement:
+		if node.Result == nil {
+			compiler.emit(code.OpNull)
+		} else {
+			err := compiler.Compile(node.Result)
+			if err != nil {
+				return err
+			}
+		}
+
 		compiler.emit(code.OpReturnValue)
 
 	case *ast.CallExpression:
@@ -322,6 +430,14 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 			return err
 		}
 
+		if compiler.callsFreezing(node) && len(node.Arguments) == 1 {
+			if literal, ok := constantLiteral(node.Arguments[0]); ok {
+				compiler.emit(code.OpConstant, compiler.addConstant(object.Freeze(literal)))
+				compiler.emit(code.OpCall, 1)
+				return nil
+			}
+		}
+
 		for _, argument := range node.Arguments {
 			err = compiler.Compile(argument)
 			if err != nil {
@@ -335,6 +451,159 @@ func (compiler *Compiler) Compile(node ast.Node) error {
 	return nil
 }
 
+// compileOperand compiles node where its value is only read, as the array of
+// an index or an operand of an infix operator. A literal of integers and
+// strings can not be changed from there, nor can anything taken out of it, so
+// it is folded into a frozen constant OpConstant shares instead of copying.
+func (compiler *Compiler) compileOperand(node ast.Expression) error {
+	if flatLiteral(node) {
+		if literal, ok := constantLiteral(node); ok {
+			compiler.emit(code.OpConstant, compiler.addConstant(object.Freeze(literal)))
+			return nil
+		}
+	}
+
+	return compiler.Compile(node)
+}
+
+// flatLiteral tells whether node is a non-empty array or hash literal holding
+// integer and string literals only.
+func flatLiteral(node ast.Expression) bool {
+	var values []ast.Expression
+	switch node := node.(type) {
+	case *ast.Array:
+		values = node.Elements
+	case *ast.Hash:
+		for _, key := range node.Keys {
+			values = append(values, key, node.Pairs[key])
+		}
+	}
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, value := range values {
+		switch value.(type) {
+		case *ast.Integer, *ast.String:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// callsFreezing tells whether node calls one of object.Builtins marked
+// Freezes. Bindings and builtins the host registers under the same name hide
+// it, the latter come after object.Builtins.
+func (compiler *Compiler) callsFreezing(node *ast.CallExpression) bool {
+	identifier, ok := node.Function.(*ast.Identifier)
+	if !ok {
+		return false
+	}
+
+	symbol, ok := compiler.symbolTable.Resolve(identifier.Value)
+	if !ok || symbol.SymbolScope != BuiltinScope || symbol.Index >= len(object.Builtins) {
+		return false
+	}
+
+	return object.Builtins[symbol.Index].Freezes
+}
+
+// constantLiteral builds the object for a literal made only of integers,
+// strings and nested literals of those, so it can be loaded with a single
+// OpConstant instead of being assembled on the stack at runtime. Hashes keyed
+// by arrays are left to OpHash, which fails on them when they run.
+func constantLiteral(node ast.Expression) (object.Object, bool) {
+	switch node := node.(type) {
+	case *ast.Integer:
+		return &object.Integer{Value: node.Value}, true
+
+	case *ast.String:
+		return &object.String{Value: node.Value}, true
+
+	case *ast.Array:
+		elements := make([]object.Object, len(node.Elements))
+		for i, element := range node.Elements {
+			value, ok := constantLiteral(element)
+			if !ok {
+				return nil, false
+			}
+			elements[i] = value
+		}
+
+		return &object.Array{Elements: elements}, true
+
+	case *ast.Hash:
+		hash := object.NewHash(len(node.Pairs))
+		for _, keyNode := range node.Keys {
+			key, ok := constantLiteral(keyNode)
+			if !ok {
+				return nil, false
+			}
+			if _, err := object.HashKeyOf(key); err != nil {
+				return nil, false
+			}
+			value, ok := constantLiteral(node.Pairs[keyNode])
+			if !ok {
+				return nil, false
+			}
+
+			hash.Set(key, value)
+		}
+
+		return hash, true
+	}
+
+	return nil, false
+}
+
+// compileAssignment leaves the assigned value on the stack. Only globals and
+// locals can be assigned, closures hold copies of their free variables.
+func (compiler *Compiler) compileAssignment(node *ast.AssignExpression) error {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		symbol, ok := compiler.symbolTable.Resolve(target.Value)
+		if !ok {
+			return diagnostic.NewCompileError(target, diagnostic.UnresolvedIdentifier, "unable to resolve identifier: %s", target.Value)
+		}
+
+		switch symbol.SymbolScope {
+		case FreeScope:
+			return diagnostic.NewCompileError(target, diagnostic.InvalidAssignment, "cannot assign to captured variable %s", target.Value)
+		case BuiltinScope:
+			return diagnostic.NewCompileError(target, diagnostic.InvalidAssignment, "cannot assign to builtin %s", target.Value)
+		}
+
+		err := compiler.Compile(node.Value)
+		if err != nil {
+			return err
+		}
+
+		if symbol.SymbolScope == GlobalScope {
+			compiler.emit(code.OpSetGlobal, symbol.Index)
+		} else {
+			compiler.emit(code.OpSetLocal, symbol.Index)
+		}
+		compiler.loadSymbol(symbol)
+
+	case *ast.IndexExpression:
+		for _, expression := range []ast.Expression{target.Array, target.Index, node.Value} {
+			err := compiler.Compile(expression)
+			if err != nil {
+				return err
+			}
+		}
+
+		compiler.emit(code.OpSetIndex)
+
+	default:
+		return diagnostic.NewCompileError(node.Target, diagnostic.InvalidAssignment, "cannot assign to %s", node.Target.String())
+	}
+
+	return nil
+}
+
 func (compiler *Compiler) loadSymbol(symbol Symbol) {
 	switch symbol.SymbolScope {
 	case GlobalScope:
@@ -358,6 +627,9 @@ func (compiler *Compiler) emit(opcode code.Opcode, operands ...int) int {
 
 	newInstructionIndex := len(compiler.scopes[compiler.scopeIndex].instructions)
 	compiler.scopes[compiler.scopeIndex].instructions = append(compiler.scopes[compiler.scopeIndex].instructions, instruction...)
+	if compiler.position.Line > 0 {
+		compiler.scopes[compiler.scopeIndex].sourceMap = compiler.scopes[compiler.scopeIndex].sourceMap.Add(newInstructionIndex, compiler.position)
+	}
 
 	compiler.scopes[compiler.scopeIndex].previousInstruction = compiler.scopes[compiler.scopeIndex].lastInstruction
 	compiler.scopes[compiler.scopeIndex].lastInstruction = EmittedInstruction{
@@ -368,8 +640,20 @@ func (compiler *Compiler) emit(opcode code.Opcode, operands ...int) int {
 	return newInstructionIndex
 }
 
+// keepBlockValue leaves the value of a compiled branch on the stack. Branches
+// that do not end with an expression, such as `{}`, are worth null.
+func (compiler *Compiler) keepBlockValue() {
+	if compiler.lastInstructionIs(code.OpPop) {
+		compiler.removeLastInstruction()
+		return
+	}
+
+	compiler.emit(code.OpNull)
+}
+
 func (compiler *Compiler) removeLastInstruction() {
 	compiler.scopes[compiler.scopeIndex].instructions = compiler.scopes[compiler.scopeIndex].instructions[:compiler.scopes[compiler.scopeIndex].lastInstruction.Position]
+	compiler.scopes[compiler.scopeIndex].sourceMap = compiler.scopes[compiler.scopeIndex].sourceMap.Truncate(compiler.scopes[compiler.scopeIndex].lastInstruction.Position)
 	compiler.scopes[compiler.scopeIndex].lastInstruction = compiler.scopes[compiler.scopeIndex].previousInstruction
 }
 
@@ -380,10 +664,19 @@ func (compiler *Compiler) changeOperand(instructionIndex, operand int) {
 	compiler.replaceInstruction(instructionIndex, newInstruction)
 }
 
+func (compiler *Compiler) SymbolTable() *SymbolTable {
+	return compiler.symbolTable
+}
+
 func (compiler *Compiler) Bytecode() *Bytecode {
+	if compiler.registers != nil {
+		return compiler.registers.bytecode()
+	}
+
 	return &Bytecode{
 		Instructions: compiler.scopes[compiler.scopeIndex].instructions,
 		Constants:    compiler.constants,
+		SourceMap:    compiler.scopes[compiler.scopeIndex].sourceMap,
 	}
 }
 
@@ -423,16 +716,31 @@ func (compiler *Compiler) enterScope() {
 	compiler.scopeIndex++
 }
 
-func (compiler *Compiler) leaveScope() code.Instructions {
+func (compiler *Compiler) leaveScope() (code.Instructions, code.SourceMap) {
 	compiler.symbolTable = compiler.symbolTable.Outer
-	instructions := compiler.scopes[compiler.scopeIndex].instructions
+	scope := compiler.scopes[compiler.scopeIndex]
 	compiler.scopes = compiler.scopes[:len(compiler.scopes)-1]
 	compiler.scopeIndex--
 
-	return instructions
+	return scope.instructions, scope.sourceMap
+}
+
+// sourcePosition is where runtime errors of node are reported: at the operator
+// of infix expressions and at the start of any other node.
+func sourcePosition(node ast.Node) lexer.Position {
+	if infix, ok := node.(*ast.InfixExpression); ok {
+		return infix.Token.Position
+	}
+
+	return node.Pos()
 }
 
 type Bytecode struct {
 	Instructions code.Instructions
 	Constants    []object.Object
+	SourceMap    code.SourceMap
+	// Registers and RegistersCount replace Instructions for programs compiled
+	// WithRegisters.
+	Registers      code.RegisterInstructions
+	RegistersCount int
 }
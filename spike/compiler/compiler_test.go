@@ -101,13 +101,13 @@ func Test_Compiler(t *testing.T) {
 		{
 			code: "1 < 2",
 			expectedConstants: []object.Object{
-				&object.Integer{Value: 2},
 				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
 				Make(code.OpConstant, 1).
-				Make(code.OpGreaterThan).
+				Make(code.OpLessThan).
 				Make(code.OpPop).
 				Build(),
 		},
@@ -272,16 +272,40 @@ func Test_Compiler(t *testing.T) {
 		},
 		{
 			code: `[1, 2, 3]`,
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpPop).
+				Build(),
+		},
+		{
+			code: `[["a"], []]`,
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Array{Elements: []object.Object{&object.String{Value: "a"}}},
+					&object.Array{Elements: []object.Object{}},
+				}},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpPop).
+				Build(),
+		},
+		{
+			code: `[1, true]`,
 			expectedConstants: []object.Object{
 				&object.Integer{Value: 1},
-				&object.Integer{Value: 2},
-				&object.Integer{Value: 3},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
-				Make(code.OpConstant, 1).
-				Make(code.OpConstant, 2).
-				Make(code.OpArray, 3).
+				Make(code.OpTrue).
+				Make(code.OpArray, 2).
 				Make(code.OpPop).
 				Build(),
 		},
@@ -313,29 +337,55 @@ func Test_Compiler(t *testing.T) {
 				Build(),
 		},
 		{
-			code: `{1: 2, 3: 4}`,
+			code: `{1: 2, 3: "x"}`,
+			expectedConstants: []object.Object{
+				&object.Hash{Pairs: map[object.HashKey]object.HashPair{
+					(&object.Integer{Value: 1}).GetHashKey(): {
+						Key:   &object.Integer{Value: 1},
+						Value: &object.Integer{Value: 2},
+					},
+					(&object.Integer{Value: 3}).GetHashKey(): {
+						Key:   &object.Integer{Value: 3},
+						Value: &object.String{Value: "x"},
+					},
+				}, Keys: []object.HashKey{
+					(&object.Integer{Value: 1}).GetHashKey(),
+					(&object.Integer{Value: 3}).GetHashKey(),
+				}},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpPop).
+				Build(),
+		},
+		{
+			code: `{1 + 2: 2 - 3}`,
 			expectedConstants: []object.Object{
 				&object.Integer{Value: 1},
 				&object.Integer{Value: 2},
+				&object.Integer{Value: 2},
 				&object.Integer{Value: 3},
-				&object.Integer{Value: 4},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
 				Make(code.OpConstant, 1).
+				Make(code.OpAdd).
 				Make(code.OpConstant, 2).
 				Make(code.OpConstant, 3).
-				Make(code.OpHash, 4).
+				Make(code.OpSub).
+				Make(code.OpHash, 2).
 				Make(code.OpPop).
 				Build(),
 		},
 		{
-			code: `{1 + 2: 2 - 3}`,
+			code: `{"b" + "": 1, "a" + "": 2}`,
 			expectedConstants: []object.Object{
+				&object.String{Value: "b"},
+				&object.String{Value: ""},
 				&object.Integer{Value: 1},
+				&object.String{Value: "a"},
+				&object.String{Value: ""},
 				&object.Integer{Value: 2},
-				&object.Integer{Value: 2},
-				&object.Integer{Value: 3},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
@@ -343,7 +393,22 @@ func Test_Compiler(t *testing.T) {
 				Make(code.OpAdd).
 				Make(code.OpConstant, 2).
 				Make(code.OpConstant, 3).
-				Make(code.OpSub).
+				Make(code.OpConstant, 4).
+				Make(code.OpAdd).
+				Make(code.OpConstant, 5).
+				Make(code.OpHash, 4).
+				Make(code.OpPop).
+				Build(),
+		},
+		{
+			code: `{[1]: 2}`,
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+				&object.Integer{Value: 2},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpConstant, 1).
 				Make(code.OpHash, 2).
 				Make(code.OpPop).
 				Build(),
@@ -351,36 +416,70 @@ func Test_Compiler(t *testing.T) {
 		{
 			code: `[1, 2][0 + 1]`,
 			expectedConstants: []object.Object{
-				&object.Integer{Value: 1},
-				&object.Integer{Value: 2},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+				}, Frozen: true},
 				&object.Integer{Value: 0},
 				&object.Integer{Value: 1},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
 				Make(code.OpConstant, 1).
-				Make(code.OpArray, 2).
 				Make(code.OpConstant, 2).
-				Make(code.OpConstant, 3).
 				Make(code.OpAdd).
 				Make(code.OpIndex).
 				Make(code.OpPop).
 				Build(),
 		},
+		{
+			code: `[[1]][0] == [1]`,
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+				}},
+				&object.Integer{Value: 0},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}, Frozen: true},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpConstant, 1).
+				Make(code.OpIndex).
+				Make(code.OpConstant, 2).
+				Make(code.OpEqual).
+				Make(code.OpPop).
+				Build(),
+		},
+		{
+			code: `freeze([[1]])`,
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}, Frozen: true},
+				}, Frozen: true},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpGetBuiltin, 3).
+				Make(code.OpConstant, 0).
+				Make(code.OpCall, 1).
+				Make(code.OpPop).
+				Build(),
+		},
 		{
 			code: `{1: 2}[0 + 1]`,
 			expectedConstants: []object.Object{
-				&object.Integer{Value: 1},
-				&object.Integer{Value: 2},
+				&object.Hash{Pairs: map[object.HashKey]object.HashPair{
+					(&object.Integer{Value: 1}).GetHashKey(): {
+						Key:   &object.Integer{Value: 1},
+						Value: &object.Integer{Value: 2},
+					},
+				}, Keys: []object.HashKey{(&object.Integer{Value: 1}).GetHashKey()}, Frozen: true},
 				&object.Integer{Value: 0},
 				&object.Integer{Value: 1},
 			},
 			expectedInstructions: code.NewBuilder().
 				Make(code.OpConstant, 0).
 				Make(code.OpConstant, 1).
-				Make(code.OpHash, 2).
 				Make(code.OpConstant, 2).
-				Make(code.OpConstant, 3).
 				Make(code.OpAdd).
 				Make(code.OpIndex).
 				Make(code.OpPop).
@@ -495,6 +594,7 @@ func Test_Compiler(t *testing.T) {
 						Build(),
 					LocalsCount:     0,
 					ParametersCount: 0,
+					Name:            "f",
 				},
 			},
 			expectedInstructions: code.NewBuilder().
@@ -555,6 +655,7 @@ func Test_Compiler(t *testing.T) {
 						Build(),
 					LocalsCount:     1,
 					ParametersCount: 1,
+					Name:            "f",
 				},
 				&object.Integer{Value: 24},
 			},
@@ -581,6 +682,7 @@ func Test_Compiler(t *testing.T) {
 						Build(),
 					LocalsCount:     3,
 					ParametersCount: 3,
+					Name:            "f",
 				},
 				&object.Integer{Value: 2},
 				&object.Integer{Value: 4},
@@ -607,6 +709,25 @@ func Test_Compiler(t *testing.T) {
 				Make(code.OpPop).
 				Build(),
 		},
+		{
+			code: "let a = [1]; a[0] = a = 2",
+			expectedConstants: []object.Object{
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+				&object.Integer{Value: 0},
+				&object.Integer{Value: 2},
+			},
+			expectedInstructions: code.NewBuilder().
+				Make(code.OpConstant, 0).
+				Make(code.OpSetGlobal, 0).
+				Make(code.OpGetGlobal, 0).
+				Make(code.OpConstant, 1).
+				Make(code.OpConstant, 2).
+				Make(code.OpSetGlobal, 0).
+				Make(code.OpGetGlobal, 0).
+				Make(code.OpSetIndex).
+				Make(code.OpPop).
+				Build(),
+		},
 		{
 			code: `
 				fn (a) {
@@ -761,12 +882,57 @@ func Test_Compiler(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.code, func(t *testing.T) {
 			bytecode := compileCode(t, testCase.code)
-			assert.Equal(t, testCase.expectedConstants, bytecode.Constants)
+			assert.Equal(t, testCase.expectedConstants, withoutSourceMaps(bytecode.Constants))
 			assert.Equal(t, testCase.expectedInstructions.String(), bytecode.Instructions.String())
 		})
 	}
 }
 
+func Test_Compiler_localNames(t *testing.T) {
+	bytecode := compileCode(t, "let g = 1;\nfn(a, b) { let c = a + g; let a = c; c }")
+
+	function := bytecode.Constants[1].(*object.CompiledFunction)
+	assert.Equal(t, []string{"", "b", "c", "a"}, function.LocalNames)
+}
+
+func Test_Compiler_sourceMap(t *testing.T) {
+	bytecode := compileCode(t, "let x = 1;\nlet f = fn() {\n  x + 2\n};")
+
+	function := bytecode.Constants[2].(*object.CompiledFunction)
+	assert.Equal(t, code.SourceMap{
+		{Offset: 0, Position: lexer.Position{Line: 1, Column: 9, Offset: 8}},
+		{Offset: 3, Position: lexer.Position{Line: 1, Column: 1, Offset: 0}},
+		{Offset: 6, Position: lexer.Position{Line: 2, Column: 9, Offset: 19}},
+		{Offset: 10, Position: lexer.Position{Line: 2, Column: 1, Offset: 11}},
+	}, bytecode.SourceMap)
+	assert.Equal(t, code.SourceMap{
+		{Offset: 0, Position: lexer.Position{Line: 3, Column: 3, Offset: 28}},
+		{Offset: 3, Position: lexer.Position{Line: 3, Column: 7, Offset: 32}},
+		{Offset: 6, Position: lexer.Position{Line: 3, Column: 5, Offset: 30}},
+		{Offset: 7, Position: lexer.Position{Line: 3, Column: 3, Offset: 28}},
+	}, function.SourceMap)
+	assert.Equal(t, 2, function.Line)
+}
+
+// withoutSourceMaps copies constants so compiled functions can be compared
+// by their instructions, source maps, local names and lines are covered by
+// Test_Compiler_sourceMap and Test_Compiler_localNames.
+func withoutSourceMaps(constants []object.Object) []object.Object {
+	result := make([]object.Object, len(constants))
+	for i, constant := range constants {
+		if function, ok := constant.(*object.CompiledFunction); ok {
+			copied := *function
+			copied.SourceMap = nil
+			copied.LocalNames = nil
+			copied.Line = 0
+			constant = &copied
+		}
+		result[i] = constant
+	}
+
+	return result
+}
+
 func compileCode(t *testing.T, input string) *Bytecode {
 	l := lexer.New(strings.NewReader(input))
 	p := parser.New(l)
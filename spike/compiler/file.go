@@ -0,0 +1,83 @@
+package compiler
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+// fileMagic starts every file written by WriteFile, so other files are
+// rejected before decoding them.
+const fileMagic = "spike-bytecode-1\n"
+
+func init() {
+	gob.Register(&object.Integer{})
+	gob.Register(&object.String{})
+	gob.Register(&object.Array{})
+	gob.Register(&object.Hash{})
+	gob.Register(&object.Enum{})
+	gob.Register(&object.CompiledFunction{})
+}
+
+// file is what a compiled program is stored as. Globals keep the names of
+// the top-level definitions, so functions like main can still be looked up.
+type file struct {
+	Bytecode *Bytecode
+	Globals  []Symbol
+}
+
+// WriteFile stores bytecode compiled with symbolTable for running it later
+// without the source.
+func WriteFile(w io.Writer, bytecode *Bytecode, symbolTable *SymbolTable) error {
+	_, err := io.WriteString(w, fileMagic)
+	if err != nil {
+		return err
+	}
+
+	globals := []Symbol{}
+	for _, symbol := range symbolTable.Symbols() {
+		if symbol.SymbolScope == GlobalScope {
+			globals = append(globals, symbol)
+		}
+	}
+
+	return gob.NewEncoder(w).Encode(&file{Bytecode: bytecode, Globals: globals})
+}
+
+// ReadFile loads a program stored by WriteFile, along with a symbol table
+// holding its globals and the builtins.
+func ReadFile(r io.Reader) (*Bytecode, *SymbolTable, error) {
+	reader := bufio.NewReader(r)
+	magic := make([]byte, len(fileMagic))
+	_, err := io.ReadFull(reader, magic)
+	if err != nil || string(magic) != fileMagic {
+		return nil, nil, errors.New("not a spike bytecode file")
+	}
+
+	var decoded file
+	err = gob.NewDecoder(reader).Decode(&decoded)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "corrupt bytecode file")
+	}
+
+	symbolTable := NewSymbolTable()
+	for i, builtin := range object.Builtins {
+		symbolTable.DefineBuiltin(i, builtin.Name)
+	}
+	for _, symbol := range decoded.Globals {
+		symbolTable.store[symbol.Name] = symbol
+		if symbol.Index >= symbolTable.numDefinitions {
+			symbolTable.numDefinitions = symbol.Index + 1
+		}
+	}
+
+	return decoded.Bytecode, symbolTable, nil
+}
+
+// IsFile tells whether the contents start like a file written by WriteFile.
+func IsFile(contents []byte) bool {
+	return len(contents) >= len(fileMagic) && string(contents[:len(fileMagic)]) == fileMagic
+}
@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"bytes"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteFile_ReadFile(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(
+		"enum Color { Red, Green };\nlet x = [1, \"a\", {\"b\": [2]}];\nlet x = 2;\nlet main = fn(args) { len(args) + x };",
+	))).ParseProgram()
+	assert.NoError(t, err)
+
+	compiler := New()
+	assert.NoError(t, compiler.Compile(program))
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, WriteFile(buffer, compiler.Bytecode(), compiler.SymbolTable()))
+	assert.True(t, IsFile(buffer.Bytes()))
+
+	bytecode, symbolTable, err := ReadFile(buffer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.Bytecode(), bytecode)
+	assert.Equal(t, compiler.SymbolTable().Symbols(), symbolTable.Symbols())
+	assert.Equal(t, Symbol{Name: "y", SymbolScope: GlobalScope, Index: 4}, symbolTable.Define("y"))
+}
+
+func Test_ReadFile_rejectsOtherFiles(t *testing.T) {
+	_, _, err := ReadFile(strings.NewReader("let x = 1;"))
+
+	assert.EqualError(t, err, "not a spike bytecode file")
+	assert.False(t, IsFile([]byte("let x = 1;")))
+}
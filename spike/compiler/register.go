@@ -0,0 +1,549 @@
+package compiler
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/code"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+)
+
+// WithRegisters makes the compiler generate code for the experimental
+// register machine, vm.RegisterVM, into Bytecode.Registers instead of stack
+// instructions. Only integers, strings, booleans, operators, conditionals,
+// let, assignment and calls of functions without free variables are
+// supported, anything else fails to compile.
+func WithRegisters() Option {
+	return func(compiler *Compiler) {
+		main := &registerScope{next: 1, count: 1}
+		main.emit(code.MakeRegister(code.RegLoadNull, 0, 0, 0))
+
+		compiler.registers = &registerGenerator{
+			compiler: compiler,
+			scopes:   []*registerScope{main},
+		}
+	}
+}
+
+var registerOperators = map[string]code.RegisterOpcode{
+	"+":  code.RegAdd,
+	"-":  code.RegSub,
+	"*":  code.RegMul,
+	"/":  code.RegDiv,
+	"==": code.RegEqual,
+	"!=": code.RegNotEqual,
+	">":  code.RegGreaterThan,
+	"<":  code.RegLessThan,
+}
+
+// registerScope is the code of one function. Locals live in the registers
+// numbered by their symbol index, the values of expressions in the registers
+// after them.
+type registerScope struct {
+	instructions code.RegisterInstructions
+	// next is the first free register.
+	next int
+	// count is how many registers the code needs.
+	count int
+}
+
+func (scope *registerScope) emit(instruction code.RegisterInstruction) int {
+	scope.instructions = append(scope.instructions, instruction)
+
+	return len(scope.instructions) - 1
+}
+
+// registerGenerator compiles for the register machine. The main program keeps
+// the value of its last expression statement in register 0.
+type registerGenerator struct {
+	compiler *Compiler
+	scopes   []*registerScope
+}
+
+func (generator *registerGenerator) scope() *registerScope {
+	return generator.scopes[len(generator.scopes)-1]
+}
+
+func (generator *registerGenerator) emit(instruction code.RegisterInstruction) int {
+	return generator.scope().emit(instruction)
+}
+
+// patchJump points the jump at index to the next instruction emitted.
+func (generator *registerGenerator) patchJump(index int) {
+	scope := generator.scope()
+	jump := scope.instructions[index]
+	scope.instructions[index] = code.MakeRegisterWide(jump.Opcode(), jump.A(), len(scope.instructions))
+}
+
+func (generator *registerGenerator) allocate(node ast.Node) (int, error) {
+	scope := generator.scope()
+	if scope.next >= code.MaxRegisters {
+		return 0, diagnostic.NewCompileError(node, diagnostic.Unsupported, "expression needs more than %d registers", code.MaxRegisters)
+	}
+
+	register := scope.next
+	scope.next++
+	if scope.next > scope.count {
+		scope.count = scope.next
+	}
+
+	return register, nil
+}
+
+// release frees the registers allocated since next was mark.
+func (generator *registerGenerator) release(mark int) {
+	generator.scope().next = mark
+}
+
+func (generator *registerGenerator) bytecode() *Bytecode {
+	main := generator.scopes[0]
+	instructions := append(code.RegisterInstructions{}, main.instructions...)
+
+	return &Bytecode{
+		Constants:      generator.compiler.constants,
+		Registers:      append(instructions, code.MakeRegister(code.RegReturn, 0, 0, 0)),
+		RegistersCount: main.count,
+	}
+}
+
+func (generator *registerGenerator) compile(node ast.Node) error {
+	program, ok := node.(*ast.Program)
+	if !ok {
+		return unsupportedByRegisters(node)
+	}
+
+	for _, statement := range program.Statements {
+		if expression, ok := statement.(*ast.ExpressionStatement); ok {
+			err := generator.into(expression.Expression, 0)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := generator.statement(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (generator *registerGenerator) statement(node ast.Statement) error {
+	mark := generator.scope().next
+	defer generator.release(mark)
+
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		_, err := generator.value(node.Expression)
+		return err
+
+	case *ast.BlockStatement:
+		for _, statement := range node.Statements {
+			err := generator.statement(statement)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case *ast.LetStatement:
+		// The value sees the binding the name shadows, if there is one, so
+		// when it reads the name it is compiled before defining it. Otherwise
+		// a local's value goes straight into its register, and a function
+		// can call itself.
+		if !refersTo(node.Value, node.Name.Value) {
+			symbol := generator.compiler.symbolTable.Define(node.Name.Value)
+			if symbol.SymbolScope == LocalScope {
+				return generator.into(node.Value, symbol.Index)
+			}
+
+			register, err := generator.value(node.Value)
+			if err != nil {
+				return err
+			}
+			generator.emit(code.MakeRegisterWide(code.RegSetGlobal, register, symbol.Index))
+
+			return nil
+		}
+
+		register, err := generator.value(node.Value)
+		if err != nil {
+			return err
+		}
+
+		symbol := generator.compiler.symbolTable.Define(node.Name.Value)
+		if symbol.SymbolScope == LocalScope {
+			generator.emit(code.MakeRegister(code.RegMove, symbol.Index, register, 0))
+		} else {
+			generator.emit(code.MakeRegisterWide(code.RegSetGlobal, register, symbol.Index))
+		}
+
+		return nil
+
+	case *ast.ReturnStatement:
+		var register int
+		var err error
+		if node.Result == nil {
+			register, err = generator.allocate(node)
+			if err == nil {
+				generator.emit(code.MakeRegister(code.RegLoadNull, register, 0, 0))
+			}
+		} else {
+			register, err = generator.value(node.Result)
+		}
+		if err != nil {
+			return err
+		}
+		generator.emit(code.MakeRegister(code.RegReturn, register, 0, 0))
+
+		return nil
+	}
+
+	return unsupportedByRegisters(node)
+}
+
+// value compiles node and returns the register holding its value, which is the
+// local's own register for local identifiers.
+func (generator *registerGenerator) value(node ast.Expression) (int, error) {
+	if identifier, ok := node.(*ast.Identifier); ok {
+		symbol, ok := generator.compiler.symbolTable.Resolve(identifier.Value)
+		if ok && symbol.SymbolScope == LocalScope {
+			return symbol.Index, nil
+		}
+	}
+
+	register, err := generator.allocate(node)
+	if err != nil {
+		return 0, err
+	}
+
+	return register, generator.into(node, register)
+}
+
+// operand is value for an operand evaluated before later, which gets a copy of
+// a local identifier that later assigns to, so it keeps the value it had.
+func (generator *registerGenerator) operand(node ast.Expression, later ast.Expression) (int, error) {
+	identifier, ok := node.(*ast.Identifier)
+	if !ok || !assigns(later, identifier.Value) {
+		return generator.value(node)
+	}
+
+	register, err := generator.allocate(node)
+	if err != nil {
+		return 0, err
+	}
+
+	return register, generator.into(node, register)
+}
+
+// into compiles node to leave its value in register target.
+func (generator *registerGenerator) into(node ast.Expression, target int) error {
+	mark := generator.scope().next
+	defer generator.release(mark)
+
+	switch node := node.(type) {
+	case *ast.Integer:
+		index := generator.compiler.addConstant(&object.Integer{Value: node.Value})
+		generator.emit(code.MakeRegisterWide(code.RegLoadConstant, target, index))
+
+	case *ast.String:
+		index := generator.compiler.addConstant(&object.String{Value: node.Value})
+		generator.emit(code.MakeRegisterWide(code.RegLoadConstant, target, index))
+
+	case *ast.Boolean:
+		if node.Value {
+			generator.emit(code.MakeRegister(code.RegLoadTrue, target, 0, 0))
+		} else {
+			generator.emit(code.MakeRegister(code.RegLoadFalse, target, 0, 0))
+		}
+
+	case *ast.Identifier:
+		symbol, err := generator.resolve(node)
+		if err != nil {
+			return err
+		}
+
+		if symbol.SymbolScope == GlobalScope {
+			generator.emit(code.MakeRegisterWide(code.RegGetGlobal, target, symbol.Index))
+		} else if symbol.Index != target {
+			generator.emit(code.MakeRegister(code.RegMove, target, symbol.Index, 0))
+		}
+
+	case *ast.PrefixExpression:
+		right, err := generator.value(node.Right)
+		if err != nil {
+			return err
+		}
+
+		switch node.Operator {
+		case "!":
+			generator.emit(code.MakeRegister(code.RegBang, target, right, 0))
+		case "-":
+			generator.emit(code.MakeRegister(code.RegMinus, target, right, 0))
+		default:
+			return diagnostic.NewCompileError(node, diagnostic.UnknownOperator, "invalid prefix operator: %s", node.Operator)
+		}
+
+	case *ast.InfixExpression:
+		opcode, ok := registerOperators[node.Operator]
+		if !ok {
+			return diagnostic.NewCompileError(node, diagnostic.UnknownOperator, "unknown operator: %s", node.Operator)
+		}
+
+		left, err := generator.operand(node.Left, node.Right)
+		if err != nil {
+			return err
+		}
+
+		right, err := generator.value(node.Right)
+		if err != nil {
+			return err
+		}
+
+		generator.emit(code.MakeRegister(opcode, target, left, right))
+
+	case *ast.IfExpression:
+		condition, err := generator.value(node.Condition)
+		if err != nil {
+			return err
+		}
+		generator.release(mark)
+
+		jumpNotTrue := generator.emit(code.MakeRegisterWide(code.RegJumpNotTrue, condition, 0))
+
+		err = generator.block(node.Then, target)
+		if err != nil {
+			return err
+		}
+
+		jump := generator.emit(code.MakeRegisterWide(code.RegJump, 0, 0))
+		generator.patchJump(jumpNotTrue)
+
+		if node.Else == nil {
+			generator.emit(code.MakeRegister(code.RegLoadNull, target, 0, 0))
+		} else {
+			err = generator.block(node.Else, target)
+			if err != nil {
+				return err
+			}
+		}
+
+		generator.patchJump(jump)
+
+	case *ast.FunctionExpression:
+		function, err := generator.function(node)
+		if err != nil {
+			return err
+		}
+
+		index := generator.compiler.addConstant(function)
+		generator.emit(code.MakeRegisterWide(code.RegLoadConstant, target, index))
+
+	case *ast.CallExpression:
+		callee, err := generator.allocate(node)
+		if err != nil {
+			return err
+		}
+
+		err = generator.into(node.Function, callee)
+		if err != nil {
+			return err
+		}
+
+		for _, argument := range node.Arguments {
+			register, err := generator.allocate(argument)
+			if err != nil {
+				return err
+			}
+
+			err = generator.into(argument, register)
+			if err != nil {
+				return err
+			}
+		}
+
+		generator.emit(code.MakeRegister(code.RegCall, target, callee, len(node.Arguments)))
+
+	case *ast.AssignExpression:
+		return generator.assign(node, target)
+
+	default:
+		return unsupportedByRegisters(node)
+	}
+
+	return nil
+}
+
+// block compiles a then, else or function body to leave the value of its last
+// expression statement, or null, in register target.
+func (generator *registerGenerator) block(node ast.Statement, target int) error {
+	block, ok := node.(*ast.BlockStatement)
+	if !ok {
+		return unsupportedByRegisters(node)
+	}
+
+	for i, statement := range block.Statements {
+		if expression, ok := statement.(*ast.ExpressionStatement); ok && i == len(block.Statements)-1 {
+			return generator.into(expression.Expression, target)
+		}
+
+		err := generator.statement(statement)
+		if err != nil {
+			return err
+		}
+	}
+
+	generator.emit(code.MakeRegister(code.RegLoadNull, target, 0, 0))
+
+	return nil
+}
+
+func (generator *registerGenerator) assign(node *ast.AssignExpression, target int) error {
+	identifier, ok := node.Target.(*ast.Identifier)
+	if !ok {
+		return unsupportedByRegisters(node)
+	}
+
+	symbol, err := generator.resolve(identifier)
+	if err != nil {
+		return err
+	}
+
+	register := symbol.Index
+	if symbol.SymbolScope == GlobalScope {
+		register, err = generator.value(node.Value)
+		if err != nil {
+			return err
+		}
+		generator.emit(code.MakeRegisterWide(code.RegSetGlobal, register, symbol.Index))
+	} else {
+		err = generator.into(node.Value, register)
+		if err != nil {
+			return err
+		}
+	}
+
+	if register != target {
+		generator.emit(code.MakeRegister(code.RegMove, target, register, 0))
+	}
+
+	return nil
+}
+
+func (generator *registerGenerator) function(node *ast.FunctionExpression) (*object.CompiledFunction, error) {
+	if node.Generator {
+		return nil, unsupportedByRegisters(node)
+	}
+
+	localsCount := len(node.Parameters) + countLets(node.Body)
+	if localsCount >= code.MaxRegisters {
+		return nil, diagnostic.NewCompileError(node, diagnostic.Unsupported, "function needs more than %d registers", code.MaxRegisters)
+	}
+
+	compiler := generator.compiler
+	compiler.symbolTable = NewEnclosedSymbolTable(compiler.symbolTable)
+	generator.scopes = append(generator.scopes, &registerScope{next: localsCount, count: localsCount})
+	defer func() {
+		compiler.symbolTable = compiler.symbolTable.Outer
+		generator.scopes = generator.scopes[:len(generator.scopes)-1]
+	}()
+
+	for _, parameter := range node.Parameters {
+		compiler.symbolTable.Define(parameter.Value)
+	}
+
+	result, err := generator.allocate(node)
+	if err != nil {
+		return nil, err
+	}
+
+	err = generator.block(node.Body, result)
+	if err != nil {
+		return nil, err
+	}
+	generator.emit(code.MakeRegister(code.RegReturn, result, 0, 0))
+
+	return &object.CompiledFunction{
+		LocalsCount:     localsCount,
+		ParametersCount: len(node.Parameters),
+		Name:            node.Name,
+		Doc:             node.Doc(),
+		Line:            node.Pos().Line,
+		Registers:       generator.scope().instructions,
+		RegistersCount:  generator.scope().count,
+	}, nil
+}
+
+// resolve looks up identifier, which the register machine can only load from
+// globals and locals of the function being compiled.
+func (generator *registerGenerator) resolve(identifier *ast.Identifier) (Symbol, error) {
+	symbol, ok := generator.compiler.symbolTable.Resolve(identifier.Value)
+	if !ok {
+		return symbol, diagnostic.NewCompileError(identifier, diagnostic.UnresolvedIdentifier, "unable to resolve identifier: %s", identifier.Value)
+	}
+
+	switch symbol.SymbolScope {
+	case BuiltinScope:
+		return symbol, diagnostic.NewCompileError(identifier, diagnostic.Unsupported, "the register machine does not support builtins yet: %s", identifier.Value)
+	case FreeScope:
+		return symbol, diagnostic.NewCompileError(identifier, diagnostic.Unsupported, "the register machine does not support free variables yet: %s", identifier.Value)
+	}
+
+	return symbol, nil
+}
+
+// countLets counts the let statements of a function body, not of the
+// functions in it, which is at most how many locals they define.
+func countLets(body ast.Node) int {
+	count := 0
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.LetStatement:
+			count++
+		case *ast.FunctionExpression:
+			return false
+		}
+
+		return true
+	})
+
+	return count
+}
+
+// refersTo reports whether node reads name outside the functions defined in
+// it.
+func refersTo(node ast.Node, name string) bool {
+	found := false
+	inspectBody(node, func(node ast.Node) {
+		if identifier, ok := node.(*ast.Identifier); ok && identifier.Value == name {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// assigns reports whether node assigns to name outside the functions defined
+// in it.
+func assigns(node ast.Node, name string) bool {
+	found := false
+	inspectBody(node, func(node ast.Node) {
+		if assignment, ok := node.(*ast.AssignExpression); ok {
+			if identifier, ok := assignment.Target.(*ast.Identifier); ok && identifier.Value == name {
+				found = true
+			}
+		}
+	})
+
+	return found
+}
+
+func unsupportedByRegisters(node ast.Node) error {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+
+	return diagnostic.NewCompileError(node, diagnostic.Unsupported, "the register machine does not support %s yet", name)
+}
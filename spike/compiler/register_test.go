@@ -0,0 +1,140 @@
+package compiler
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Compiler_withRegisters(t *testing.T) {
+	testCases := []struct {
+		code                 string
+		expectedInstructions string
+		expectedRegisters    int
+	}{
+		{
+			code: "1 + 2 * 3",
+			expectedInstructions: `0000 RegLoadNull 0
+0001 RegLoadConstant 1 0
+0002 RegLoadConstant 3 1
+0003 RegLoadConstant 4 2
+0004 RegMul 2 3 4
+0005 RegAdd 0 1 2
+0006 RegReturn 0
+`,
+			expectedRegisters: 5,
+		},
+		{
+			code: "let a = true; if (!a) { 1 } else { -2 }",
+			expectedInstructions: `0000 RegLoadNull 0
+0001 RegLoadTrue 1
+0002 RegSetGlobal 1 0
+0003 RegGetGlobal 2 0
+0004 RegBang 1 2
+0005 RegJumpNotTrue 1 8
+0006 RegLoadConstant 0 0
+0007 RegJump 10
+0008 RegLoadConstant 1 1
+0009 RegMinus 0 1
+0010 RegReturn 0
+`,
+			expectedRegisters: 3,
+		},
+		{
+			code: "let f = fn(a) { a }; f(1)",
+			expectedInstructions: `0000 RegLoadNull 0
+0001 RegLoadConstant 1 0
+0002 RegSetGlobal 1 0
+0003 RegGetGlobal 1 0
+0004 RegLoadConstant 2 1
+0005 RegCall 0 1 1
+0006 RegReturn 0
+`,
+			expectedRegisters: 3,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			bytecode, err := compileRegisters(testCase.code)
+
+			assert.NoError(t, err)
+			assert.Nil(t, bytecode.Instructions)
+			assert.Equal(t, testCase.expectedInstructions, bytecode.Registers.String())
+			assert.Equal(t, testCase.expectedRegisters, bytecode.RegistersCount)
+		})
+	}
+}
+
+func Test_Compiler_withRegistersFunction(t *testing.T) {
+	bytecode, err := compileRegisters("fn(a, b) {\n  let c = a * b;\n  c = c + a;\n  return c\n}")
+	assert.NoError(t, err)
+
+	function := bytecode.Constants[0].(*object.CompiledFunction)
+	assert.Equal(t, `0000 RegMul 2 0 1
+0001 RegAdd 2 2 0
+0002 RegMove 4 2
+0003 RegReturn 2
+0004 RegLoadNull 3
+0005 RegReturn 3
+`, function.Registers.String())
+	assert.Equal(t, 5, function.RegistersCount)
+	assert.Equal(t, 2, function.ParametersCount)
+	assert.Equal(t, 1, function.Line)
+	assert.Nil(t, function.Instructions)
+}
+
+func Test_Compiler_withRegistersUnsupported(t *testing.T) {
+	testCases := []struct {
+		code          string
+		expectedError string
+	}{
+		{
+			code:          "[1, 2]",
+			expectedError: "the register machine does not support Array yet at 1:1",
+		},
+		{
+			code:          "len(\"a\")",
+			expectedError: "the register machine does not support builtins yet: len at 1:1",
+		},
+		{
+			code:          "fn(a) { fn() { a } }",
+			expectedError: "the register machine does not support free variables yet: a at 1:16",
+		},
+		{
+			code:          "fn*() { yield 1 }",
+			expectedError: "the register machine does not support FunctionExpression yet at 1:1",
+		},
+		{
+			code:          "x",
+			expectedError: "unable to resolve identifier: x at 1:1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			_, err := compileRegisters(testCase.code)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func compileRegisters(input string) (*Bytecode, error) {
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := New(WithRegisters())
+	err = compiler.Compile(program)
+	if err != nil {
+		return nil, err
+	}
+
+	return compiler.Bytecode(), nil
+}
@@ -1,5 +1,7 @@
 package compiler
 
+import "sort"
+
 type SymbolScope string
 
 const (
@@ -50,6 +52,23 @@ func (symbolTable *SymbolTable) Define(name string) Symbol {
 	return symbol
 }
 
+// Copy returns a table with the same symbols and the same outer table.
+// Defining names in the copy leaves this table alone, so a program can be
+// compiled against it and its definitions kept only once it ran.
+func (symbolTable *SymbolTable) Copy() *SymbolTable {
+	store := make(map[string]Symbol, len(symbolTable.store))
+	for name, symbol := range symbolTable.store {
+		store[name] = symbol
+	}
+
+	return &SymbolTable{
+		Outer:          symbolTable.Outer,
+		FreeSymbols:    append([]Symbol{}, symbolTable.FreeSymbols...),
+		store:          store,
+		numDefinitions: symbolTable.numDefinitions,
+	}
+}
+
 func (symbolTable *SymbolTable) DefineBuiltin(index int, name string) {
 	symbol := Symbol{Name: name, Index: index, SymbolScope: BuiltinScope}
 	symbolTable.store[name] = symbol
@@ -87,3 +106,92 @@ func (symbolTable *SymbolTable) defineFree(original Symbol) Symbol {
 
 	return symbol
 }
+
+// Lookup resolves name like Resolve does, but never records free variables,
+// so it is safe to call from tooling without changing what gets compiled.
+func (symbolTable *SymbolTable) Lookup(name string) (Symbol, bool) {
+	for table := symbolTable; table != nil; table = table.Outer {
+		if symbol, ok := table.store[name]; ok {
+			return symbol, true
+		}
+	}
+
+	return Symbol{}, false
+}
+
+// Symbols returns the symbols defined directly in this table, sorted by name.
+func (symbolTable *SymbolTable) Symbols() []Symbol {
+	symbols := make([]Symbol, 0, len(symbolTable.store))
+	for _, symbol := range symbolTable.store {
+		symbols = append(symbols, symbol)
+	}
+	sortSymbols(symbols)
+
+	return symbols
+}
+
+// Visible returns every symbol that can be referenced from this table,
+// with inner definitions shadowing outer ones, sorted by name.
+func (symbolTable *SymbolTable) Visible() []Symbol {
+	seen := make(map[string]bool)
+	symbols := make([]Symbol, 0)
+
+	for table := symbolTable; table != nil; table = table.Outer {
+		for name, symbol := range table.store {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	sortSymbols(symbols)
+
+	return symbols
+}
+
+// localNames returns the names of the locals defined in this table by index.
+// Names defined again leave the slot of their earlier definition unnamed.
+func (symbolTable *SymbolTable) localNames() []string {
+	names := make([]string, symbolTable.numDefinitions)
+	for name, symbol := range symbolTable.store {
+		if symbol.SymbolScope == LocalScope {
+			names[symbol.Index] = name
+		}
+	}
+
+	return names
+}
+
+// FreeVariables returns the outer symbols captured by this scope, in the
+// order they are loaded onto the closure.
+func (symbolTable *SymbolTable) FreeVariables() []Symbol {
+	free := make([]Symbol, len(symbolTable.FreeSymbols))
+	copy(free, symbolTable.FreeSymbols)
+
+	return free
+}
+
+// Scopes returns the chain of tables from this one out to the global table.
+func (symbolTable *SymbolTable) Scopes() []*SymbolTable {
+	scopes := make([]*SymbolTable, 0)
+	for table := symbolTable; table != nil; table = table.Outer {
+		scopes = append(scopes, table)
+	}
+
+	return scopes
+}
+
+func (symbolTable *SymbolTable) Depth() int {
+	return len(symbolTable.Scopes()) - 1
+}
+
+func (symbolTable *SymbolTable) DefinitionsCount() int {
+	return symbolTable.numDefinitions
+}
+
+func sortSymbols(symbols []Symbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Name < symbols[j].Name
+	})
+}
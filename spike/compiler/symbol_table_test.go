@@ -143,3 +143,73 @@ func Test_SymbolTable_resolveFreeVars(t *testing.T) {
 		},
 	}, local2.FreeSymbols)
 }
+
+func Test_SymbolTable_introspection(t *testing.T) {
+	global := NewSymbolTable()
+	global.DefineBuiltin(0, "len")
+	global.Define("b")
+	global.Define("a")
+
+	local1 := NewEnclosedSymbolTable(global)
+	local1.Define("c")
+
+	local2 := NewEnclosedSymbolTable(local1)
+	local2.Define("a")
+	local2.Resolve("c")
+
+	assert.Equal(t, []Symbol{
+		{Name: "a", SymbolScope: GlobalScope, Index: 1},
+		{Name: "b", SymbolScope: GlobalScope, Index: 0},
+		{Name: "len", SymbolScope: BuiltinScope, Index: 0},
+	}, global.Symbols())
+
+	assert.Equal(t, []Symbol{
+		{Name: "a", SymbolScope: LocalScope, Index: 0},
+		{Name: "b", SymbolScope: GlobalScope, Index: 0},
+		{Name: "c", SymbolScope: FreeScope, Index: 0},
+		{Name: "len", SymbolScope: BuiltinScope, Index: 0},
+	}, local2.Visible())
+
+	assert.Equal(t, []Symbol{
+		{Name: "c", SymbolScope: LocalScope, Index: 0},
+	}, local2.FreeVariables())
+
+	assert.Equal(t, []*SymbolTable{local2, local1, global}, local2.Scopes())
+	assert.Equal(t, 2, local2.Depth())
+	assert.Equal(t, 0, global.Depth())
+	assert.Equal(t, 2, global.DefinitionsCount())
+}
+
+func Test_SymbolTable_Lookup_doesNotCaptureFreeVariables(t *testing.T) {
+	global := NewSymbolTable()
+	local1 := NewEnclosedSymbolTable(global)
+	local1.Define("a")
+	local2 := NewEnclosedSymbolTable(local1)
+
+	symbol, ok := local2.Lookup("a")
+	assert.True(t, ok)
+	assert.Equal(t, Symbol{Name: "a", SymbolScope: LocalScope, Index: 0}, symbol)
+	assert.Empty(t, local2.FreeVariables())
+
+	_, ok = local2.Lookup("b")
+	assert.False(t, ok)
+}
+
+func Test_SymbolTable_Copy(t *testing.T) {
+	global := NewSymbolTable()
+	a := global.Define("a")
+
+	copied := global.Copy()
+	b := copied.Define("b")
+	copied.Define("a")
+
+	assert.Equal(t, Symbol{Name: "b", SymbolScope: GlobalScope, Index: 1}, b)
+	symbol, _ := copied.Resolve("a")
+	assert.Equal(t, Symbol{Name: "a", SymbolScope: GlobalScope, Index: 2}, symbol)
+
+	symbol, _ = global.Resolve("a")
+	assert.Equal(t, a, symbol)
+	_, ok := global.Resolve("b")
+	assert.False(t, ok)
+	assert.Equal(t, 1, global.DefinitionsCount())
+}
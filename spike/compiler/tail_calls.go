@@ -0,0 +1,106 @@
+package compiler
+
+import (
+	"sort"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/parser/ast"
+)
+
+// TailCalls reports the calls functions bound by let make to themselves: as
+// info when a call is in tail position, its result returned as it is, and as
+// a warning when it is not. Tail calls are not eliminated yet, every call
+// keeps a frame until it returns whatever its position, so the hints only
+// show which recursion could be rewritten to be in tail position.
+func TailCalls(program ast.Node) []*diagnostic.Hint {
+	hints := []*diagnostic.Hint{}
+	ast.Inspect(program, func(node ast.Node) bool {
+		if function, ok := node.(*ast.FunctionExpression); ok && function.Name != "" {
+			hints = append(hints, recursiveCalls(function)...)
+		}
+
+		return true
+	})
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Start.Offset < hints[j].Start.Offset
+	})
+
+	return hints
+}
+
+func recursiveCalls(function *ast.FunctionExpression) []*diagnostic.Hint {
+	for _, parameter := range function.Parameters {
+		if parameter.Value == function.Name {
+			return nil
+		}
+	}
+
+	tail := map[*ast.CallExpression]bool{}
+	markTailCalls(function.Body, tail)
+
+	calls := []*ast.CallExpression{}
+	shadowed := false
+	inspectBody(function.Body, func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			shadowed = shadowed || node.Name.Value == function.Name
+		case *ast.ReturnStatement:
+			if node.Result != nil {
+				markTailCalls(node.Result, tail)
+			}
+		case *ast.CallExpression:
+			if callee, ok := node.Function.(*ast.Identifier); ok && callee.Value == function.Name {
+				calls = append(calls, node)
+			}
+		}
+	})
+	if shadowed {
+		return nil
+	}
+
+	hints := make([]*diagnostic.Hint, len(calls))
+	for i, call := range calls {
+		if tail[call] {
+			hints[i] = diagnostic.NewHint(call, diagnostic.Info, diagnostic.TailCall, "recursive call of %s is in tail position, it still keeps a frame until it returns", function.Name)
+		} else {
+			hints[i] = diagnostic.NewHint(call, diagnostic.Warning, diagnostic.NonTailCall, "recursive call of %s is not in tail position", function.Name)
+		}
+	}
+
+	return hints
+}
+
+// markTailCalls marks the calls whose value is the value of node, which is in
+// tail position.
+func markTailCalls(node ast.Node, tail map[*ast.CallExpression]bool) {
+	switch node := node.(type) {
+	case *ast.BlockStatement:
+		if len(node.Statements) > 0 {
+			markTailCalls(node.Statements[len(node.Statements)-1], tail)
+		}
+	case *ast.ExpressionStatement:
+		markTailCalls(node.Expression, tail)
+	case *ast.IfExpression:
+		markTailCalls(node.Then, tail)
+		if node.Else != nil {
+			markTailCalls(node.Else, tail)
+		}
+	case *ast.CallExpression:
+		tail[node] = true
+	}
+}
+
+// inspectBody calls f for every node of a function body, leaving out the
+// functions defined in it.
+func inspectBody(body ast.Node, f func(ast.Node)) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FunctionExpression); ok {
+			return false
+		}
+		if node != nil {
+			f(node)
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,71 @@
+package compiler
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TailCalls(t *testing.T) {
+	testCases := []struct {
+		code          string
+		expectedHints []string
+	}{
+		{
+			code: "let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }",
+			expectedHints: []string{
+				"warning: recursive call of fib is not in tail position at 1:43",
+				"warning: recursive call of fib is not in tail position at 1:56",
+			},
+		},
+		{
+			code: "let sum = fn(n, acc) { if (n == 0) { acc } else { sum(n - 1, acc + n) } }",
+			expectedHints: []string{
+				"info: recursive call of sum is in tail position, it still keeps a frame until it returns at 1:51",
+			},
+		},
+		{
+			code: "let f = fn(n) { if (n > 0) { return f(n - 1) }; let x = f(0); x }",
+			expectedHints: []string{
+				"info: recursive call of f is in tail position, it still keeps a frame until it returns at 1:37",
+				"warning: recursive call of f is not in tail position at 1:57",
+			},
+		},
+		{
+			code: "let f = fn(n) { f(f(n)) }",
+			expectedHints: []string{
+				"info: recursive call of f is in tail position, it still keeps a frame until it returns at 1:17",
+				"warning: recursive call of f is not in tail position at 1:19",
+			},
+		},
+		{
+			code: "let f = fn(n) { let g = fn() { f(n) }; g() }",
+		},
+		{
+			code: "let f = fn(f) { f(1) }; let g = fn() { let g = 1; g() }",
+		},
+		{
+			code: "fn(n) { n }(1)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.code))).ParseProgram()
+			assert.NoError(t, err)
+
+			hints := []string{}
+			for _, hint := range TailCalls(program) {
+				hints = append(hints, hint.Error())
+			}
+
+			if testCase.expectedHints == nil {
+				testCase.expectedHints = []string{}
+			}
+			assert.Equal(t, testCase.expectedHints, hints)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package diagnostic
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is an error about a span of the source, from Start up to End. A zero
+// Start means the position is not known.
+type Error struct {
+	Message string
+	Code    Code
+	Start   lexer.Position
+	End     lexer.Position
+}
+
+func (err *Error) Error() string {
+	if err.Start.Line == 0 {
+		return err.Message
+	}
+
+	return fmt.Sprintf("%s at %s", err.Message, err.Start)
+}
+
+func (err *Error) ErrorCode() Code {
+	return err.Code
+}
+
+func (err *Error) Span() (lexer.Position, lexer.Position) {
+	return err.Start, err.End
+}
+
+// AtToken returns an error spanning token.
+func AtToken(token lexer.Token, format string, args ...interface{}) *Error {
+	return &Error{
+		Message: fmt.Sprintf(format, args...),
+		Start:   token.Position,
+		End:     token.End(),
+	}
+}
+
+// AtNode returns an error spanning node.
+func AtNode(node ast.Node, format string, args ...interface{}) *Error {
+	return &Error{
+		Message: fmt.Sprintf(format, args...),
+		Start:   node.Pos(),
+		End:     node.End(),
+	}
+}
+
+type spanned interface {
+	Span() (lexer.Position, lexer.Position)
+}
+
+// Render formats err for people. Errors that know their span in source are
+// followed by the source line and a ^~~~ underline of the span, any other
+// error is rendered as its message alone.
+func Render(source string, err error) string {
+	span, ok := spanOf(err)
+	if !ok {
+		return err.Error()
+	}
+
+	start, end := span.Span()
+	lines := strings.Split(source, "\n")
+	if start.Line < 1 || start.Line > len(lines) {
+		return err.Error()
+	}
+
+	line := strings.TrimSuffix(lines[start.Line-1], "\r")
+	return fmt.Sprintf("%s\n%s\n%s", err, line, underline(line, start, end))
+}
+
+// spanOf finds the first error knowing its span among err and its causes.
+func spanOf(err error) (spanned, bool) {
+	for err != nil {
+		if span, ok := err.(spanned); ok {
+			return span, true
+		}
+
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return nil, false
+		}
+		err = cause.Cause()
+	}
+
+	return nil, false
+}
+
+// underline marks the columns from start to end of line. Tabs before the span
+// are kept so the marks line up with the source however tabs are displayed.
+func underline(line string, start lexer.Position, end lexer.Position) string {
+	result := &strings.Builder{}
+	column := 1
+	for _, character := range line {
+		if column == start.Column {
+			break
+		}
+		if character == '\t' {
+			result.WriteRune('\t')
+		} else {
+			result.WriteRune(' ')
+		}
+		column++
+	}
+
+	width := utf8.RuneCountInString(line) - column + 1
+	if end.Line == start.Line && end.Column-start.Column < width {
+		width = end.Column - start.Column
+	}
+
+	result.WriteString("^")
+	if width > 1 {
+		result.WriteString(strings.Repeat("~", width-1))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,86 @@
+package diagnostic
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Render(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		err      error
+		expected string
+	}{
+		{
+			name:   "token",
+			source: "let x = 1;\nlet y = x + foo;",
+			err: AtToken(
+				lexer.Token{Type: lexer.Identifier, Literal: "foo", Position: lexer.Position{Line: 2, Column: 13, Offset: 23}},
+				"unable to resolve identifier: foo",
+			),
+			expected: "unable to resolve identifier: foo at 2:13\nlet y = x + foo;\n            ^~~",
+		},
+		{
+			name:   "single character",
+			source: "1 + ;",
+			err: AtToken(
+				lexer.Token{Type: lexer.Semicolon, Literal: ";", Position: lexer.Position{Line: 1, Column: 5, Offset: 4}},
+				`";" is not a valid prefix expression`,
+			),
+			expected: "\";\" is not a valid prefix expression at 1:5\n1 + ;\n    ^",
+		},
+		{
+			name:   "end of input",
+			source: "let x =",
+			err: AtToken(
+				lexer.Token{Type: lexer.Eof, Position: lexer.Position{Line: 1, Column: 8, Offset: 7}},
+				`"" is not a valid prefix expression`,
+			),
+			expected: "\"\" is not a valid prefix expression at 1:8\nlet x =\n       ^",
+		},
+		{
+			name:   "span across lines",
+			source: "f([1,\n2])",
+			err: &Error{
+				Message: "bad call",
+				Start:   lexer.Position{Line: 1, Column: 1},
+				End:     lexer.Position{Line: 2, Column: 4},
+			},
+			expected: "bad call at 1:1\nf([1,\n^~~~~",
+		},
+		{
+			name:     "tabs",
+			source:   "\tlet x = ~;",
+			err:      &lexer.Error{Message: `illegal character "~"`, Position: lexer.Position{Line: 1, Column: 10, Offset: 9}},
+			expected: "illegal character \"~\" at 1:10\n\tlet x = ~;\n\t        ^",
+		},
+		{
+			name:     "wrapped",
+			source:   "x\r\n",
+			err:      errors.Wrap(&Error{Message: "bad", Start: lexer.Position{Line: 1, Column: 1}, End: lexer.Position{Line: 1, Column: 2}}, "compile"),
+			expected: "compile: bad at 1:1\nx\n^",
+		},
+		{
+			name:     "without span",
+			source:   "x",
+			err:      errors.New("stack overflow"),
+			expected: "stack overflow",
+		},
+		{
+			name:     "line out of range",
+			source:   "x",
+			err:      &Error{Message: "bad", Start: lexer.Position{Line: 3, Column: 1}},
+			expected: "bad at 3:1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, Render(testCase.source, testCase.err))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package diagnostic
+
+import (
+	"context"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+
+	"github.com/pkg/errors"
+)
+
+// Phase is the step of running a script an error happened in.
+type Phase string
+
+const (
+	ParsePhase   Phase = "parse"
+	CompilePhase Phase = "compile"
+	RuntimePhase Phase = "runtime"
+)
+
+// Code tells kinds of errors apart without matching their messages.
+type Code string
+
+const (
+	InvalidCharacter     Code = "invalid-character"
+	InvalidSyntax        Code = "invalid-syntax"
+	UnknownOperator      Code = "unknown-operator"
+	UnresolvedIdentifier Code = "unresolved-identifier"
+	InvalidAssignment    Code = "invalid-assignment"
+	Unsupported          Code = "unsupported"
+	StepLimitExceeded    Code = "step-limit-exceeded"
+	Interrupted          Code = "interrupted"
+	NotPermitted         Code = "not-permitted"
+	RuntimeFailure       Code = "runtime-failure"
+	TailCall             Code = "tail-call"
+	NonTailCall          Code = "non-tail-call"
+)
+
+// Diagnostic is implemented by ParseError, CompileError and RuntimeError.
+type Diagnostic interface {
+	error
+	Phase() Phase
+	ErrorCode() Code
+	Span() (lexer.Position, lexer.Position)
+}
+
+// located is embedded by the typed errors, under a name that leaves Error to
+// the method.
+type located = Error
+
+// ParseError is a syntax error, or a character the lexer could not read.
+type ParseError struct {
+	located
+}
+
+func (err *ParseError) Phase() Phase {
+	return ParsePhase
+}
+
+// NewParseError returns an error spanning token.
+func NewParseError(token lexer.Token, code Code, format string, args ...interface{}) *ParseError {
+	err := AtToken(token, format, args...)
+	err.Code = code
+
+	return &ParseError{located: *err}
+}
+
+// FromLexer turns an error of the lexer into a ParseError, other errors, like
+// failing to read the input, are returned as they are.
+func FromLexer(err error) error {
+	lexerError, ok := err.(*lexer.Error)
+	if !ok {
+		return err
+	}
+
+	start, end := lexerError.Span()
+	return &ParseError{located: Error{Message: lexerError.Message, Code: InvalidCharacter, Start: start, End: end}}
+}
+
+type CompileError struct {
+	located
+}
+
+func (err *CompileError) Phase() Phase {
+	return CompilePhase
+}
+
+// NewCompileError returns an error spanning node.
+func NewCompileError(node ast.Node, code Code, format string, args ...interface{}) *CompileError {
+	err := AtNode(node, format, args...)
+	err.Code = code
+
+	return &CompileError{located: *err}
+}
+
+// RuntimeError is an error a running script failed with, Err is what the
+// engine or a builtin returned.
+type RuntimeError struct {
+	located
+	Err error
+}
+
+func (err *RuntimeError) Phase() Phase {
+	return RuntimePhase
+}
+
+func (err *RuntimeError) Cause() error {
+	return err.Err
+}
+
+func (err *RuntimeError) Unwrap() error {
+	return err.Err
+}
+
+// NewRuntimeError wraps err at position, which is left zero when it is not
+// known.
+func NewRuntimeError(err error, position lexer.Position) *RuntimeError {
+	return &RuntimeError{
+		located: Error{Message: err.Error(), Code: runtimeCode(err), Start: position, End: position},
+		Err:     err,
+	}
+}
+
+func runtimeCode(err error) Code {
+	switch cause := errors.Cause(err); cause.(type) {
+	case *object.StepLimitError:
+		return StepLimitExceeded
+	case *object.PermissionError:
+		return NotPermitted
+	default:
+		if cause == context.Canceled || cause == context.DeadlineExceeded {
+			return Interrupted
+		}
+
+		return RuntimeFailure
+	}
+}
+
+// Severity tells how much a Hint matters.
+type Severity string
+
+const (
+	Info    Severity = "info"
+	Warning Severity = "warning"
+)
+
+// Hint is a remark about the source that does not stop it from compiling or
+// running. It implements error only so Render can show it.
+type Hint struct {
+	located
+	Severity Severity
+}
+
+// NewHint returns a hint spanning node.
+func NewHint(node ast.Node, severity Severity, code Code, format string, args ...interface{}) *Hint {
+	err := AtNode(node, format, args...)
+	err.Code = code
+
+	return &Hint{located: *err, Severity: severity}
+}
+
+func (hint *Hint) Error() string {
+	return string(hint.Severity) + ": " + hint.located.Error()
+}
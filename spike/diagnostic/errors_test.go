@@ -0,0 +1,66 @@
+package diagnostic
+
+import (
+	"context"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FromLexer(t *testing.T) {
+	err := FromLexer(&lexer.Error{Message: `illegal character "~"`, Position: lexer.Position{Line: 1, Column: 9, Offset: 8}})
+
+	parseError, ok := err.(*ParseError)
+	assert.True(t, ok)
+	assert.Equal(t, ParsePhase, parseError.Phase())
+	assert.Equal(t, InvalidCharacter, parseError.ErrorCode())
+	assert.EqualError(t, err, `illegal character "~" at 1:9`)
+
+	readError := errors.New("read failed")
+	assert.Equal(t, readError, FromLexer(readError))
+}
+
+func Test_NewRuntimeError(t *testing.T) {
+	position := lexer.Position{Line: 2, Column: 3, Offset: 7}
+	testCases := []struct {
+		err          error
+		expectedCode Code
+	}{
+		{err: errors.New("index 1 out of range [0..0]"), expectedCode: RuntimeFailure},
+		{err: &object.StepLimitError{Limit: 10}, expectedCode: StepLimitExceeded},
+		{err: errors.Wrap(&object.PermissionError{Name: "exec"}, "call"), expectedCode: NotPermitted},
+		{err: context.DeadlineExceeded, expectedCode: Interrupted},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.err.Error(), func(t *testing.T) {
+			err := NewRuntimeError(testCase.err, position)
+
+			assert.Equal(t, RuntimePhase, err.Phase())
+			assert.Equal(t, testCase.expectedCode, err.ErrorCode())
+			assert.Equal(t, testCase.err, err.Err)
+			assert.EqualError(t, err, testCase.err.Error()+" at 2:3")
+		})
+	}
+
+	assert.EqualError(t, NewRuntimeError(errors.New("stack overflow"), lexer.Position{}), "stack overflow")
+	assert.Equal(t, "bad at 1:3\nx + 1\n  ^", Render("x + 1", NewRuntimeError(errors.New("bad"), lexer.Position{Line: 1, Column: 3})))
+}
+
+func Test_NewHint(t *testing.T) {
+	identifier := &ast.Identifier{
+		Token: lexer.Token{Type: lexer.Identifier, Literal: "f", Position: lexer.Position{Line: 1, Column: 5, Offset: 4}},
+		Value: "f",
+	}
+
+	hint := NewHint(identifier, Warning, NonTailCall, "recursive call of %s", "f")
+
+	assert.Equal(t, Warning, hint.Severity)
+	assert.Equal(t, NonTailCall, hint.ErrorCode())
+	assert.Equal(t, "recursive call of f", hint.Message)
+	assert.Equal(t, "warning: recursive call of f at 1:5\nx + f\n    ^", Render("x + f", hint))
+}
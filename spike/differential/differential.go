@@ -0,0 +1,6 @@
+// Package differential keeps the tree-walking evaluator and the VM in step.
+// Its tests run a corpus of programs on both engines through spike.Engine and
+// compare what the programs print, their results and their errors, so a
+// feature landing on one engine only fails the build until the other catches
+// up, or until the difference is recorded as a known one.
+package differential
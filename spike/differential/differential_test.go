@@ -0,0 +1,279 @@
+package differential
+
+import (
+	"spike-interpreter-go/spike"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// corpus holds programs both engines have to run alike.
+var corpus = []string{
+	// literals and operators
+	"1",
+	"-5",
+	"1 + 2 * 3 - 4 / 2",
+	"7 / 2",
+	"-(5 - 10)",
+	"1.5 + 2.25",
+	"true",
+	"!true",
+	"!!5",
+	"1 < 2",
+	"1 > 2",
+	"1 == 1",
+	"1 != 1",
+	"(1 < 2) == true",
+	"\"spi\" + \"ke\"",
+	"\"a\" < \"b\"",
+	"\"a\" * 3",
+	"[1, 2] + [3]",
+	"[1] + [\"a\"] + []",
+	"[1, 2] == [1, 2]",
+	"[1, [2]] != [1, [3]]",
+	"[1] == [\"1\"]",
+	"\"a\" == \"a\"",
+	"\"a\" != \"b\"",
+	"{} < {}",
+	"bigint(2) == 2",
+	"1 / 0",
+	"1 + true",
+	"-true",
+	"-\"a\"",
+	"true + false",
+	"true < false",
+	"[1] - [1]",
+	"\"a\" - \"b\"",
+	"bigint(\"123456789012345678901234567890\") * 2",
+	"bigint(1) + 1",
+	"bigint(1) / 0",
+	"bigint(\"12x\")",
+
+	// bindings and control flow
+	"let a = 5; a",
+	"let a = 5; a = a * 2; a",
+	"let a = 1; let b = a + 1; b",
+	"let a = 1;",
+	"let a = 1; let a = 2; a",
+	"let a = 1; let a = a + 1; a",
+	"let x = x + 1",
+	"x",
+	"x = 1",
+	"null",
+	"let f = fn() { let x = 1; x }; f(); x",
+	"if (true) { let y = 1 }; y",
+	"if (true) { 10 }",
+	"if (false) { 10 }",
+	"if (1 < 2) { 10 } else { 20 }",
+	"if (0) { 1 } else { 2 }",
+	"if (\"\") { 1 } else { 2 }",
+	"if ([]) { 1 } else { 2 }",
+	"let a, b = (1, 2); a + b",
+	"let a, b = [1]; a",
+	"enum Color { Red, Green }; Color.Red == Color.Red",
+	"enum Color { Red, Green }; Color.Red == Color.Green",
+	"enum Color { Red }; Color.Blue",
+	"exit(3)",
+	"exit(300)",
+	"return 1",
+	"return;",
+	"return 5; 6",
+
+	// functions and closures
+	"let add = fn(a, b) { a + b }; add(1, 2)",
+	"let f = fn() { }; f()",
+	"let f = fn() { return 1; 2 }; f()",
+	"let f = fn(n) { if (n > 3) { return n }; n + 10 }; f(1) + f(5)",
+	"let adder = fn(x) { fn(y) { x + y } }; adder(2)(3)",
+	"let counter = fn() { let n = 0; fn() { n = n + 1; n } }; let c = counter(); c(); c()",
+	"let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(15)",
+	"let f = fn(n) { if (n == 0) { return callDepth() }; f(n - 1) }; f(3)",
+	"let f = fn(a) { a }; f(1, 2)",
+	"let f = fn(a, b) { a }; f(1)",
+	"let x = 1; x()",
+	"fn(n) { n * 2 }(21)",
+	"let f = fn() { f() }; f()",
+
+	// arrays, strings, tuples and hashes
+	"[1, 2, 3][1]",
+	"[1, 2, 3][3]",
+	"[1, 2, 3][-1]",
+	"[][0]",
+	"[1][\"a\"]",
+	"let a = [1, [2, 3]]; a[1][0]",
+	"let a = [1, 2]; a[0] = 5; a",
+	"let a = freeze([1]); a[0] = 2",
+	"let a = [[1]][0]; a[0] = 2; [a, [[1]][0], [1, 2][1] + 1, [3] + [4]]",
+	"let f = fn() { freeze({\"a\": [1]}) }; f()[\"a\"][0] = 2",
+	"\"abc\"[1]",
+	"\"abc\"[5]",
+	"bytes(\"ab\")[1]",
+	"bytes(\"ab\")[2]",
+	"\"abc\"[\"a\"]",
+	"let s = \"a\"; s[0] = \"b\"",
+	"1[0]",
+	"(1, \"a\")",
+	"(1, 2)[0]",
+	"{\"a\": 1}[\"a\"]",
+	"{\"a\": 1}[\"b\"]",
+	"{1: \"one\", true: \"yes\"}[true]",
+	"{[1]: 2}",
+	"{\"a\": 1}[[1]]",
+	"let h = {\"a\": 1}; h[\"b\"] = 2; h",
+	"let h = {}; h[[1]] = 1",
+	"let a = [1]; a[0] = a; println(a); a",
+	"let h = {}; h[\"h\"] = h; h",
+	"let a = [1]; a[0] = a; let b = [1]; b[0] = b; [a == b, a != [a]]",
+	"let order = []; let f = fn(x) { order = push(order, x); x }; {f(\"b\"): f(1), f(\"a\"): f(2)}; order",
+
+	// builtins
+	"len(\"spike\")",
+	"len([1, 2])",
+	"len(1)",
+	"len(\"a\", \"b\")",
+	"first([1, 2])",
+	"last([1, 2])",
+	"rest([1, 2, 3])",
+	"first([])",
+	"rest([])",
+	"push([1], 2)",
+	"pop([1, 2])",
+	"slice([1, 2, 3], 1, 2)",
+	"map([1, 2, 3], fn(x) { x * 2 })",
+	"filter([1, 2, 3, 4], fn(x) { x > 2 })",
+	"filter([0, first([]), \"\", false, 1], fn(x) { x })",
+	"reduce([1, 2, 3], 0, fn(acc, x) { acc + x })",
+	"reduce([1], 0, \"f\")",
+	"keys({\"a\": 1, \"b\": 2})",
+	"values({\"a\": 1})",
+	"has({\"a\": 1}, \"a\")",
+	"delete({\"a\": 1}, \"a\")",
+	"merge({\"a\": 1}, {\"b\": 2})",
+	"deepCopy([[1]])",
+	"range(0, 5, 2)",
+	"range(1, 2, 0)",
+	"split(\"a,b\", \",\")",
+	"join([\"a\", \"b\"], \"-\")",
+	"upper(\"a\") + lower(\"B\")",
+	"trim(\" a \")",
+	"contains(\"spike\", \"pi\")",
+	"indexOf(\"spike\", \"k\")",
+	"replace(\"aaa\", \"a\", \"b\")",
+	"format(\"%d-%s\", 1, \"a\")",
+	"type(1)",
+	"type(\"a\")",
+	"type((1,))",
+	"type({})",
+	"type(len)",
+	"assert(1 == 1, \"x\")",
+	"assert(1 == 2, \"oops\")",
+	"forAll({\"x\": \"integer\"}, fn(x) { x + 1 > x })",
+	"forAll({\"x\": \"integer\"}, fn(x) {\n assert(x < 5, \"big\") })",
+	"let check = fn(x) { [1, 2][x] }; forAll({\"x\": {\"type\": \"integer\", \"min\": 0}}, fn(x) { check(x) })",
+	"forAll({\"s\": \"string\"}, fn(s) { exit(2) })",
+	"forAll({\"x\": 1}, fn(x) { true })",
+	"puts(\"hi\")",
+	"println(\"hi\")",
+	"print(1, 2); println(\"x\")",
+	"printf(\"%d\\n\", 5)",
+	"eprintln(\"e\")",
+
+	// iterators and generators
+	"let it = iter([1, 2]); next(it); next(it)",
+	"let g = fn*(n) { yield n; yield n + 1 }; let it = g(1); [next(it), next(it)]",
+	"let g = fn*() { yield 1 }; let it = g(); next(it); next(it)",
+	"let g = fn*() { let i = 0; yield i; i = i + 1; yield i }; map(iter(g()), fn(x) { x })",
+}
+
+// knownDifferences holds programs the engines are known to run differently,
+// with what each of them does. Once the engines agree on one, it belongs in
+// the corpus.
+var knownDifferences = []struct {
+	input string
+	vm    outcome
+	eval  outcome
+}{
+	// The VM compares hashes with nothing and values of different types only
+	// with null, or with an enum member on the left.
+	{
+		input: "{\"a\": 1} == {\"a\": 1}",
+		vm:    outcome{err: "unable to compare variables of type hash and hash at 1:10"},
+		eval:  outcome{result: "true"},
+	},
+	{
+		input: "1 == \"a\"",
+		vm:    outcome{err: "both operands must have same type, had: integer and string at 1:3"},
+		eval:  outcome{result: "false"},
+	},
+	{
+		input: "enum Color { Red }; 1 == Color.Red",
+		vm:    outcome{err: "both operands must have same type, had: integer and enumMember at 1:23"},
+		eval:  outcome{result: "false"},
+	},
+	// The compiler has no instructions for these operators.
+	{
+		input: "1 <= 2",
+		vm:    outcome{err: "unknown operator: <= at 1:1"},
+		eval:  outcome{result: "true"},
+	},
+	{
+		input: "true && false",
+		vm:    outcome{err: "unknown operator: && at 1:1"},
+		eval:  outcome{result: "false"},
+	},
+	// The VM does not know the names of globals at run time.
+	{
+		input: "if (false) { let y = 1 }; y",
+		vm:    outcome{err: "identifier used before it was defined at 1:27"},
+		eval:  outcome{err: "unable to resolve identifier: y at 1:27"},
+	},
+	// Every function is a closure on the VM.
+	{
+		input: "type(fn() { 1 })",
+		vm:    outcome{result: "\"closure\""},
+		eval:  outcome{result: "\"function\""},
+	},
+}
+
+// outcome is what running a program on an engine printed, and the result it
+// evaluated to or the error it failed with.
+type outcome struct {
+	output string
+	result string
+	err    string
+}
+
+func Test_Engines(t *testing.T) {
+	for _, input := range corpus {
+		t.Run(input, func(t *testing.T) {
+			assert.Equal(t, run(spike.VMEngine, input), run(spike.EvalEngine, input))
+		})
+	}
+}
+
+func Test_Engines_knownDifferences(t *testing.T) {
+	for _, testCase := range knownDifferences {
+		t.Run(testCase.input, func(t *testing.T) {
+			assert.Equal(t, testCase.vm, run(spike.VMEngine, testCase.input))
+			assert.Equal(t, testCase.eval, run(spike.EvalEngine, testCase.input))
+		})
+	}
+}
+
+func run(kind spike.EngineKind, input string) outcome {
+	output := &strings.Builder{}
+	engine := spike.NewEngine(
+		spike.WithEngine(kind),
+		spike.WithStdout(output),
+		spike.WithStderr(output),
+		spike.WithStdin(strings.NewReader("")),
+	)
+
+	result, err := engine.Eval(input)
+	if err != nil {
+		return outcome{output: output.String(), err: err.Error()}
+	}
+
+	return outcome{output: output.String(), result: result.Inspect()}
+}
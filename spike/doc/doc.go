@@ -0,0 +1,127 @@
+// Package doc extracts the documentation of scripts: the comments right above
+// their top-level functions and enums, and the strings functions start with.
+package doc
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+)
+
+// Entry documents one top-level definition.
+type Entry struct {
+	Name      string
+	Signature string
+	Doc       string
+	Position  lexer.Position
+}
+
+// Source extracts the entries of a whole source file.
+func Source(source string) ([]Entry, error) {
+	program, err := parser.New(lexer.New(strings.NewReader(source), lexer.WithComments()), parser.WithComments()).ParseProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	return Program(program), nil
+}
+
+// Program returns an entry for every function bound by a top-level let and
+// every top-level enum, in source order. Their documentation is the block of
+// comments ending on the line before them, followed for functions by the
+// string their body starts with. Comments are only found in programs parsed
+// with parser.WithComments.
+func Program(program *ast.Program) []Entry {
+	entries := []Entry{}
+	for _, statement := range program.Statements {
+		var entry Entry
+		switch statement := statement.(type) {
+		case *ast.LetStatement:
+			function, ok := statement.Value.(*ast.FunctionExpression)
+			if !ok {
+				continue
+			}
+			entry = Entry{Name: statement.Name.Value, Signature: signature(statement.Name.Value, function)}
+			entry.Doc = join(comments(statement, program.Attached[statement]), function.Doc())
+
+		case *ast.EnumStatement:
+			entry = Entry{Name: statement.Name.Value, Signature: statement.String()}
+			entry.Doc = comments(statement, program.Attached[statement])
+
+		default:
+			continue
+		}
+
+		entry.Position = statement.Pos()
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Markdown renders entries as a Markdown document headed by title.
+func Markdown(title string, entries []Entry) string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "# %s\n", title)
+	for _, entry := range entries {
+		fmt.Fprintf(out, "\n## %s\n\n```spike\n%s\n```\n", entry.Name, entry.Signature)
+		if entry.Doc != "" {
+			fmt.Fprintf(out, "\n%s\n", entry.Doc)
+		}
+	}
+
+	return out.String()
+}
+
+func signature(name string, function *ast.FunctionExpression) string {
+	parameters := make([]string, len(function.Parameters))
+	for i, parameter := range function.Parameters {
+		parameters[i] = parameter.Value
+	}
+
+	keyword := "fn"
+	if function.Generator {
+		keyword = "fn*"
+	}
+
+	return fmt.Sprintf("%s %s(%s)", keyword, name, strings.Join(parameters, ", "))
+}
+
+// comments returns the text of the leading comments directly above statement,
+// without blank lines between them.
+func comments(statement ast.Statement, attached *ast.Comments) string {
+	if attached == nil {
+		return ""
+	}
+
+	line := statement.Pos().Line
+	start := len(attached.Leading)
+	for start > 0 {
+		comment := attached.Leading[start-1]
+		if comment.Pos().Line != line-1 {
+			break
+		}
+		start--
+		line--
+	}
+
+	lines := make([]string, 0, len(attached.Leading)-start)
+	for _, comment := range attached.Leading[start:] {
+		lines = append(lines, comment.Text())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func join(paragraphs ...string) string {
+	nonEmpty := []string{}
+	for _, paragraph := range paragraphs {
+		if paragraph != "" {
+			nonEmpty = append(nonEmpty, paragraph)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n\n")
+}
@@ -0,0 +1,82 @@
+package doc
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected []Entry
+	}{
+		"function with comments": {
+			source: "// Adds numbers.\n// Floats too.\nlet add = fn(a, b) { a + b };",
+			expected: []Entry{
+				{Name: "add", Signature: "fn add(a, b)", Doc: "Adds numbers.\nFloats too."},
+			},
+		},
+		"function with a doc string": {
+			source: "// Counts.\nlet count = fn*(n) { \"Yields n.\"; yield n };",
+			expected: []Entry{
+				{Name: "count", Signature: "fn* count(n)", Doc: "Counts.\n\nYields n."},
+			},
+		},
+		"comments separated by a blank line": {
+			source: "// License.\n\nlet f = fn() { 1 };",
+			expected: []Entry{
+				{Name: "f", Signature: "fn f()"},
+			},
+		},
+		"enum": {
+			source: "let x = 1;\n// Colors.\nenum Color { Red, Green }",
+			expected: []Entry{
+				{Name: "Color", Signature: "enum Color { Red, Green }", Doc: "Colors."},
+			},
+		},
+		"nested functions": {
+			source:   "if (true) { let f = fn() { 1 }; }",
+			expected: []Entry{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := Source(test.source)
+			require.NoError(t, err)
+
+			// Positions are covered by TestSource_positions.
+			for i := range entries {
+				entries[i].Position = lexer.Position{}
+			}
+			assert.Equal(t, test.expected, entries)
+		})
+	}
+}
+
+func TestSource_positions(t *testing.T) {
+	entries, err := Source("// Doubles.\nlet double = fn(x) { x * 2 };")
+	require.NoError(t, err)
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, lexer.Position{Line: 2, Column: 1, Offset: 12}, entries[0].Position)
+}
+
+func TestSource_parseError(t *testing.T) {
+	_, err := Source("let = 1;")
+
+	assert.EqualError(t, err, "expected identifier, got assign at 1:5")
+}
+
+func TestMarkdown(t *testing.T) {
+	entries := []Entry{
+		{Name: "add", Signature: "fn add(a, b)", Doc: "Adds numbers."},
+		{Name: "Color", Signature: "enum Color { Red }"},
+	}
+
+	expected := "# math.spike\n\n## add\n\n```spike\nfn add(a, b)\n```\n\nAdds numbers.\n\n## Color\n\n```spike\nenum Color { Red }\n```\n"
+	assert.Equal(t, expected, Markdown("math.spike", entries))
+}
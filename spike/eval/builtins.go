@@ -5,7 +5,74 @@ import (
 )
 
 var builtins = map[string]*object.BuiltinFunction{
-	"len":   object.GetBuiltinByName("len"),
-	"print": object.GetBuiltinByName("print"),
-	"read":  object.GetBuiltinByName("read"),
+	"len":              object.GetBuiltinByName("len"),
+	"type":             object.GetBuiltinByName("type"),
+	"deepCopy":         object.GetBuiltinByName("deepCopy"),
+	"freeze":           object.GetBuiltinByName("freeze"),
+	"assert":           object.GetBuiltinByName("assert"),
+	"forAll":           object.GetBuiltinByName("forAll"),
+	"bigint":           object.GetBuiltinByName("bigint"),
+	"split":            object.GetBuiltinByName("split"),
+	"join":             object.GetBuiltinByName("join"),
+	"trim":             object.GetBuiltinByName("trim"),
+	"replace":          object.GetBuiltinByName("replace"),
+	"upper":            object.GetBuiltinByName("upper"),
+	"lower":            object.GetBuiltinByName("lower"),
+	"contains":         object.GetBuiltinByName("contains"),
+	"startsWith":       object.GetBuiltinByName("startsWith"),
+	"endsWith":         object.GetBuiltinByName("endsWith"),
+	"indexOf":          object.GetBuiltinByName("indexOf"),
+	"regex":            object.GetBuiltinByName("regex"),
+	"regexMatch":       object.GetBuiltinByName("regexMatch"),
+	"regexFind":        object.GetBuiltinByName("regexFind"),
+	"regexReplace":     object.GetBuiltinByName("regexReplace"),
+	"bytes":            object.GetBuiltinByName("bytes"),
+	"slice":            object.GetBuiltinByName("slice"),
+	"first":            object.GetBuiltinByName("first"),
+	"last":             object.GetBuiltinByName("last"),
+	"rest":             object.GetBuiltinByName("rest"),
+	"push":             object.GetBuiltinByName("push"),
+	"pop":              object.GetBuiltinByName("pop"),
+	"keys":             object.GetBuiltinByName("keys"),
+	"values":           object.GetBuiltinByName("values"),
+	"has":              object.GetBuiltinByName("has"),
+	"delete":           object.GetBuiltinByName("delete"),
+	"merge":            object.GetBuiltinByName("merge"),
+	"iter":             object.GetBuiltinByName("iter"),
+	"next":             object.GetBuiltinByName("next"),
+	"range":            object.GetBuiltinByName("range"),
+	"map":              object.GetBuiltinByName("map"),
+	"filter":           object.GetBuiltinByName("filter"),
+	"reduce":           object.GetBuiltinByName("reduce"),
+	"callDepth":        object.GetBuiltinByName("callDepth"),
+	"stackTrace":       object.GetBuiltinByName("stackTrace"),
+	"doc":              object.GetBuiltinByName("doc"),
+	"rand":             object.GetBuiltinByName("rand"),
+	"randInt":          object.GetBuiltinByName("randInt"),
+	"seed":             object.GetBuiltinByName("seed"),
+	"readFile":         object.GetBuiltinByName("readFile"),
+	"writeFile":        object.GetBuiltinByName("writeFile"),
+	"appendFile":       object.GetBuiltinByName("appendFile"),
+	"readFileBytes":    object.GetBuiltinByName("readFileBytes"),
+	"writeFileBytes":   object.GetBuiltinByName("writeFileBytes"),
+	"listDir":          object.GetBuiltinByName("listDir"),
+	"now":              object.GetBuiltinByName("now"),
+	"clock":            object.GetBuiltinByName("clock"),
+	"sleep":            object.GetBuiltinByName("sleep"),
+	"httpGet":          object.GetBuiltinByName("httpGet"),
+	"httpRequest":      object.GetBuiltinByName("httpRequest"),
+	"exec":             object.GetBuiltinByName("exec"),
+	"print":            object.GetBuiltinByName("print"),
+	"println":          object.GetBuiltinByName("println"),
+	"exit":             object.GetBuiltinByName("exit"),
+	"args":             object.GetBuiltinByName("args"),
+	"eprint":           object.GetBuiltinByName("eprint"),
+	"eprintln":         object.GetBuiltinByName("eprintln"),
+	"input":            object.GetBuiltinByName("input"),
+	"printf":           object.GetBuiltinByName("printf"),
+	"format":           object.GetBuiltinByName("format"),
+	"read":             object.GetBuiltinByName("read"),
+	"normalizeNFC":     object.GetBuiltinByName("normalizeNFC"),
+	"caseFold":         object.GetBuiltinByName("caseFold"),
+	"equalsIgnoreCase": object.GetBuiltinByName("equalsIgnoreCase"),
 }
@@ -0,0 +1,59 @@
+package eval
+
+import (
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+const MainFunctionName = object.MainFunctionName
+
+// CallMain invokes the top-level main(args) function of an already evaluated
+// program. It reports false when the program defines no main function.
+func CallMain(environment *object.Environment, args []string) (object.Object, bool, error) {
+	return New().CallMain(environment, args)
+}
+
+func (evaluator *Evaluator) CallMain(environment *object.Environment, args []string) (object.Object, bool, error) {
+	definition, err := environment.Get(MainFunctionName)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	function, ok := definition.(*object.Function)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if len(function.Parameters) > 1 {
+		return nil, true, errors.Errorf("%s must take at most one parameter, got %d", MainFunctionName, len(function.Parameters))
+	}
+
+	arguments := &object.Array{Elements: make([]object.Object, len(args))}
+	for i, arg := range args {
+		arguments.Elements[i] = &object.String{Value: arg}
+	}
+
+	if len(function.Parameters) == 0 {
+		result, err := evaluator.applyFunction(function, nil)
+		return result, true, err
+	}
+
+	result, err := evaluator.applyFunction(function, []object.Object{arguments})
+	return result, true, err
+}
+
+// ExitCode maps the result of main to a process exit code: integers are
+// used as is, anything else means success. Like with exit, integers outside
+// 0 to object.MaxExitCode are an error.
+func ExitCode(result object.Object) (int, error) {
+	integer, ok := result.(*object.Integer)
+	if !ok {
+		return 0, nil
+	}
+	if integer.Value < 0 || integer.Value > object.MaxExitCode {
+		return 0, errors.Errorf("main: exit code must be between 0 and %d, got %d", object.MaxExitCode, integer.Value)
+	}
+
+	return int(integer.Value), nil
+}
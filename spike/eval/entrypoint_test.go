@@ -0,0 +1,86 @@
+package eval
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CallMain(t *testing.T) {
+	testCases := []struct {
+		input            string
+		args             []string
+		expectedHasMain  bool
+		expectedExitCode int
+	}{
+		{
+			input:            `let x = 5;`,
+			expectedHasMain:  false,
+			expectedExitCode: 0,
+		},
+		{
+			input:            `let main = fn(args) { len(args) };`,
+			args:             []string{"a", "b", "c"},
+			expectedHasMain:  true,
+			expectedExitCode: 3,
+		},
+		{
+			input:            `let base = 40; let main = fn() { base + 2 };`,
+			expectedHasMain:  true,
+			expectedExitCode: 42,
+		},
+		{
+			input:            `let main = fn(args) { args[0] };`,
+			args:             []string{"not a number"},
+			expectedHasMain:  true,
+			expectedExitCode: 0,
+		},
+		{
+			input:            `let main = 10;`,
+			expectedHasMain:  false,
+			expectedExitCode: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.input))).ParseProgram()
+			assert.NoError(t, err)
+
+			environment := object.NewEnvironment()
+			_, err = Eval(program, environment)
+			assert.NoError(t, err)
+
+			result, hasMain, err := CallMain(environment, testCase.args)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedHasMain, hasMain)
+			code, err := ExitCode(result)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedExitCode, code)
+		})
+	}
+}
+
+func Test_ExitCode_outOfRange(t *testing.T) {
+	for _, value := range []int64{-1, 256} {
+		_, err := ExitCode(&object.Integer{Value: value})
+		assert.EqualError(t, err, fmt.Sprintf("main: exit code must be between 0 and 255, got %d", value))
+	}
+}
+
+func Test_CallMain_tooManyParameters(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`let main = fn(a, b) { 0 };`))).ParseProgram()
+	assert.NoError(t, err)
+
+	environment := object.NewEnvironment()
+	_, err = Eval(program, environment)
+	assert.NoError(t, err)
+
+	_, _, err = CallMain(environment, nil)
+	assert.EqualError(t, err, "main must take at most one parameter, got 2")
+}
@@ -25,7 +25,7 @@ func Test_Eval_withErrors(t *testing.T) {
 		},
 		{
 			input:         "!10+true",
-			expectedError: "type mismatch: !integer",
+			expectedError: "type mismatch: boolean + boolean",
 		},
 		{
 			input:         "!(10+true)",
@@ -47,9 +47,173 @@ func Test_Eval_withErrors(t *testing.T) {
 			input:         "2 / true",
 			expectedError: "type mismatch: integer / boolean",
 		},
+		{
+			input:         "2 / 0",
+			expectedError: "division by zero",
+		},
 		{
 			input:         "x;",
-			expectedError: "undefined identifier: x",
+			expectedError: "unable to resolve identifier: x",
+		},
+		{
+			input:         "len(true)",
+			expectedError: "len: argument of type boolean is not supported",
+		},
+		{
+			input:         "len()",
+			expectedError: "len: expected 1 argument, got 0",
+		},
+		{
+			input:         "let add = fn(a, b) { a + b };\nadd(1)",
+			expectedError: "wrong number of arguments: want 2, got 1 (function defined at line 1)",
+		},
+		{
+			input:         "let add = fn(a, b) { a + b };\nadd(1, 2, 3)",
+			expectedError: "wrong number of arguments: want 2, got 3 (function defined at line 1)",
+		},
+		{
+			input:         "first([], [])",
+			expectedError: "first: expected 1 argument, got 2",
+		},
+		{
+			input:         "map([1], fn(a, b) { a })",
+			expectedError: "wrong number of arguments: want 2, got 1 (function defined at line 1)",
+		},
+		{
+			input:         "5(1, 2)",
+			expectedError: "calling non-function: integer",
+		},
+		{
+			input:         "reduce([1], 0, 5)",
+			expectedError: "calling non-function: integer",
+		},
+		{
+			input:         `merge({}, 1)`,
+			expectedError: "merge: argument 2 must be hash, got integer",
+		},
+		{
+			input:         `type()`,
+			expectedError: "type: expected 1 argument, got 0",
+		},
+		{
+			input:         `assert(true, "fine"); assert(len("ab") == 3, "length")`,
+			expectedError: "assertion failed: length",
+		},
+		{
+			input:         `assert(true, "a", "b")`,
+			expectedError: "assert: expected 1 or 2 arguments, got 3",
+		},
+		{
+			input:         `forAll({"s": {"type": "string", "min": 1}}, fn(s) { !contains(s, "a") })`,
+			expectedError: `forAll: property failed for {"s": "a"}`,
+		},
+		{
+			input:         `forAll({"xs": {"type": "array", "of": "boolean"}}, fn(xs) { [][len(xs)] })`,
+			expectedError: `forAll: property failed for {"xs": []}: index 0 out of range, length is 0`,
+		},
+		{
+			input:         `forAll({"xs": {"type": "array"}}, fn(xs) { true })`,
+			expectedError: `forAll: generator "xs": array elements are missing under of`,
+		},
+		{
+			input:         `deepCopy()`,
+			expectedError: "deepCopy: expected 1 argument, got 0",
+		},
+		{
+			input:         "{fn() { 1 }: 1}",
+			expectedError: "unusable as hash key: function at 1:1",
+		},
+		{
+			input:         `{"a": 1, [1]: 2}`,
+			expectedError: "unusable as hash key: array at 1:1",
+		},
+		{
+			input:         "{1: 2}[[1]]",
+			expectedError: "unusable as hash key: array",
+		},
+		{
+			input:         "let f = fn() { return 1, 2, 3 }; let x, y = f()",
+			expectedError: "cannot destructure tuple of 3 elements into 2 names",
+		},
+		{
+			input:         "enum Color { Red }; Color.Blue",
+			expectedError: "Color has no member Blue",
+		},
+		{
+			input:         "let g = fn*() { yield 1; len(1) }; let it = g(); next(it); next(it)",
+			expectedError: "len: argument of type integer is not supported",
+		},
+		{
+			input:         "1 + 2.5",
+			expectedError: "float literals are not supported yet: 2.5 at 1:5",
+		},
+		{
+			input:         `let a = freeze([1]); a[0] = 2`,
+			expectedError: "cannot modify frozen array",
+		},
+		{
+			input:         `let a = [1]; a[1] = 2`,
+			expectedError: "index 1 out of range [0..0]",
+		},
+		{
+			input:         "let xs = [1, 2, 3, 4];\nxs[7]",
+			expectedError: "index 7 out of range [0..3] at 2:1",
+		},
+		{
+			input:         `bytes("hé")[-1]`,
+			expectedError: "index -1 out of range [0..2] at 1:1",
+		},
+		{
+			input:         `"żółw"[4]`,
+			expectedError: "index 4 out of range [0..3] at 1:1",
+		},
+		{
+			input:         `let f = fn(x) { fn() { x = 1 } }; f(1)()`,
+			expectedError: "cannot assign to captured variable x",
+		},
+		{
+			input:         `len = 1`,
+			expectedError: "cannot assign to builtin len",
+		},
+		{
+			input:         `"abc"[0] = "x"`,
+			expectedError: "index assignment not supported: string",
+		},
+		{
+			input:         `let h = {}; h[[]] = 1`,
+			expectedError: "unusable as hash key: array",
+		},
+		{
+			input:         "y = 1",
+			expectedError: "unable to resolve identifier: y",
+		},
+		{
+			input:         "len(x)",
+			expectedError: "unable to resolve identifier: x",
+		},
+		{
+			input:         `"a" < "b"`,
+			expectedError: "unable to compare variables of type string and string",
+		},
+		{
+			input:         `1 > "a"`,
+			expectedError: "both operands must have same type, had: integer and string",
+		},
+		{
+			input:         "1 && true",
+			expectedError: "type mismatch: integer && boolean",
+		},
+		{
+			input:         "1[0]",
+			expectedError: "index operator not supported: integer",
+		},
+		{
+			input:         `[1]["a"]`,
+			expectedError: "Array index must be an integer, got: string",
+		},
+		{
+			input:         "let f = fn() { f() }; f()",
+			expectedError: "stack overflow",
 		},
 	}
 
@@ -1,20 +1,64 @@
 package eval
 
 import (
+	"math/big"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
 	"spike-interpreter-go/spike/parser/ast"
 
 	"github.com/pkg/errors"
 )
 
-func Eval(node ast.Node, environment *object.Environment) (object.Object, error) {
+func (evaluator *Evaluator) Eval(node ast.Node, environment *object.Environment) (object.Object, error) {
+	if evaluator.maxSteps > 0 {
+		evaluator.steps++
+		if evaluator.steps > evaluator.maxSteps {
+			return nil, &object.StepLimitError{Limit: evaluator.maxSteps}
+		}
+	}
+
+	if evaluator.ctx != nil {
+		evaluator.evaluated++
+		if evaluator.evaluated%interruptCheckInterval == 0 {
+			select {
+			case <-evaluator.ctx.Done():
+				return nil, evaluator.ctx.Err()
+			default:
+			}
+		}
+	}
+
+	result, err := evaluator.eval(node, environment)
+	if err != nil && err != evaluator.err {
+		evaluator.err = err
+		evaluator.errorPosition = errorPosition(node)
+	}
+
+	return result, err
+}
+
+// errorPosition is where errors of node are reported, like the compiler maps
+// them for the VM: at the operator of infix expressions and at the start of
+// any other node.
+func errorPosition(node ast.Node) lexer.Position {
+	if infix, ok := node.(*ast.InfixExpression); ok {
+		return infix.Token.Position
+	}
+
+	return node.Pos()
+}
+
+func (evaluator *Evaluator) eval(node ast.Node, environment *object.Environment) (object.Object, error) {
 	switch node := node.(type) {
 	case *ast.Program:
-		return evalProgram(node, environment)
+		return evaluator.evalProgram(node, environment)
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression, environment)
+		return evaluator.Eval(node.Expression, environment)
 	case *ast.Integer:
 		return &object.Integer{Value: node.Value}, nil
+	case *ast.Float:
+		return nil, diagnostic.AtNode(node, "float literals are not supported yet: %s", node.Token.Literal)
 	case *ast.Boolean:
 		return evalBoolean(node)
 	case *ast.Array:
@@ -23,7 +67,7 @@ func Eval(node ast.Node, environment *object.Environment) (object.Object, error)
 		}
 
 		for _, element := range node.Elements {
-			evaluatedElement, err := Eval(element, environment)
+			evaluatedElement, err := evaluator.Eval(element, environment)
 			if err != nil {
 				return nil, err
 			}
@@ -32,109 +76,228 @@ func Eval(node ast.Node, environment *object.Environment) (object.Object, error)
 
 		return array, nil
 
-	case *ast.Hash:
-		hash := &object.Hash{
-			Pairs: make(map[object.HashKey]object.HashPair, len(node.Pairs)),
+	case *ast.Tuple:
+		tuple := &object.Tuple{
+			Elements: make([]object.Object, 0, len(node.Elements)),
+		}
+
+		for _, element := range node.Elements {
+			evaluatedElement, err := evaluator.Eval(element, environment)
+			if err != nil {
+				return nil, err
+			}
+			tuple.Elements = append(tuple.Elements, evaluatedElement)
 		}
 
-		for key, value := range node.Pairs {
-			evaluatedKey, err := Eval(key, environment)
+		return tuple, nil
+
+	case *ast.Hash:
+		hash := object.NewHash(len(node.Pairs))
+
+		for _, key := range node.Keys {
+			value := node.Pairs[key]
+			evaluatedKey, err := evaluator.Eval(key, environment)
 			if err != nil {
 				return nil, err
 			}
-			evalutedValue, err := Eval(value, environment)
+			evalutedValue, err := evaluator.Eval(value, environment)
 			if err != nil {
 				return nil, err
 			}
 
-			hashable, isHashable := evaluatedKey.(object.Hashable)
-			if !isHashable {
-				return nil, errors.Errorf("%s does not implement Hashable", evaluatedKey.Type())
+			_, err = object.HashKeyOf(evaluatedKey)
+			if err != nil {
+				return nil, diagnostic.AtNode(node, "%s", err)
 			}
 
-			hash.Pairs[hashable.GetHashKey()] = object.HashPair{
-				Key:   evaluatedKey,
-				Value: evalutedValue,
-			}
+			hash.Set(evaluatedKey, evalutedValue)
 		}
 
 		return hash, nil
 
 	case *ast.PrefixExpression:
-		right, err := Eval(node.Right, environment)
+		right, err := evaluator.Eval(node.Right, environment)
 		if err != nil {
 			return nil, err
 		}
 		return evalPrefixExpression(right, node.Operator)
 	case *ast.InfixExpression:
-		left, err := Eval(node.Left, environment)
+		left, err := evaluator.Eval(node.Left, environment)
 		if err != nil {
 			return nil, err
 		}
-		right, err := Eval(node.Right, environment)
+		right, err := evaluator.Eval(node.Right, environment)
 		if err != nil {
 			return nil, err
 		}
 
 		return evalInfixExpression(left, right, node.Operator)
 	case *ast.IfExpression:
-		condition, _ := Eval(node.Condition, environment)
-		if condition.Equal(&object.True) {
-			return Eval(node.Then, environment)
-		} else {
-			return Eval(node.Else, environment)
+		condition, err := evaluator.Eval(node.Condition, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		if object.Truthy(condition) {
+			return evaluator.Eval(node.Then, environment)
+		} else if node.Else != nil {
+			return evaluator.Eval(node.Else, environment)
 		}
+
+		return &object.NullObject, nil
 	case *ast.BlockStatement:
-		return evalStatements(node.Statements, environment)
+		return evaluator.evalStatements(node.Statements, environment)
 	case *ast.ReturnStatement:
-		result, _ := Eval(node.Result, environment)
+		if node.Result == nil {
+			return &object.Return{Value: &object.NullObject}, nil
+		}
+		result, err := evaluator.Eval(node.Result, environment)
+		if err != nil {
+			return nil, err
+		}
 		return &object.Return{Value: result}, nil
 	case *ast.LetStatement:
-		result, _ := Eval(node.Value, environment)
+		result, err := evaluator.Eval(node.Value, environment)
+		if err != nil {
+			return nil, err
+		}
+
 		environment.Set(node.Name.Value, result)
+	case *ast.EnumStatement:
+		members := make([]string, len(node.Members))
+		for i, member := range node.Members {
+			members[i] = member.Value
+		}
+
+		environment.Set(node.Name.Value, object.NewEnum(node.Name.Value, members))
+	case *ast.ImportStatement:
+		module, ok := evaluator.modules[node.Module.Value]
+		if !ok {
+			return nil, errors.Errorf("unknown module %s", node.Module.Value)
+		}
+
+		environment.Set(node.Module.Value, module)
+	case *ast.MemberExpression:
+		value, err := evaluator.Eval(node.Object, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		return object.Member(value, node.Member.Value)
+	case *ast.DestructuringLetStatement:
+		result, err := evaluator.Eval(node.Value, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		elements, err := object.Destructure(result, len(node.Names))
+		if err != nil {
+			return nil, err
+		}
+
+		for i, name := range node.Names {
+			environment.Set(name.Value, elements[i])
+		}
 	case *ast.Identifier:
 		return evalIdentifier(node.Value, environment)
+	case *ast.AssignExpression:
+		return evaluator.evalAssignment(node, environment)
 	case *ast.FunctionExpression:
 		return &object.Function{
 			Parameters:  node.Parameters,
 			Body:        node.Body,
 			Environment: environment,
+			Name:        node.Name,
+			Generator:   node.Generator,
+			Doc:         node.Doc(),
+			Line:        node.Pos().Line,
 		}, nil
+	case *ast.YieldExpression:
+		value, err := evaluator.Eval(node.Value, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		err = evaluator.yield(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &object.NullObject, nil
 	case *ast.CallExpression:
-		function, _ := Eval(node.Function, environment)
-		arguments, _ := evalExpressions(node.Arguments, environment)
-		return applyFunction(function, arguments)
+		function, err := evaluator.Eval(node.Function, environment)
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := evaluator.evalExpressions(node.Arguments, environment)
+		if err != nil {
+			return nil, err
+		}
+		return evaluator.applyFunction(function, arguments)
 	case *ast.String:
 		return &object.String{Value: node.Value}, nil
 	case *ast.IndexExpression:
-		evaluatedArray, err := Eval(node.Array, environment)
+		evaluatedArray, err := evaluator.Eval(node.Array, environment)
 		if err != nil {
 			return nil, err
 		}
-		evaluatedIndex, err := Eval(node.Index, environment)
+		evaluatedIndex, err := evaluator.Eval(node.Index, environment)
 		if err != nil {
 			return nil, err
 		}
 
 		switch evaluatedArray.(type) {
+		case *object.String:
+			integerObject, ok := evaluatedIndex.(*object.Integer)
+			if !ok {
+				return nil, errors.Errorf("String index must be an integer, got: %s", evaluatedIndex.Type())
+			}
+
+			character, err := evaluatedArray.(*object.String).Character(integerObject.Value)
+			if err != nil {
+				return nil, diagnostic.AtNode(node, "%s", err)
+			}
+
+			return character, nil
 		case *object.Array:
 			arrayObject := evaluatedArray.(*object.Array)
 			integerObject, ok := evaluatedIndex.(*object.Integer)
 			if !ok {
-				return nil, errors.New("only integer can be used as index")
+				return nil, errors.Errorf("Array index must be an integer, got: %s", evaluatedIndex.Type())
+			}
+
+			if integerObject.Value < 0 || integerObject.Value >= int64(len(arrayObject.Elements)) {
+				return nil, diagnostic.AtNode(node, "%s", object.IndexOutOfRange(integerObject.Value, len(arrayObject.Elements)))
 			}
 
 			return arrayObject.Elements[integerObject.Value], nil
+		case *object.Bytes:
+			bytesObject := evaluatedArray.(*object.Bytes)
+			integerObject, ok := evaluatedIndex.(*object.Integer)
+			if !ok {
+				return nil, errors.Errorf("Bytes index must be an integer, got: %s", evaluatedIndex.Type())
+			}
+
+			if integerObject.Value < 0 || integerObject.Value >= int64(len(bytesObject.Value)) {
+				return nil, diagnostic.AtNode(node, "%s", object.IndexOutOfRange(integerObject.Value, len(bytesObject.Value)))
+			}
+
+			return &object.Integer{Value: int64(bytesObject.Value[integerObject.Value])}, nil
 		case *object.Hash:
 			hashObject := evaluatedArray.(*object.Hash)
 			hashable, ok := evaluatedIndex.(object.Hashable)
 			if !ok {
-				return nil, errors.New("hash key must implement Hashable interface")
+				return nil, errors.Errorf("unusable as hash key: %s", evaluatedIndex.Type())
+			}
+
+			value, err := hashObject.Get(hashable)
+			if err != nil {
+				return &object.NullObject, nil
 			}
 
-			return hashObject.Get(hashable)
+			return value, nil
 		default:
-			return nil, errors.New("index can be used only on array")
+			return nil, errors.Errorf("index operator not supported: %s", evaluatedArray.Type())
 		}
 	default:
 		return nil, errors.Errorf("Trying to evaluate unknown node: %T: %#v", node, node)
@@ -142,22 +305,47 @@ func Eval(node ast.Node, environment *object.Environment) (object.Object, error)
 	return nil, nil
 }
 
-func applyFunction(function object.Object, arguments []object.Object) (object.Object, error) {
+func (evaluator *Evaluator) applyFunction(function object.Object, arguments []object.Object) (object.Object, error) {
 	if builtinFunction, ok := function.(*object.BuiltinFunction); ok {
-		return builtinFunction.Function(arguments...)
+		err := evaluator.policy.CheckBuiltin(builtinFunction.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := builtinFunction.Function(evaluator, arguments...)
+		if err == nil && result == nil {
+			result = &object.NullObject
+		}
+
+		return result, err
 	}
 
 	functionObject, ok := function.(*object.Function)
 	if !ok {
-		return nil, nil
+		return nil, errors.Errorf("calling non-function: %s", function.Type())
 	}
 
+	if len(functionObject.Parameters) != len(arguments) {
+		return nil, object.ArityError(len(functionObject.Parameters), len(arguments), functionObject.Line)
+	}
+
+	if len(evaluator.calls) >= maxCalls {
+		return nil, errors.New("stack overflow")
+	}
+
+	evaluator.calls = append(evaluator.calls, object.FrameName(functionObject.Name))
+	defer func() { evaluator.calls = evaluator.calls[:len(evaluator.calls)-1] }()
+
 	extendedEnvironment := object.ExtendEnvironment(functionObject.Environment)
 	for i, identifier := range functionObject.Parameters {
 		extendedEnvironment.Set(identifier.Value, arguments[i])
 	}
 
-	result, err := Eval(functionObject.Body, extendedEnvironment)
+	if functionObject.Generator {
+		return newGenerator(evaluator, functionObject, extendedEnvironment), nil
+	}
+
+	result, err := evaluator.Eval(functionObject.Body, extendedEnvironment)
 	if err != nil {
 		return nil, err
 	}
@@ -169,11 +357,11 @@ func applyFunction(function object.Object, arguments []object.Object) (object.Ob
 	return result, nil
 }
 
-func evalProgram(program *ast.Program, environment *object.Environment) (object.Object, error) {
+func (evaluator *Evaluator) evalProgram(program *ast.Program, environment *object.Environment) (object.Object, error) {
 	var result object.Object
 	var err error
 	for _, statement := range program.Statements {
-		result, err = Eval(statement, environment)
+		result, err = evaluator.Eval(statement, environment)
 		if err != nil {
 			return nil, err
 		}
@@ -186,11 +374,11 @@ func evalProgram(program *ast.Program, environment *object.Environment) (object.
 	return result, err
 }
 
-func evalStatements(statements []ast.Statement, environment *object.Environment) (object.Object, error) {
+func (evaluator *Evaluator) evalStatements(statements []ast.Statement, environment *object.Environment) (object.Object, error) {
 	var result object.Object
 	var err error
 	for _, statement := range statements {
-		result, err = Eval(statement, environment)
+		result, err = evaluator.Eval(statement, environment)
 		if err != nil {
 			return nil, err
 		}
@@ -203,11 +391,14 @@ func evalStatements(statements []ast.Statement, environment *object.Environment)
 	return result, err
 }
 
-func evalExpressions(expressions []ast.Expression, environment *object.Environment) ([]object.Object, error) {
+func (evaluator *Evaluator) evalExpressions(expressions []ast.Expression, environment *object.Environment) ([]object.Object, error) {
 	result := make([]object.Object, 0)
 
 	for _, expression := range expressions {
-		evaluated, _ := Eval(expression, environment)
+		evaluated, err := evaluator.Eval(expression, environment)
+		if err != nil {
+			return nil, err
+		}
 		result = append(result, evaluated)
 	}
 
@@ -234,26 +425,25 @@ func evalPrefixExpression(right object.Object, operator string) (object.Object,
 }
 
 func evalBangOperator(right object.Object) (object.Object, error) {
-	switch right {
-	case &object.True:
-		return &object.False, nil
-	case &object.False:
-		return &object.True, nil
-	default:
-		return nil, errors.Errorf("type mismatch: !%s", right.Type())
-	}
+	return nativeBoolToBoolean(!object.Truthy(right)), nil
 }
 
 func evalMinusOperator(right object.Object) (object.Object, error) {
 	switch rightObject := right.(type) {
 	case *object.Integer:
 		return &object.Integer{Value: -rightObject.Value}, nil
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Neg(rightObject.Value)}, nil
 	default:
 		return nil, errors.Errorf("type mismatch: -%s", right.Type())
 	}
 }
 
 func evalInfixExpression(left, right object.Object, operator string) (object.Object, error) {
+	if result, ok, err := object.BigIntOperation(operator, left, right); ok {
+		return result, err
+	}
+
 	switch operator {
 	case "+":
 		return evalPlusInfixOperator(left, right)
@@ -269,33 +459,18 @@ func evalInfixExpression(left, right object.Object, operator string) (object.Obj
 	case "!=":
 		equal := left.Equal(right)
 		return nativeBoolToBoolean(!equal), nil
-	case "<":
-		leftComparable := left.(object.Comparable)
-		rightComparable := right.(object.Comparable)
-		result, err := leftComparable.Compare(rightComparable)
-		return nativeBoolToBoolean(result == object.LT), err
-	case ">":
-		leftComparable := left.(object.Comparable)
-		rightComparable := right.(object.Comparable)
-		result, err := leftComparable.Compare(rightComparable)
-		return nativeBoolToBoolean(result == object.GT), err
-	case "<=":
-		leftComparable := left.(object.Comparable)
-		rightComparable := right.(object.Comparable)
-		result, err := leftComparable.Compare(rightComparable)
-		return nativeBoolToBoolean(result == object.LT || result == object.EQ), err
-	case ">=":
-		leftComparable := left.(object.Comparable)
-		rightComparable := right.(object.Comparable)
-		result, err := leftComparable.Compare(rightComparable)
-		return nativeBoolToBoolean(result == object.GT || result == object.EQ), err
-	case "||":
-		leftBool := left.(*object.Boolean)
-		rightBool := right.(*object.Boolean)
-		return nativeBoolToBoolean(leftBool.Value || rightBool.Value), nil
-	case "&&":
-		leftBool := left.(*object.Boolean)
-		rightBool := right.(*object.Boolean)
+	case "<", ">", "<=", ">=":
+		return evalComparison(left, right, operator)
+	case "||", "&&":
+		leftBool, leftOk := left.(*object.Boolean)
+		rightBool, rightOk := right.(*object.Boolean)
+		if !leftOk || !rightOk {
+			return nil, errors.Errorf("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+		if operator == "||" {
+			return nativeBoolToBoolean(leftBool.Value || rightBool.Value), nil
+		}
+
 		return nativeBoolToBoolean(leftBool.Value && rightBool.Value), nil
 
 	default:
@@ -303,12 +478,55 @@ func evalInfixExpression(left, right object.Object, operator string) (object.Obj
 	}
 }
 
+// evalComparison orders values of the same comparable type, failing with the
+// VM's errors for any other operands.
+func evalComparison(left, right object.Object, operator string) (object.Object, error) {
+	if left.Type() != right.Type() {
+		return nil, errors.Errorf("both operands must have same type, had: %s and %s", left.Type(), right.Type())
+	}
+
+	leftComparable, leftOk := left.(object.Comparable)
+	rightComparable, rightOk := right.(object.Comparable)
+	if !leftOk || !rightOk {
+		return nil, errors.Errorf("unable to compare variables of type %s and %s", left.Type(), right.Type())
+	}
+
+	ordering, err := leftComparable.Compare(rightComparable)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "<":
+		return nativeBoolToBoolean(ordering == object.LT), nil
+	case ">":
+		return nativeBoolToBoolean(ordering == object.GT), nil
+	case "<=":
+		return nativeBoolToBoolean(ordering != object.GT), nil
+	}
+
+	return nativeBoolToBoolean(ordering != object.LT), nil
+}
+
 func evalPlusInfixOperator(left, right object.Object) (object.Object, error) {
 	if left.Type() == object.IntegerType && right.Type() == object.IntegerType {
 		newValue := left.(*object.Integer).Value + right.(*object.Integer).Value
 		return &object.Integer{Value: newValue}, nil
 	}
 
+	if left.Type() == object.StringType && right.Type() == object.StringType {
+		return &object.String{Value: left.(*object.String).Value + right.(*object.String).Value}, nil
+	}
+
+	if leftArray, ok := left.(*object.Array); ok {
+		if rightArray, ok := right.(*object.Array); ok {
+			elements := make([]object.Object, 0, len(leftArray.Elements)+len(rightArray.Elements))
+			elements = append(elements, leftArray.Elements...)
+
+			return &object.Array{Elements: append(elements, rightArray.Elements...)}, nil
+		}
+	}
+
 	return nil, errors.Errorf("type mismatch: %s + %s", left.Type(), right.Type())
 }
 
@@ -332,6 +550,9 @@ func evalAsteriskInfixOperator(left, right object.Object) (object.Object, error)
 
 func evalAsteriskSlashOperator(left, right object.Object) (object.Object, error) {
 	if left.Type() == object.IntegerType && right.Type() == object.IntegerType {
+		if right.(*object.Integer).Value == 0 {
+			return nil, errors.New("division by zero")
+		}
 		newValue := left.(*object.Integer).Value / right.(*object.Integer).Value
 		return &object.Integer{Value: newValue}, nil
 	}
@@ -347,6 +568,51 @@ func nativeBoolToBoolean(b bool) *object.Boolean {
 	return &object.False
 }
 
+func (evaluator *Evaluator) evalAssignment(node *ast.AssignExpression, environment *object.Environment) (object.Object, error) {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		value, err := evaluator.Eval(node.Value, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		err = environment.Assign(target.Value, value)
+		if _, ok := builtins[target.Value]; ok && err != nil {
+			return nil, errors.Errorf("cannot assign to builtin %s", target.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return value, nil
+
+	case *ast.IndexExpression:
+		container, err := evaluator.Eval(target.Array, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := evaluator.Eval(target.Index, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := evaluator.Eval(node.Value, environment)
+		if err != nil {
+			return nil, err
+		}
+
+		err = object.SetIndex(container, index, value)
+		if err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}
+
+	return nil, errors.Errorf("cannot assign to %s", node.Target.String())
+}
+
 func evalIdentifier(name string, environment *object.Environment) (object.Object, error) {
 	variable, err := environment.Get(name)
 	if err == nil {
@@ -1,6 +1,7 @@
 package eval
 
 import (
+	"math/big"
 	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
 	"spike-interpreter-go/spike/parser"
@@ -48,6 +49,8 @@ func Test_Eval_AST(t *testing.T) {
 }
 
 func Test_Eval_program(t *testing.T) {
+	factorial25, _ := new(big.Int).SetString("15511210043330985984000000", 10)
+
 	testCases := []struct {
 		input    string
 		expected object.Object
@@ -140,6 +143,18 @@ func Test_Eval_program(t *testing.T) {
 			input:    "(2 > 3) || (true != false)",
 			expected: &object.True,
 		},
+		{
+			input:    `"spi" + "ke"`,
+			expected: &object.String{Value: "spike"},
+		},
+		{
+			input:    `{"a": 1}["b"]`,
+			expected: &object.NullObject,
+		},
+		{
+			input:    "[1] + [2]",
+			expected: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}},
+		},
 		{
 			input:    "if (2 > 3) { 10; } else { 11; }",
 			expected: &object.Integer{Value: 11},
@@ -164,6 +179,10 @@ func Test_Eval_program(t *testing.T) {
 			input:    "if (10 > 1) { if (10 > 1) { return 10; } return 5; }",
 			expected: &object.Integer{Value: 10},
 		},
+		{
+			input:    "2 + 2; return; 3 + 3;",
+			expected: &object.NullObject,
+		},
 		{
 			input:    "let x = 5; x;",
 			expected: &object.Integer{Value: 5},
@@ -220,6 +239,294 @@ func Test_Eval_program(t *testing.T) {
 			input:    "len([1, 2, 3])",
 			expected: &object.Integer{Value: 3},
 		},
+		{
+			input: `[!first([]), !0, !"", if (first([])) { 1 } else { 2 }, if (0) { 1 } else { 2 },
+			first([]) == first([]), first([]) != 1, 1 == first([])]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+				&object.Boolean{Value: false},
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 1},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+			}},
+		},
+		{
+			input:    `if (false) { 1 }`,
+			expected: &object.NullObject,
+		},
+		{
+			input: `let h = merge({"b": 1, "a": 2}, {"c": 3, "b": 4}); [keys(delete(h, "a")), values(h)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{&object.String{Value: "b"}, &object.String{Value: "c"}}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+			}},
+		},
+		{
+			input: `let h = {true: "yes", false: "no", 1: "one"}; [h[1 == 1], h[false], h[1], keys(h)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.String{Value: "yes"},
+				&object.String{Value: "no"},
+				&object.String{Value: "one"},
+				&object.Array{Elements: []object.Object{&object.True, &object.False, &object.Integer{Value: 1}}},
+			}},
+		},
+		{
+			input: `let config = fn() { {"ports": [80]} }; let frozen = freeze(config());
+			[frozen["ports"], config()["ports"]]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 80}}, Frozen: true},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 80}}},
+			}},
+		},
+		{
+			input: `let s = "żółw"; [len(s), s[1], s[3], slice(s, 1, 3), len(bytes(s)), indexOf(s, "w")]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 4},
+				&object.String{Value: "ó"},
+				&object.String{Value: "w"},
+				&object.String{Value: "ół"},
+				&object.Integer{Value: 7},
+				&object.Integer{Value: 3},
+			}},
+		},
+		{
+			input: `
+			let swap = fn(a, b) { return b, a };
+			let x, y = swap(1, 2);
+			let g = fn() { let p, q = swap(3, 4); [p, q] };
+			[x, y, g(), len(swap(1, 2)), swap(5, 6)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 1},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 4}, &object.Integer{Value: 3}}},
+				&object.Integer{Value: 2},
+				&object.Tuple{Elements: []object.Object{&object.Integer{Value: 6}, &object.Integer{Value: 5}}},
+			}},
+		},
+		{
+			input: `
+			enum Color { Red, Green, Blue };
+			let isRed = fn(c) { c == Color.Red };
+			[isRed(Color.Red), isRed(Color.Blue), Color.Green != Color.Green, Color.Blue == 2, type(Color.Red)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.True,
+				&object.False,
+				&object.False,
+				&object.False,
+				&object.String{Value: "enumMember"},
+			}},
+		},
+		{
+			input: `
+			let step = 2;
+			let count = fn*(n) { let doubled = n * 2; yield n; yield doubled; yield doubled + step; };
+			let g = count(10);
+			let first = next(g);
+			[first, map(g, fn(x) { x + 1 }), next(g), map(count(1), fn(x) { x })]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 10},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 21}, &object.Integer{Value: 23}}},
+				&object.NullObject,
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 4},
+				}},
+			}},
+		},
+		{
+			input: `let f = fn(x) { "Doubles x."; x * 2 }; [doc(f), f(2), doc(fn() { 1 }), doc(len)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.String{Value: "Doubles x."},
+				&object.Integer{Value: 4},
+				&object.NullObject,
+				&object.NullObject,
+			}},
+		},
+		{
+			input: `
+			let total = 1;
+			let add = fn(x) { let local = x; local = local + 1; total = total + local; total };
+			let a = [1, 2, 3];
+			let h = {"a": 1};
+			let r = a[0] = h["b"] = 5;
+			add(1);
+			[total, a, h["b"], r, add(10)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 3},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 5},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+				&object.Integer{Value: 5},
+				&object.Integer{Value: 5},
+				&object.Integer{Value: 14},
+			}},
+		},
+		{
+			input:    `len({"a": 1}) + len(["a", "b"][0])`,
+			expected: &object.Integer{Value: 2},
+		},
+		{
+			input: `
+			let factorial = fn(n) { if (n == 0) { bigint(1) } else { n * factorial(n - 1) } };
+			let big = factorial(25);
+			[big, -big / bigint("1000000000000000000000000") - 1, big > 1, 2 == bigint(2), type(big)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.BigInt{Value: factorial25},
+				&object.BigInt{Value: big.NewInt(-16)},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: true},
+				&object.String{Value: "bigint"},
+			}},
+		},
+		{
+			input: `let b = bytes("hé"); [len(b), b[0], b[2], slice(b, 1, 3), slice([1, 2, 3], 1, 3), slice("spike", 0, 2), bytes([0, 255])]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 104},
+				&object.Integer{Value: 169},
+				&object.Bytes{Value: []byte{195, 169}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+				&object.String{Value: "sp"},
+				&object.Bytes{Value: []byte{0, 255}},
+			}},
+		},
+		{
+			input: `
+			let it = iter([1, 2]);
+			[next(it), next(it), next(it), map(range(3), fn(x) { x * x }), map("hé", upper), filter({"b": 1, "a": 2}, fn(k) { true })]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
+				&object.NullObject,
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 0},
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 4},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "H"},
+					&object.String{Value: "É"},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "b"},
+					&object.String{Value: "a"},
+				}},
+			}},
+		},
+		{
+			input: `let digits = regex("[0-9]+"); filter(["a1", "b", "22"], fn(s) { regexMatch(digits, s) })`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.String{Value: "a1"},
+				&object.String{Value: "22"},
+			}},
+		},
+		{
+			input: `map([1, "a", true, [], {}, len, type(1)], type)`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.String{Value: "integer"},
+				&object.String{Value: "string"},
+				&object.String{Value: "boolean"},
+				&object.String{Value: "array"},
+				&object.String{Value: "hash"},
+				&object.String{Value: "builtinFunction"},
+				&object.String{Value: "string"},
+			}},
+		},
+		{
+			input:    `type(fn() {})`,
+			expected: &object.String{Value: "function"},
+		},
+		{
+			input: `
+			let h = merge({"b": 2, "a": 1}, {"c": 3, "a": 0});
+			let d = delete(h, "b");
+			[keys(d), values(d), has(h, "b"), has(d, "b"), len(h)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "a"},
+					&object.String{Value: "c"},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 0},
+					&object.Integer{Value: 3},
+				}},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+				&object.Integer{Value: 3},
+			}},
+		},
+		{
+			input:    `join(map(split(" a, b ,c ", ","), fn(s) { upper(trim(s)) }), "-")`,
+			expected: &object.String{Value: "A-B-C"},
+		},
+		{
+			input: `
+			let inner = fn() { [callDepth(), stackTrace()] };
+			let outer = fn() { first(map([1], fn(x) { inner() })) };
+			[callDepth(), outer()]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 0},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 3},
+					&object.Array{Elements: []object.Object{
+						&object.String{Value: "inner"},
+						&object.String{Value: "<anonymous>"},
+						&object.String{Value: "outer"},
+						&object.String{Value: "<main>"},
+					}},
+				}},
+			}},
+		},
+		{
+			input: `
+			let double = fn(x) { x * 2 };
+			let isBig = fn(x) { x > 2 };
+			let sum = fn(acc, x) { acc + x };
+			let values = map([1, 2, 3], double);
+			[values, filter(values, isBig), reduce(values, 0, sum), map(["a", [1, 2]], len)]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 6},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 6},
+				}},
+				&object.Integer{Value: 12},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+				}},
+			}},
+		},
+		{
+			input: `let a = [1, 2]; let b = push(a, 3); [len(a), last(b), first(rest(b)), len(pop(b))]`,
+			expected: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 2},
+			}},
+		},
+		{
+			input:    `normalizeNFC("Å")`,
+			expected: &object.String{Value: "Å"},
+		},
 		{
 			input: `{5: "val"}`,
 			expected: &object.Hash{Pairs: map[object.HashKey]object.HashPair{
@@ -230,6 +537,15 @@ func Test_Eval_program(t *testing.T) {
 					Key:   &object.Integer{Value: 5},
 					Value: &object.String{Value: "val"},
 				},
+			}, Keys: []object.HashKey{{Type: object.IntegerType, Value: 5}}},
+		},
+		{
+			input: "let order = []; let f = fn(x) { order = push(order, x); x }; {f(\"b\"): f(1), f(\"a\"): f(2)}; order",
+			expected: &object.Array{Elements: []object.Object{
+				&object.String{Value: "b"},
+				&object.Integer{Value: 1},
+				&object.String{Value: "a"},
+				&object.Integer{Value: 2},
 			}},
 		},
 		{
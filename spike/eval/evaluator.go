@@ -0,0 +1,213 @@
+package eval
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Evaluator struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	stdin   *bufio.Reader
+	random  *rand.Rand
+	policy  *object.Policy
+	modules map[string]*object.Module
+	args    []string
+	calls   []string
+	yield   func(value object.Object) error
+
+	maxSteps int
+	steps    int
+
+	ctx       context.Context
+	evaluated int
+
+	// err is the last error evaluating a node failed with and errorPosition
+	// where that node is, the innermost one an error is returned from.
+	err           error
+	errorPosition lexer.Position
+}
+
+const interruptCheckInterval = 1024
+
+// maxCalls bounds how deep calls nest, as the VM's frames do.
+const maxCalls = 1024
+
+type Option func(evaluator *Evaluator)
+
+func WithStdout(stdout io.Writer) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.stdout = stdout
+	}
+}
+
+func WithStderr(stderr io.Writer) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.stderr = stderr
+	}
+}
+
+func WithStdin(stdin io.Reader) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.stdin = object.BufferedReader(stdin)
+	}
+}
+
+// WithRand makes the rand, randInt and seed builtins use random, so runs can
+// be reproduced by seeding it.
+func WithRand(random *rand.Rand) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.random = random
+	}
+}
+
+// WithPolicy restricts what builtins may do, see object.Policy.
+func WithPolicy(policy *object.Policy) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.policy = policy
+	}
+}
+
+// WithModules sets the modules `import` statements bind, by name.
+func WithModules(modules map[string]*object.Module) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.modules = modules
+	}
+}
+
+// WithArgs sets the command line arguments returned by the args builtin.
+func WithArgs(args []string) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.args = args
+	}
+}
+
+// WithMaxSteps stops evaluation with an error once more than steps nodes
+// were evaluated. Zero means no limit.
+func WithMaxSteps(steps int) Option {
+	return func(evaluator *Evaluator) {
+		evaluator.maxSteps = steps
+	}
+}
+
+func New(options ...Option) *Evaluator {
+	evaluator := &Evaluator{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		stdin:  object.Stdin,
+		policy: object.DefaultPolicy(),
+	}
+	for _, option := range options {
+		option(evaluator)
+	}
+
+	return evaluator
+}
+
+// Eval evaluates node with a default evaluator writing to the process's
+// standard streams.
+func Eval(node ast.Node, environment *object.Environment) (object.Object, error) {
+	return New().Eval(node, environment)
+}
+
+// EvalContext evaluates node like Eval, stopping with the context's error
+// once the context is cancelled or its deadline passes.
+func (evaluator *Evaluator) EvalContext(ctx context.Context, node ast.Node, environment *object.Environment) (object.Object, error) {
+	evaluator.ctx = ctx
+	defer func() { evaluator.ctx = nil }()
+
+	return evaluator.Eval(node, environment)
+}
+
+// ErrorPosition returns where in the source the node that failed the last
+// evaluation is.
+func (evaluator *Evaluator) ErrorPosition() (lexer.Position, bool) {
+	return evaluator.errorPosition, evaluator.errorPosition.Line > 0
+}
+
+// RuntimeError returns err, which the last evaluation failed with, as a
+// *diagnostic.RuntimeError at ErrorPosition, or at the span of a
+// *diagnostic.Error. Exiting is not an error, an *object.ExitError is
+// returned as it is.
+func (evaluator *Evaluator) RuntimeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, exit := errors.Cause(err).(*object.ExitError); exit {
+		return err
+	}
+
+	located, ok := err.(*diagnostic.Error)
+	if !ok {
+		return diagnostic.NewRuntimeError(err, evaluator.errorPosition)
+	}
+
+	runtimeError := diagnostic.NewRuntimeError(errors.New(located.Message), located.Start)
+	runtimeError.End = located.End
+
+	return runtimeError
+}
+
+func (evaluator *Evaluator) Stdout() io.Writer {
+	return evaluator.stdout
+}
+
+func (evaluator *Evaluator) Stderr() io.Writer {
+	return evaluator.stderr
+}
+
+func (evaluator *Evaluator) Rand() *rand.Rand {
+	if evaluator.random == nil {
+		evaluator.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return evaluator.random
+}
+
+func (evaluator *Evaluator) Stdin() *bufio.Reader {
+	return evaluator.stdin
+}
+
+func (evaluator *Evaluator) Context() context.Context {
+	if evaluator.ctx == nil {
+		return context.Background()
+	}
+
+	return evaluator.ctx
+}
+
+func (evaluator *Evaluator) Policy() *object.Policy {
+	return evaluator.policy
+}
+
+func (evaluator *Evaluator) Args() []string {
+	return evaluator.args
+}
+
+func (evaluator *Evaluator) CallDepth() int {
+	return len(evaluator.calls)
+}
+
+func (evaluator *Evaluator) StackTrace() []string {
+	trace := make([]string, 0, len(evaluator.calls)+1)
+	for i := len(evaluator.calls) - 1; i >= 0; i-- {
+		trace = append(trace, evaluator.calls[i])
+	}
+
+	return append(trace, object.TopLevelFrameName)
+}
+
+// Call lets builtins apply functions passed to them as arguments.
+func (evaluator *Evaluator) Call(function object.Object, args ...object.Object) (object.Object, error) {
+	return evaluator.applyFunction(function, args)
+}
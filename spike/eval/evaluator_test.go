@@ -0,0 +1,261 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Evaluator_printToConfiguredStdout(t *testing.T) {
+	input := `let f = fn(x) { printf("x = %d;", x) }; f(5); print(format("%v", "done"))`
+	stdout := &strings.Builder{}
+
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	assert.NoError(t, err)
+
+	result, err := New(WithStdout(stdout)).Eval(program, object.NewEnvironment())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.NullObject, result)
+	assert.Equal(t, "x = 5;done", stdout.String())
+}
+
+func Test_Evaluator_printToConfiguredStderr(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`eprint("a"); eprintln([1], "b"); println("out")`))).ParseProgram()
+	assert.NoError(t, err)
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+
+	_, err = New(WithStdout(stdout), WithStderr(stderr)).Eval(program, object.NewEnvironment())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "out\n", stdout.String())
+	assert.Equal(t, "a[1] b\n", stderr.String())
+}
+
+func Test_Evaluator_inputFromConfiguredStdin(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`input("> ")`))).ParseProgram()
+	assert.NoError(t, err)
+	stdout := &strings.Builder{}
+
+	result, err := New(WithStdin(strings.NewReader("spike\n")), WithStdout(stdout)).Eval(program, object.NewEnvironment())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.String{Value: "spike"}, result)
+	assert.Equal(t, "> ", stdout.String())
+}
+
+func Test_Evaluator_exitUnwindsNestedCalls(t *testing.T) {
+	input := `let f = fn() { map([1, 2], fn(x) { exit(x + 2) }) }; f(); print("not reached")`
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	assert.NoError(t, err)
+	stdout := &strings.Builder{}
+
+	_, err = New(WithStdout(stdout)).Eval(program, object.NewEnvironment())
+
+	assert.Equal(t, &object.ExitError{Code: 3}, err)
+	assert.Empty(t, stdout.String())
+}
+
+func Test_Evaluator_policyDeniesFileAccess(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`listDir(".")`))).ParseProgram()
+	assert.NoError(t, err)
+
+	_, err = New(WithPolicy(object.NewPolicy())).Eval(program, object.NewEnvironment())
+
+	assert.EqualError(t, err, "listDir: file read is not permitted by the sandbox policy")
+}
+
+func Test_Evaluator_policyDeniesBuiltins(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`map([1], fn(x) { now() })`))).ParseProgram()
+	assert.NoError(t, err)
+
+	_, err = New(WithPolicy(object.PurePolicy())).Eval(program, object.NewEnvironment())
+
+	assert.EqualError(t, err, "now is not permitted by the sandbox policy")
+}
+
+func Test_Evaluator_randomWithConfiguredSource(t *testing.T) {
+	input := `seed(11); let a = randInt(1, 1000); seed(11); [a == randInt(1, 1000), randInt(1, 1000)]`
+
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	assert.NoError(t, err)
+
+	first, err := New(WithRand(rand.New(rand.NewSource(1)))).Eval(program, object.NewEnvironment())
+	assert.NoError(t, err)
+
+	second, err := New().Eval(program, object.NewEnvironment())
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, &object.Boolean{Value: true}, first.(*object.Array).Elements[0])
+}
+
+func Test_Evaluator_maxSteps(t *testing.T) {
+	input := "let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } };\nlet x = f(500);"
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	assert.NoError(t, err)
+
+	_, err = New(WithMaxSteps(100)).Eval(program, object.NewEnvironment())
+	assert.EqualError(t, err, "step limit of 100 exceeded")
+
+	_, err = New(WithMaxSteps(100000)).Eval(program, object.NewEnvironment())
+	assert.NoError(t, err)
+}
+
+func Test_Evaluator_errorPosition(t *testing.T) {
+	testCases := []struct {
+		code             string
+		expectedPosition lexer.Position
+	}{
+		{
+			code:             "let x = {};\n1 + x[[1]]",
+			expectedPosition: lexer.Position{Line: 2, Column: 5, Offset: 16},
+		},
+		{
+			code:             "let f = fn(a) {\n  len(a)\n};\nf(1)",
+			expectedPosition: lexer.Position{Line: 2, Column: 3, Offset: 18},
+		},
+		{
+			code:             "map([1, 2], fn(x) { len(x) })",
+			expectedPosition: lexer.Position{Line: 1, Column: 21, Offset: 20},
+		},
+		{
+			code:             "let f = fn(a) { a };\n\nf()",
+			expectedPosition: lexer.Position{Line: 3, Column: 1, Offset: 22},
+		},
+		{
+			code:             "let x = 1;\nx + true",
+			expectedPosition: lexer.Position{Line: 2, Column: 3, Offset: 13},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.code))).ParseProgram()
+			assert.NoError(t, err)
+
+			evaluator := New()
+			_, err = evaluator.Eval(program, object.NewEnvironment())
+			assert.Error(t, err)
+
+			position, ok := evaluator.ErrorPosition()
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expectedPosition, position)
+			assert.EqualError(t, evaluator.RuntimeError(err), fmt.Sprintf("%s at %s", err, position))
+		})
+	}
+}
+
+func Test_Evaluator_runtimeErrorKeepsExit(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader("exit(2)"))).ParseProgram()
+	assert.NoError(t, err)
+
+	evaluator := New()
+	_, err = evaluator.Eval(program, object.NewEnvironment())
+
+	assert.Equal(t, &object.ExitError{Code: 2}, evaluator.RuntimeError(err))
+}
+
+func Test_Evaluator_evalContextStopsWhenCancelled(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(
+		"let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) + f(n - 1) } };\nf(40)",
+	))).ParseProgram()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = New().EvalContext(ctx, program, object.NewEnvironment())
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_Evaluator_sleepStopsWhenCancelled(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`sleep(60000)`))).ParseProgram()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err = New().EvalContext(ctx, program, object.NewEnvironment())
+
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+	assert.True(t, time.Since(started) < 10*time.Second)
+}
+
+func Test_Evaluator_generatorsStop(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(
+		"let g = fn*() { yield 1; yield 2 }; let it = g(); next(it); next(g())",
+	))).ParseProgram()
+	assert.NoError(t, err)
+
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	environment := object.NewEnvironment()
+	_, err = New().EvalContext(ctx, program, environment)
+	assert.NoError(t, err)
+	assert.True(t, runtime.NumGoroutine() <= goroutines+2)
+
+	// The generator left behind by next(g()) stops once it is collected.
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > goroutines+1 && time.Now().Before(deadline); {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, runtime.NumGoroutine() <= goroutines+1)
+
+	// The one bound to it stops with the context.
+	cancel()
+	it, err := environment.Get("it")
+	assert.NoError(t, err)
+	_, _, err = it.(object.Iterator).Next()
+	assert.Equal(t, context.Canceled, err)
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > goroutines && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, runtime.NumGoroutine() <= goroutines)
+}
+
+func Test_Evaluator_modules(t *testing.T) {
+	modules := map[string]*object.Module{
+		"strings": {Name: "strings", Members: map[string]object.Object{"upper": object.GetBuiltinByName("upper")}},
+	}
+	program, err := parser.New(lexer.New(strings.NewReader(`import "strings"; strings.upper("spike")`))).ParseProgram()
+	assert.NoError(t, err)
+
+	result, err := New(WithModules(modules)).Eval(program, object.NewEnvironment())
+	assert.NoError(t, err)
+	assert.Equal(t, &object.String{Value: "SPIKE"}, result)
+
+	program, err = parser.New(lexer.New(strings.NewReader(`import "strings"; strings.lower`))).ParseProgram()
+	assert.NoError(t, err)
+
+	_, err = New(WithModules(modules)).Eval(program, object.NewEnvironment())
+	assert.EqualError(t, err, "module strings has no member lower")
+
+	_, err = New().Eval(program, object.NewEnvironment())
+	assert.EqualError(t, err, "unknown module strings")
+}
+
+func Test_Evaluator_argsBuiltin(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`[len(args()), args()[1]]`))).ParseProgram()
+	assert.NoError(t, err)
+
+	result, err := New(WithArgs([]string{"a", "b"})).Eval(program, object.NewEnvironment())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.Integer{Value: 2},
+		&object.String{Value: "b"},
+	}}, result)
+}
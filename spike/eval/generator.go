@@ -0,0 +1,142 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+
+	"github.com/pkg/errors"
+)
+
+// generator runs the body of a generator function on its own goroutine, which
+// hands control back and forth with the caller of Next so only one of them
+// runs at a time. The goroutine ends with the body, once the context of the
+// evaluation that called the generator function is done, or once the
+// generator is garbage collected. A generator its own body refers to, like
+// one bound to a global, is not collected before its body ends, so it keeps
+// its goroutine until the context is done.
+type generator struct {
+	evaluator *Evaluator
+	body      *generatorBody
+	started   bool
+	running   bool
+	done      bool
+}
+
+// generatorBody is what the goroutine of a generator uses. It must not refer
+// to the generator, or the generator would never be collected.
+type generatorBody struct {
+	block       ast.Statement
+	environment *object.Environment
+	ctx         context.Context
+	resume      chan struct{}
+	results     chan generatorResult
+	// stop is closed once the generator is collected.
+	stop chan struct{}
+}
+
+type generatorResult struct {
+	value object.Object
+	err   error
+	done  bool
+}
+
+// errGeneratorStopped unwinds the body of a generator that is stopped while
+// it waits to be resumed.
+var errGeneratorStopped = errors.New("generator stopped")
+
+func newGenerator(evaluator *Evaluator, function *object.Function, environment *object.Environment) *generator {
+	return &generator{
+		evaluator: evaluator,
+		body: &generatorBody{
+			block:       function.Body,
+			environment: environment,
+			ctx:         evaluator.Context(),
+			resume:      make(chan struct{}),
+			results:     make(chan generatorResult),
+			stop:        make(chan struct{}),
+		},
+	}
+}
+
+func (generator *generator) Type() object.ObjectType {
+	return object.IteratorType
+}
+
+func (generator *generator) Inspect() string {
+	return fmt.Sprintf("generator[%p]", generator)
+}
+
+func (generator *generator) Equal(other object.Object) bool {
+	return other == generator
+}
+
+func (generator *generator) Iterate() object.Iterator {
+	return generator
+}
+
+func (generator *generator) Next() (object.Object, bool, error) {
+	if generator.done {
+		return nil, false, nil
+	}
+	if generator.running {
+		return nil, false, errors.New("generator is already running")
+	}
+
+	body := generator.body
+	if generator.started {
+		select {
+		case body.resume <- struct{}{}:
+		case <-body.ctx.Done():
+			generator.done = true
+			return nil, false, body.ctx.Err()
+		}
+	} else {
+		generator.started = true
+		runtime.SetFinalizer(generator, stopCollected)
+
+		evaluator := *generator.evaluator
+		evaluator.calls = append([]string{}, generator.evaluator.calls...)
+		go body.run(evaluator)
+	}
+
+	generator.running = true
+	result := <-body.results
+	generator.running = false
+
+	if result.done {
+		generator.done = true
+		return nil, false, result.err
+	}
+
+	return result.value, true, nil
+}
+
+// stopCollected stops the body of a generator nothing refers to any more.
+func stopCollected(generator *generator) {
+	close(generator.body.stop)
+}
+
+func (body *generatorBody) run(evaluator Evaluator) {
+	evaluator.ctx = body.ctx
+	evaluator.yield = func(value object.Object) error {
+		body.results <- generatorResult{value: value}
+
+		select {
+		case <-body.resume:
+			return nil
+		case <-body.ctx.Done():
+			return errGeneratorStopped
+		case <-body.stop:
+			return errGeneratorStopped
+		}
+	}
+
+	_, err := evaluator.Eval(body.block, body.environment)
+	if err == errGeneratorStopped {
+		return
+	}
+	body.results <- generatorResult{err: err, done: true}
+}
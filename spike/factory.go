@@ -0,0 +1,28 @@
+package spike
+
+// Factory creates engines configured alike, for each goroutine or request to
+// run scripts on its own engine. It is safe for concurrent use:
+//
+//	factory := spike.NewFactory(spike.WithModule("db", functions))
+//	pool := sync.Pool{New: factory.New}
+//	engine := pool.Get().(*spike.Engine)
+//	defer func() { engine.Reset(); pool.Put(engine) }()
+//
+// Options are applied to every engine, so writers, readers and policies given
+// to them are shared by the engines.
+type Factory struct {
+	options []Option
+}
+
+func NewFactory(options ...Option) *Factory {
+	return &Factory{options: append([]Option{}, options...)}
+}
+
+func (factory *Factory) NewEngine() *Engine {
+	return NewEngine(factory.options...)
+}
+
+// New returns a new engine like NewEngine, typed to be sync.Pool's New.
+func (factory *Factory) New() interface{} {
+	return factory.NewEngine()
+}
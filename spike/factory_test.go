@@ -0,0 +1,71 @@
+package spike
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/object"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race to check engines from one factory share no mutable state.
+func Test_Factory_concurrentEngines(t *testing.T) {
+	factory := NewFactory(
+		WithPolicy(object.PurePolicy()),
+		WithModule("math", map[string]Builtin{
+			"square": func(args ...object.Object) (object.Object, error) {
+				value := args[0].(*object.Integer).Value
+				return &object.Integer{Value: value * value}, nil
+			},
+		}),
+	)
+	pool := sync.Pool{New: factory.New}
+
+	var wg sync.WaitGroup
+	results := make([]object.Object, 16)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			engine := pool.Get().(*Engine)
+			defer func() {
+				engine.Reset()
+				pool.Put(engine)
+			}()
+
+			_, errs[i] = engine.Eval(fmt.Sprintf(`import "math"; let n = %d; let g = fn*() { yield math.square(n) };`, i))
+			if errs[i] != nil {
+				return
+			}
+			results[i], errs[i] = engine.Eval(`[next(g()), regexMatch("^[0-9]+$", format("%d", n)), map([n], fn(x) { x + 1 })[0]]`)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, &object.Array{Elements: []object.Object{
+			&object.Integer{Value: int64(i * i)},
+			&object.True,
+			&object.Integer{Value: int64(i + 1)},
+		}}, result)
+	}
+}
+
+func Test_Engine_Reset(t *testing.T) {
+	engine := NewEngine(WithBuiltin("answer", func(args ...object.Object) (object.Object, error) {
+		return &object.Integer{Value: 42}, nil
+	}))
+	_, err := engine.Eval(`let x = answer();`)
+	assert.NoError(t, err)
+
+	engine.Reset()
+
+	_, err = engine.Eval(`x`)
+	assert.EqualError(t, err, "unable to resolve identifier: x at 1:1")
+	result, err := engine.Eval(`let y = answer(); y`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Integer{Value: 42}, result)
+}
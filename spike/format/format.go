@@ -0,0 +1,384 @@
+package format
+
+import (
+	"sort"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const indentation = "    "
+
+// Operator precedences, following the parser's.
+const (
+	lowest = iota
+	assign
+	alternative
+	conjunction
+	inequality
+	equals
+	sum
+	product
+	prefix
+	postfix
+	atom
+)
+
+var precedences = map[string]int{
+	"||": alternative,
+	"&&": conjunction,
+	"<":  inequality,
+	">":  inequality,
+	"<=": inequality,
+	">=": inequality,
+	"==": equals,
+	"!=": equals,
+	"+":  sum,
+	"-":  sum,
+	"*":  product,
+	"/":  product,
+}
+
+// Source formats a whole source file, keeping its `#!` line.
+func Source(source string) (string, error) {
+	program, err := parser.New(lexer.New(strings.NewReader(source), lexer.WithComments()), parser.WithComments()).ParseProgram()
+	if err != nil {
+		return "", err
+	}
+
+	shebang := ""
+	if strings.HasPrefix(source, "#!") {
+		shebang = strings.TrimRight(strings.SplitN(source, "\n", 2)[0], " \t\r") + "\n"
+	}
+
+	return shebang + Program(program), nil
+}
+
+// Program formats program with four spaces of indentation, one statement per
+// line and no more than one blank line in a row. Comments are kept when the
+// program was parsed with parser.WithComments. The ones inside an expression
+// are moved after the statement holding them.
+func Program(program *ast.Program) string {
+	comments := append([]*ast.Comment{}, program.Comments...)
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Pos().Offset < comments[j].Pos().Offset
+	})
+
+	formatter := newFormatter(program.Attached, comments)
+	formatter.statements(program.Statements, false)
+	formatter.flushComments(-1)
+
+	return formatter.out.String()
+}
+
+type formatter struct {
+	out      strings.Builder
+	indent   int
+	attached map[ast.Statement]*ast.Comments
+	comments []*ast.Comment
+	printed  map[*ast.Comment]bool
+	// lastLine is the source line of what was printed last, to keep one
+	// blank line where the source had some.
+	lastLine int
+}
+
+func newFormatter(attached map[ast.Statement]*ast.Comments, comments []*ast.Comment) *formatter {
+	return &formatter{
+		attached: attached,
+		comments: comments,
+		printed:  map[*ast.Comment]bool{},
+	}
+}
+
+// statements prints each statement on its own lines. In blocks the last
+// expression statement is the block's value and is left without a semicolon.
+func (formatter *formatter) statements(statements []ast.Statement, block bool) {
+	for i, statement := range statements {
+		start := statement.Pos()
+		comments := formatter.attached[statement]
+		if comments != nil && len(comments.Leading) > 0 {
+			start = comments.Leading[0].Pos()
+		}
+
+		formatter.flushComments(start.Offset)
+
+		if comments != nil {
+			for _, comment := range comments.Leading {
+				formatter.comment(comment)
+			}
+		}
+
+		formatter.separate(statement.Pos().Line)
+		formatter.line()
+		formatter.statement(statement, block && i == len(statements)-1)
+		formatter.advance(statement.End().Line)
+
+		if comments != nil && comments.Trailing != nil {
+			formatter.out.WriteString(" ")
+			formatter.out.WriteString(comments.Trailing.Token.Literal)
+			formatter.printed[comments.Trailing] = true
+		}
+		formatter.out.WriteString("\n")
+	}
+}
+
+// flushComments prints the comments before offset that were not printed yet,
+// all of them when offset is negative.
+func (formatter *formatter) flushComments(offset int) {
+	for _, comment := range formatter.comments {
+		if offset >= 0 && comment.Pos().Offset >= offset {
+			return
+		}
+		if !formatter.printed[comment] {
+			formatter.comment(comment)
+		}
+	}
+}
+
+func (formatter *formatter) comment(comment *ast.Comment) {
+	formatter.separate(comment.Pos().Line)
+	formatter.line()
+	formatter.out.WriteString(comment.Token.Literal)
+	formatter.out.WriteString("\n")
+	formatter.printed[comment] = true
+	formatter.advance(comment.Pos().Line)
+}
+
+// separate prints a blank line when the source had some before line.
+func (formatter *formatter) separate(line int) {
+	if formatter.lastLine > 0 && line > formatter.lastLine+1 {
+		formatter.out.WriteString("\n")
+	}
+}
+
+// advance records line as printed. Comments moved out of expressions come
+// from earlier lines, which must not add blank lines later on.
+func (formatter *formatter) advance(line int) {
+	if line > formatter.lastLine {
+		formatter.lastLine = line
+	}
+}
+
+func (formatter *formatter) line() {
+	formatter.out.WriteString(strings.Repeat(indentation, formatter.indent))
+}
+
+func (formatter *formatter) statement(statement ast.Statement, last bool) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		formatter.out.WriteString("let " + statement.Name.Value + " = ")
+		formatter.expression(statement.Value, lowest)
+		formatter.out.WriteString(";")
+
+	case *ast.DestructuringLetStatement:
+		names := make([]string, len(statement.Names))
+		for i, name := range statement.Names {
+			names[i] = name.Value
+		}
+		formatter.out.WriteString("let " + strings.Join(names, ", ") + " = ")
+		formatter.expression(statement.Value, lowest)
+		formatter.out.WriteString(";")
+
+	case *ast.ReturnStatement:
+		formatter.out.WriteString("return")
+		if statement.Result != nil {
+			formatter.out.WriteString(" ")
+			formatter.expression(statement.Result, lowest)
+		}
+		formatter.out.WriteString(";")
+
+	case *ast.EnumStatement:
+		members := make([]string, len(statement.Members))
+		for i, member := range statement.Members {
+			members[i] = member.Value
+		}
+		formatter.out.WriteString("enum " + statement.Name.Value + " { " + strings.Join(members, ", ") + " }")
+
+	case *ast.ImportStatement:
+		formatter.out.WriteString("import " + statement.Module.String() + ";")
+
+	case *ast.ExpressionStatement:
+		formatter.expression(statement.Expression, lowest)
+		if _, ok := statement.Expression.(*ast.IfExpression); !ok && !last {
+			formatter.out.WriteString(";")
+		}
+
+	case *ast.BlockStatement:
+		formatter.block(statement)
+
+	default:
+		panic(errors.Errorf("format: unexpected statement %T", statement))
+	}
+}
+
+// block prints a block over several lines, or on one line when it holds a
+// single short expression, as in `fn(x) { x * 2 }`.
+func (formatter *formatter) block(block *ast.BlockStatement) {
+	if len(block.Statements) == 0 {
+		formatter.out.WriteString("{}")
+		return
+	}
+
+	if inline, ok := formatter.inline(block); ok {
+		formatter.out.WriteString("{ " + inline + " }")
+		return
+	}
+
+	formatter.out.WriteString("{\n")
+	formatter.lastLine = block.Pos().Line
+	formatter.indent++
+	formatter.statements(block.Statements, true)
+	formatter.flushComments(block.Closing.Offset)
+	formatter.indent--
+	formatter.line()
+	formatter.out.WriteString("}")
+}
+
+func (formatter *formatter) inline(block *ast.BlockStatement) (string, bool) {
+	if len(block.Statements) != 1 || formatter.attached[block.Statements[0]] != nil {
+		return "", false
+	}
+	statement, ok := block.Statements[0].(*ast.ExpressionStatement)
+	if !ok || block.Pos().Line != block.Closing.Line {
+		return "", false
+	}
+
+	// Anything holding comments takes several lines, so the inner formatter
+	// does not need to track them.
+	inner := newFormatter(formatter.attached, nil)
+	inner.expression(statement.Expression, lowest)
+	text := inner.out.String()
+
+	return text, !strings.Contains(text, "\n")
+}
+
+func (formatter *formatter) expression(expression ast.Expression, minimum int) {
+	parenthesized := precedence(expression) < minimum
+	if parenthesized {
+		formatter.out.WriteString("(")
+	}
+
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		formatter.out.WriteString(expression.Value)
+
+	case *ast.Integer, *ast.Float, *ast.Boolean:
+		formatter.out.WriteString(expression.TokenLiteral())
+
+	case *ast.String:
+		formatter.out.WriteString(`"` + expression.Value + `"`)
+
+	case *ast.PrefixExpression:
+		formatter.out.WriteString(expression.Operator)
+		formatter.expression(expression.Right, prefix)
+
+	case *ast.InfixExpression:
+		operator := precedences[expression.Operator]
+		formatter.expression(expression.Left, operator)
+		formatter.out.WriteString(" " + expression.Operator + " ")
+		formatter.expression(expression.Right, operator+1)
+
+	case *ast.AssignExpression:
+		formatter.expression(expression.Target, postfix)
+		formatter.out.WriteString(" = ")
+		formatter.expression(expression.Value, assign)
+
+	case *ast.CallExpression:
+		formatter.expression(expression.Function, postfix)
+		formatter.out.WriteString("(")
+		formatter.list(expression.Arguments)
+		formatter.out.WriteString(")")
+
+	case *ast.IndexExpression:
+		formatter.expression(expression.Array, postfix)
+		formatter.out.WriteString("[")
+		formatter.expression(expression.Index, lowest)
+		formatter.out.WriteString("]")
+
+	case *ast.MemberExpression:
+		formatter.expression(expression.Object, postfix)
+		formatter.out.WriteString("." + expression.Member.Value)
+
+	case *ast.Array:
+		formatter.out.WriteString("[")
+		formatter.list(expression.Elements)
+		formatter.out.WriteString("]")
+
+	case *ast.Hash:
+		formatter.out.WriteString("{")
+		for i, key := range expression.Keys {
+			if i > 0 {
+				formatter.out.WriteString(", ")
+			}
+			formatter.expression(key, lowest)
+			formatter.out.WriteString(": ")
+			formatter.expression(expression.Pairs[key], lowest)
+		}
+		formatter.out.WriteString("}")
+
+	case *ast.Tuple:
+		formatter.list(expression.Elements)
+
+	case *ast.YieldExpression:
+		formatter.out.WriteString("yield ")
+		formatter.expression(expression.Value, lowest)
+
+	case *ast.FunctionExpression:
+		formatter.out.WriteString("fn")
+		if expression.Generator {
+			formatter.out.WriteString("*")
+		}
+		parameters := make([]string, len(expression.Parameters))
+		for i, parameter := range expression.Parameters {
+			parameters[i] = parameter.Value
+		}
+		formatter.out.WriteString("(" + strings.Join(parameters, ", ") + ") ")
+		formatter.statement(expression.Body, false)
+
+	case *ast.IfExpression:
+		formatter.out.WriteString("if (")
+		formatter.expression(expression.Condition, lowest)
+		formatter.out.WriteString(") ")
+		formatter.statement(expression.Then, false)
+		if expression.Else != nil {
+			formatter.out.WriteString(" else ")
+			formatter.statement(expression.Else, false)
+		}
+
+	default:
+		panic(errors.Errorf("format: unexpected expression %T", expression))
+	}
+
+	if parenthesized {
+		formatter.out.WriteString(")")
+	}
+}
+
+func (formatter *formatter) list(expressions []ast.Expression) {
+	for i, expression := range expressions {
+		if i > 0 {
+			formatter.out.WriteString(", ")
+		}
+		formatter.expression(expression, lowest)
+	}
+}
+
+func precedence(expression ast.Expression) int {
+	switch expression := expression.(type) {
+	case *ast.Tuple, *ast.YieldExpression:
+		return lowest
+	case *ast.AssignExpression:
+		return assign
+	case *ast.InfixExpression:
+		return precedences[expression.Operator]
+	case *ast.PrefixExpression:
+		return prefix
+	case *ast.CallExpression, *ast.IndexExpression, *ast.MemberExpression:
+		return postfix
+	default:
+		return atom
+	}
+}
@@ -0,0 +1,89 @@
+package format
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/parser/ast/asttest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Source(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "statements",
+			input:    "let  x=1\nx+2\nreturn x , 2;enum Color{Red,Green,}\nimport   \"db\"",
+			expected: "let x = 1;\nx + 2;\nreturn x, 2;\nenum Color { Red, Green }\nimport \"db\";\n",
+		},
+		{
+			name:     "shebang",
+			input:    "#!/usr/bin/env spike run\nlet x=1",
+			expected: "#!/usr/bin/env spike run\nlet x = 1;\n",
+		},
+		{
+			name:     "blank lines",
+			input:    "let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;\n",
+			expected: "let a = 1;\n\nlet b = 2;\nlet c = 3;\n",
+		},
+		{
+			name:     "blocks",
+			input:    "let f = fn(a,b){\nlet c = a + b\nc * 2;\n};\nlet g = fn*(){yield 1}\nif (f(1, 2) > 3) { 1 } else {\n2\n}",
+			expected: "let f = fn(a, b) {\n    let c = a + b;\n    c * 2\n};\nlet g = fn*() { yield 1 };\nif (f(1, 2) > 3) { 1 } else {\n    2\n}\n",
+		},
+		{
+			name:     "empty block",
+			input:    "fn() {}",
+			expected: "fn() {};\n",
+		},
+		{
+			name:     "parentheses",
+			input:    "(1 + 2) * 3 - (4 - 5); -(a + b); (a < b) == c; a < (b == c); x = (y = 1); !(f)(x)",
+			expected: "(1 + 2) * 3 - (4 - 5);\n-(a + b);\n(a < b) == c;\na < b == c;\nx = y = 1;\n!f(x);\n",
+		},
+		{
+			name:     "literals",
+			input:    `[1,"a",true,2.5];{"k":[],1:{}}[0];Color.Red;a[1][2]`,
+			expected: "[1, \"a\", true, 2.5];\n{\"k\": [], 1: {}}[0];\nColor.Red;\na[1][2];\n",
+		},
+		{
+			name:     "comments",
+			input:    "// header\n\nlet x = 1; // one\nlet f = fn() {\n  // inside\n  x\n  // before brace\n};\nlet h = {\n  // in hash\n  \"a\": 1\n};\n// end\n",
+			expected: "// header\n\nlet x = 1; // one\nlet f = fn() {\n    // inside\n    x\n    // before brace\n};\nlet h = {\"a\": 1};\n// in hash\n// end\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			formatted, err := Source(testCase.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, formatted)
+
+			again, err := Source(formatted)
+			assert.NoError(t, err)
+			assert.Equal(t, formatted, again)
+
+			asttest.AssertEqual(t, parse(t, testCase.input), parse(t, formatted))
+		})
+	}
+}
+
+func Test_Source_error(t *testing.T) {
+	_, err := Source("let = 1")
+
+	assert.EqualError(t, err, "expected identifier, got assign at 1:5")
+}
+
+func parse(t *testing.T, source string) *ast.Program {
+	program, err := parser.New(lexer.New(strings.NewReader(source))).ParseProgram()
+	assert.NoError(t, err)
+
+	return program
+}
@@ -0,0 +1,73 @@
+package kernel
+
+import (
+	"encoding/json"
+	"spike-interpreter-go/spike/object"
+)
+
+func mimeBundle(value object.Object) map[string]string {
+	data := map[string]string{
+		MimeTextPlain: value.Inspect(),
+	}
+
+	native, ok := toJSON(value)
+	if !ok {
+		return data
+	}
+
+	encoded, err := json.Marshal(native)
+	if err == nil {
+		data[MimeJSON] = string(encoded)
+	}
+
+	return data
+}
+
+// toJSON converts values that have a natural JSON form. Functions and hashes
+// with non-string keys have none.
+func toJSON(value object.Object) (interface{}, bool) {
+	switch value := value.(type) {
+	case *object.Integer:
+		return value.Value, true
+
+	case *object.String:
+		return value.Value, true
+
+	case *object.Boolean:
+		return value.Value, true
+
+	case *object.Null:
+		return nil, true
+
+	case *object.Array:
+		elements := make([]interface{}, len(value.Elements))
+		for i, element := range value.Elements {
+			native, ok := toJSON(element)
+			if !ok {
+				return nil, false
+			}
+			elements[i] = native
+		}
+
+		return elements, true
+
+	case *object.Hash:
+		pairs := make(map[string]interface{}, len(value.Pairs))
+		for _, pair := range value.Pairs {
+			key, ok := pair.Key.(*object.String)
+			if !ok {
+				return nil, false
+			}
+
+			native, ok := toJSON(pair.Value)
+			if !ok {
+				return nil, false
+			}
+			pairs[key.Value] = native
+		}
+
+		return pairs, true
+	}
+
+	return nil, false
+}
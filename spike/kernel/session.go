@@ -0,0 +1,120 @@
+// Package kernel exposes a cell-by-cell evaluation API for driving spike from
+// notebook frontends. A Session keeps globals, constants and symbols between
+// executions the way the REPL does.
+package kernel
+
+import (
+	"context"
+	"math/rand"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/vm"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MimeTextPlain = "text/plain"
+	MimeJSON      = "application/json"
+)
+
+type Result struct {
+	ExecutionCount int
+	Value          object.Object
+	Output         string
+	Data           map[string]string
+}
+
+type Session struct {
+	executing sync.Mutex
+
+	session        *vm.Session
+	random         *rand.Rand
+	executionCount int
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewSession() *Session {
+	symbolTable := compiler.NewSymbolTable()
+	for i, builtin := range object.Builtins {
+		symbolTable.DefineBuiltin(i, builtin.Name)
+	}
+
+	return &Session{
+		session: vm.NewSession(symbolTable),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Execute runs a single cell. Cells are executed one at a time; the returned
+// result is never nil, so output written before a runtime error is kept.
+func (session *Session) Execute(ctx context.Context, source string) (*Result, error) {
+	session.executing.Lock()
+	defer session.executing.Unlock()
+
+	session.executionCount++
+	result := &Result{
+		ExecutionCount: session.executionCount,
+		Data:           map[string]string{},
+	}
+
+	program, err := parser.New(lexer.New(strings.NewReader(source))).ParseProgram()
+	if err != nil {
+		return result, err
+	}
+
+	compiled, err := session.session.Compile(program)
+	if err != nil {
+		return result, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	session.setCancel(cancel)
+	defer session.setCancel(nil)
+
+	output := &strings.Builder{}
+	machine, err := session.session.Run(ctx, compiled, vm.WithStdout(output), vm.WithRand(session.random))
+	result.Output = output.String()
+	if err != nil {
+		return result, err
+	}
+
+	if program.EndsWithExpression() {
+		result.Value = machine.LastPoppedStackElement()
+		result.Data = mimeBundle(result.Value)
+	}
+
+	return result, nil
+}
+
+// Interrupt stops the currently executing cell, if any. Globals the cell
+// assigned before the interruption keep their values, while the names it
+// defined are forgotten like those of any failing cell.
+func (session *Session) Interrupt() {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	if session.cancel != nil {
+		session.cancel()
+	}
+}
+
+func (session *Session) ExecutionCount() int {
+	session.executing.Lock()
+	defer session.executing.Unlock()
+
+	return session.executionCount
+}
+
+func (session *Session) setCancel(cancel context.CancelFunc) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	session.cancel = cancel
+}
@@ -0,0 +1,142 @@
+package kernel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Session_keepsStateBetweenCells(t *testing.T) {
+	session := NewSession()
+
+	_, err := session.Execute(context.Background(), `let greet = fn(name) { "hello " + name };`)
+	assert.NoError(t, err)
+
+	_, err = session.Execute(context.Background(), `let x = [1, "a"];`)
+	assert.NoError(t, err)
+
+	result, err := session.Execute(context.Background(), `println(greet("spike")); push(x, {"b": true})`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, result.ExecutionCount)
+	assert.Equal(t, "hello spike\n", result.Output)
+	assert.Equal(t, map[string]string{
+		MimeTextPlain: `[1, "a", {"b": true}]`,
+		MimeJSON:      `[1,"a",{"b":true}]`,
+	}, result.Data)
+}
+
+func Test_Session_richResults(t *testing.T) {
+	testCases := []struct {
+		source       string
+		expectedData map[string]string
+	}{
+		{
+			source:       `"text"`,
+			expectedData: map[string]string{MimeTextPlain: `"text"`, MimeJSON: `"text"`},
+		},
+		{
+			source:       `{1: 2}`,
+			expectedData: map[string]string{MimeTextPlain: `{1: 2}`},
+		},
+		{
+			source:       `fn(x) { x }`,
+			expectedData: map[string]string{MimeTextPlain: `Closure[`},
+		},
+		{
+			source:       ``,
+			expectedData: map[string]string{},
+		},
+		{
+			source:       `let a = 1;`,
+			expectedData: map[string]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.source, func(t *testing.T) {
+			result, err := NewSession().Execute(context.Background(), testCase.source)
+
+			assert.NoError(t, err)
+			for mime, expected := range testCase.expectedData {
+				assert.Contains(t, result.Data[mime], expected)
+			}
+			assert.Len(t, result.Data, len(testCase.expectedData))
+		})
+	}
+}
+
+func Test_Session_errorsKeepSessionUsable(t *testing.T) {
+	session := NewSession()
+
+	_, err := session.Execute(context.Background(), `let a = 5;`)
+	assert.NoError(t, err)
+
+	result, err := session.Execute(context.Background(), `print("before"); len(1)`)
+	assert.EqualError(t, err, "len: argument of type integer is not supported")
+	assert.Equal(t, "before", result.Output)
+
+	_, err = session.Execute(context.Background(), `let = ;`)
+	assert.Error(t, err)
+
+	result, err = session.Execute(context.Background(), `a * 2`)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", result.Data[MimeTextPlain])
+	assert.Equal(t, 4, session.ExecutionCount())
+}
+
+func Test_Session_cancelledContextStopsExecution(t *testing.T) {
+	session := NewSession()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	source := `
+		let countdown = fn(n) { if (n == 0) { 0 } else { countdown(n - 1) } };
+		countdown(500)
+	`
+	_, err := session.Execute(ctx, source)
+
+	assert.Equal(t, context.Canceled, err)
+
+	session.Interrupt()
+	result, err := session.Execute(context.Background(), source)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", result.Data[MimeTextPlain])
+}
+
+func Test_Session_interruptedCellForgetsItsNames(t *testing.T) {
+	session := NewSession()
+	_, err := session.Execute(context.Background(), `let a = 5;`)
+	assert.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := session.Execute(context.Background(), `let b = 1; let a = sleep(60000);`)
+		done <- err
+	}()
+
+	// The cell may not have started when interrupting the first time.
+	interrupt := time.NewTicker(time.Millisecond)
+	defer interrupt.Stop()
+	for err == nil {
+		select {
+		case err = <-done:
+		case <-interrupt.C:
+			session.Interrupt()
+		}
+	}
+	assert.Equal(t, context.Canceled, err)
+
+	result, err := session.Execute(context.Background(), `a`)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", result.Data[MimeTextPlain])
+	_, err = session.Execute(context.Background(), `b`)
+	assert.EqualError(t, err, "unable to resolve identifier: b at 1:1")
+
+	_, err = session.Execute(context.Background(), `let c = d;`)
+	assert.EqualError(t, err, "unable to resolve identifier: d at 1:9")
+	_, err = session.Execute(context.Background(), `c`)
+	assert.EqualError(t, err, "unable to resolve identifier: c at 1:1")
+}
@@ -0,0 +1,37 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a lexing error. Line holds the text of the source line the error
+// occurred on, so it can be shown with Snippet. Of very long lines only the
+// part around the error is kept, without the first droppedCharacters.
+type Error struct {
+	Message string
+	Position
+	Line string
+
+	droppedCharacters int
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s at %s", err.Message, err.Position)
+}
+
+// Span returns the position of the offending character and the one after it.
+func (err *Error) Span() (Position, Position) {
+	end := err.Position
+	end.Column++
+
+	return err.Position, end
+}
+
+// Snippet returns the source line followed by a caret under the column the
+// error occurred at.
+func (err *Error) Snippet() string {
+	indent := err.Column - 1 - err.droppedCharacters
+
+	return fmt.Sprintf("%s\n%s^", err.Line, strings.Repeat(" ", indent))
+}
@@ -0,0 +1,39 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzLexer(f *testing.F) {
+	for _, seed := range []string{
+		"let x = [1, 2.5e3, \"a\\n\"]; // comment",
+		"#!/usr/bin/env spike\nfn*(a) { yield a && !b || c <= 1 }",
+		"\"unterminated",
+		"x @ y",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		lexer := New(strings.NewReader(input), WithComments())
+		// Every token but the end of input consumes at least one byte.
+		for i := 0; i <= len(input); i++ {
+			token, err := lexer.NextToken()
+			if err != nil || token.Type == Eof {
+				break
+			}
+			if i == len(input) {
+				t.Fatalf("more tokens than bytes in %q", input)
+			}
+		}
+
+		offset := 0
+		for _, token := range TokenizeAll(input) {
+			if token.Start.Offset < offset || token.End.Offset < token.Start.Offset || token.End.Offset > len(input) {
+				t.Fatalf("token %q spans %d to %d after offset %d in %q", token.Literal, token.Start.Offset, token.End.Offset, offset, input)
+			}
+			offset = token.End.Offset
+		}
+	})
+}
@@ -2,20 +2,51 @@ package lexer
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 type TokenIterator interface {
 	NextToken() (Token, error)
 }
 
+// maxLineLength bounds, in bytes, how much of the current line the lexer keeps
+// for error snippets, so inputs without line breaks are not copied whole.
+const maxLineLength = 1024
+
+// Lexer reads its input incrementally through a buffered reader. Besides the
+// token being read it only keeps the end of the current line, without its
+// first droppedCharacters characters.
 type Lexer struct {
-	reader *bufio.Reader
+	reader            *bufio.Reader
+	position          Position
+	line              []byte
+	droppedCharacters int
+	comments          bool
+}
+
+type Option func(lexer *Lexer)
+
+// WithComments makes the lexer emit a Comment token for every `//` comment
+// instead of skipping it. The literal is the comment text including `//`.
+func WithComments() Option {
+	return func(lexer *Lexer) {
+		lexer.comments = true
+	}
 }
 
-func New(reader io.Reader) *Lexer {
-	return &Lexer{reader: bufio.NewReader(reader)}
+func New(reader io.Reader, options ...Option) *Lexer {
+	lexer := &Lexer{
+		reader:   bufio.NewReader(reader),
+		position: Position{Line: 1, Column: 1},
+	}
+	for _, option := range options {
+		option(lexer)
+	}
+
+	return lexer
 }
 
 func (lexer *Lexer) NextToken() (Token, error) {
@@ -24,60 +55,111 @@ func (lexer *Lexer) NextToken() (Token, error) {
 		return lexer.handleIOError(err)
 	}
 
-	return lexer.readNextToken()
+	start := lexer.position
+	token, err := lexer.readNextToken()
+	token.Position = start
+
+	return token, err
 }
 
 func (lexer *Lexer) readNextToken() (Token, error) {
-	operator, err := lexer.tryReadTwoCharOperator()
-	if err != nil {
-		return lexer.handleIOError(err)
-	}
-	if operator != nil {
-		return *operator, nil
+	readers := []func() (Token, bool, error){
+		lexer.tryReadComment,
+		lexer.tryReadTwoCharOperator,
+		lexer.tryReadOneCharOperator,
+		lexer.tryReadIdentifier,
+		lexer.tryReadNumber,
+		lexer.tryReadString,
+	}
+
+	for _, read := range readers {
+		token, ok, err := read()
+		if ok {
+			return token, err
+		}
+		if err != nil {
+			return lexer.handleIOError(err)
+		}
 	}
 
-	operator, err = lexer.tryReadOneCharOperator()
-	if err != nil {
-		return lexer.handleIOError(err)
-	}
-	if operator != nil {
-		return *operator, nil
-	}
+	return lexer.readIllegalCharacter()
+}
 
-	identifier, err := lexer.tryReadIdentifier()
-	if err != nil {
-		return lexer.handleIOError(err)
-	}
-	if identifier != nil {
-		return *identifier, nil
-	}
+// readIllegalCharacter consumes a character no token starts with and the rest
+// of its line, so the error can quote the whole line.
+func (lexer *Lexer) readIllegalCharacter() (Token, error) {
+	start := lexer.position
+	character := []byte{}
+	for {
+		b, err := lexer.readByte()
+		if err != nil {
+			return Token{}, err
+		}
+		character = append(character, b)
 
-	integer, err := lexer.tryReadNumber()
-	if err != nil {
-		return lexer.handleIOError(err)
-	}
-	if integer != nil {
-		return *integer, nil
+		next, err := lexer.reader.Peek(1)
+		if err != nil || utf8.RuneStart(next[0]) {
+			break
+		}
 	}
 
-	str, err := lexer.tryReadString()
-	if err != nil {
-		return lexer.handleIOError(err)
-	}
-	if str != nil {
-		return *str, nil
+	line := string(lexer.line)
+	droppedCharacters := lexer.droppedCharacters
+	rest := strings.Builder{}
+	for rest.Len() < maxLineLength {
+		next, err := lexer.reader.Peek(1)
+		if err != nil || next[0] == '\n' {
+			break
+		}
+
+		b, err := lexer.readByte()
+		if err != nil {
+			return Token{}, err
+		}
+		rest.WriteByte(b)
 	}
 
-	invalidToken, err := lexer.reader.ReadByte()
-	return Token{Invalid, string(invalidToken)}, err
+	literal := string(character)
+
+	return Token{Type: Invalid, Literal: literal}, &Error{
+		Message:           fmt.Sprintf("illegal character %q", literal),
+		Position:          start,
+		Line:              strings.TrimRight(line+rest.String(), "\r"),
+		droppedCharacters: droppedCharacters,
+	}
 }
 
 func (lexer *Lexer) skipWhitespace() error {
+	for {
+		err := lexer.skipBlanks()
+		if err != nil {
+			return err
+		}
+
+		chars, err := lexer.reader.Peek(2)
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		// A `#!` line starting the input lets scripts run as executables.
+		shebang := string(chars) == "#!" && lexer.position.Offset == 0
+		if !shebang && (string(chars) != "//" || lexer.comments) {
+			return nil
+		}
+
+		err = lexer.skipLine()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (lexer *Lexer) skipBlanks() error {
 	var err error
 	c := make([]byte, 0, 1)
 
 	for c, err = lexer.reader.Peek(1); err == nil && isWhitespace(c[0]); c, err = lexer.reader.Peek(1) {
-		_, err2 := lexer.reader.ReadByte()
+		_, err2 := lexer.readByte()
 		if err2 != nil {
 			return err2
 		}
@@ -85,103 +167,186 @@ func (lexer *Lexer) skipWhitespace() error {
 	return err
 }
 
-func (lexer *Lexer) tryReadTwoCharOperator() (*Token, error) {
+func (lexer *Lexer) skipLine() error {
+	for {
+		b, err := lexer.readByte()
+		if err != nil || b == '\n' {
+			return err
+		}
+	}
+}
+
+func (lexer *Lexer) tryReadComment() (Token, bool, error) {
+	if !lexer.comments {
+		return Token{}, false, nil
+	}
+
+	chars, err := lexer.reader.Peek(2)
+	if err == io.EOF || string(chars) != "//" {
+		return Token{}, false, nil
+	}
+	if err != nil {
+		return Token{}, false, err
+	}
+
+	comment := strings.Builder{}
+	for {
+		next, err := lexer.reader.Peek(1)
+		if err == io.EOF || (err == nil && next[0] == '\n') {
+			break
+		}
+		if err != nil {
+			return Token{}, false, err
+		}
+
+		b, err := lexer.readByte()
+		if err != nil {
+			return Token{}, false, err
+		}
+		comment.WriteByte(b)
+	}
+
+	return Token{Type: Comment, Literal: strings.TrimRight(comment.String(), "\r")}, true, nil
+}
+
+func (lexer *Lexer) tryReadTwoCharOperator() (Token, bool, error) {
 	twoChars, err := lexer.reader.Peek(2)
 	if err == io.EOF {
-		return nil, nil
+		return Token{}, false, nil
 	}
 
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
-	t := lookupTwoCharOperator(string(twoChars))
-	if t == nil {
-		return nil, nil
+	t, ok := lookupTwoCharOperator(twoChars)
+	if !ok {
+		return Token{}, false, nil
 	}
 
-	_, err = lexer.reader.Read(twoChars)
-	return t, err
+	err = lexer.skip(len(twoChars))
+	return t, true, err
 }
 
-func (lexer *Lexer) tryReadOneCharOperator() (*Token, error) {
+func (lexer *Lexer) tryReadOneCharOperator() (Token, bool, error) {
 	char, err := lexer.reader.Peek(1)
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
-	t := lookupOneCharOperator(string(char))
-	if t == nil {
-		return nil, nil
-
+	t, ok := lookupOneCharOperator(char)
+	if !ok {
+		return Token{}, false, nil
 	}
 
-	_, err = lexer.reader.Read(char)
-	return t, err
+	err = lexer.skip(len(char))
+	return t, true, err
 }
 
-func (lexer *Lexer) tryReadIdentifier() (*Token, error) {
+func (lexer *Lexer) tryReadIdentifier() (Token, bool, error) {
 	char, err := lexer.reader.Peek(1)
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
 	if !isIdentifierFirstCharacter(char[0]) {
-		return nil, nil
+		return Token{}, false, nil
 	}
 
 	identifier, err := lexer.readIdentifier()
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
-	keyword := lookupKeyword(identifier)
-	if keyword != nil {
-		return keyword, nil
+	keyword, ok := lookupKeyword(identifier)
+	if ok {
+		return keyword, true, nil
 	}
 
-	return &Token{Identifier, identifier}, nil
+	return Token{Type: Identifier, Literal: identifier}, true, nil
 }
 
-func (lexer *Lexer) tryReadNumber() (*Token, error) {
+func (lexer *Lexer) tryReadNumber() (Token, bool, error) {
 	char, err := lexer.reader.Peek(1)
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
 	if !isNumber(char[0]) {
-		return nil, nil
+		return Token{}, false, nil
 	}
 
 	number, err := lexer.readNumber()
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
+	}
+
+	fraction, err := lexer.readFraction()
+	if err != nil {
+		return Token{}, false, err
+	}
+
+	exponent, err := lexer.readExponent()
+	if err != nil {
+		return Token{}, false, err
 	}
 
-	return &Token{Integer, number}, nil
+	if fraction == "" && exponent == "" {
+		return Token{Type: Integer, Literal: number}, true, nil
+	}
+
+	return Token{Type: Float, Literal: number + fraction + exponent}, true, nil
 }
 
-func (lexer *Lexer) tryReadString() (*Token, error) {
+func (lexer *Lexer) tryReadString() (Token, bool, error) {
 	char, err := lexer.reader.Peek(1)
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
 	if char[0] != '"' {
-		return nil, nil
+		return Token{}, false, nil
 	}
 
-	_, err = lexer.reader.ReadByte()
+	start := lexer.position
+	prefixLength := len(lexer.line)
+	droppedCharacters := lexer.droppedCharacters
+
+	_, err = lexer.readByte()
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
 	str, err := lexer.readString()
+	if err == io.EOF {
+		return Token{Type: Invalid, Literal: `"` + str}, true, lexer.unterminatedString(start, str, prefixLength, droppedCharacters)
+	}
 	if err != nil {
-		return nil, err
+		return Token{}, false, err
 	}
 
-	return &Token{String, str}, nil
+	return Token{Type: String, Literal: str}, true, nil
+}
+
+// unterminatedString reports a string running to the end of input. The line
+// before the string is quoted if it is still kept, which is not the case when
+// the string spans lines or the line was shortened while reading it.
+func (lexer *Lexer) unterminatedString(start Position, str string, prefixLength int, droppedCharacters int) error {
+	prefix := ""
+	if lexer.droppedCharacters == droppedCharacters && !strings.Contains(str, "\n") {
+		prefix = string(lexer.line[:prefixLength])
+	} else {
+		droppedCharacters = start.Column - 1
+	}
+
+	rest := strings.SplitN(str, "\n", 2)[0]
+
+	return &Error{
+		Message:           "unterminated string",
+		Position:          start,
+		Line:              strings.TrimRight(prefix+`"`+truncate(rest, maxLineLength), "\r"),
+		droppedCharacters: droppedCharacters,
+	}
 }
 
 func (lexer *Lexer) readIdentifier() (string, error) {
@@ -191,7 +356,7 @@ func (lexer *Lexer) readIdentifier() (string, error) {
 	identifier := strings.Builder{}
 
 	for c, err = lexer.reader.Peek(1); err == nil && isIdentifierCharacter(c[0]); c, err = lexer.reader.Peek(1) {
-		b, err2 := lexer.reader.ReadByte()
+		b, err2 := lexer.readByte()
 		if err2 != nil {
 			return "", err2
 		}
@@ -213,7 +378,7 @@ func (lexer *Lexer) readNumber() (string, error) {
 	number := strings.Builder{}
 
 	for c, err = lexer.reader.Peek(1); err == nil && isNumber(c[0]); c, err = lexer.reader.Peek(1) {
-		b, err2 := lexer.reader.ReadByte()
+		b, err2 := lexer.readByte()
 		if err2 != nil {
 			return "", err2
 		}
@@ -228,10 +393,63 @@ func (lexer *Lexer) readNumber() (string, error) {
 	return number.String(), nil
 }
 
+// readFraction reads the `.5` part of a float, a dot not followed by a digit
+// is left for the parser.
+func (lexer *Lexer) readFraction() (string, error) {
+	chars, err := lexer.reader.Peek(2)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if len(chars) < 2 || chars[0] != '.' || !isNumber(chars[1]) {
+		return "", nil
+	}
+
+	_, err = lexer.readByte()
+	if err != nil {
+		return "", err
+	}
+
+	digits, err := lexer.readNumber()
+
+	return "." + digits, err
+}
+
+// readExponent reads the `e-3` part of a float, an e not followed by digits is
+// left to be read as an identifier.
+func (lexer *Lexer) readExponent() (string, error) {
+	chars, err := lexer.reader.Peek(3)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if len(chars) < 2 || (chars[0] != 'e' && chars[0] != 'E') {
+		return "", nil
+	}
+
+	prefixLength := 1
+	if chars[1] == '+' || chars[1] == '-' {
+		prefixLength = 2
+	}
+	if len(chars) <= prefixLength || !isNumber(chars[prefixLength]) {
+		return "", nil
+	}
+
+	prefix := string(chars[:prefixLength])
+	err = lexer.skip(prefixLength)
+	if err != nil {
+		return "", err
+	}
+
+	digits, err := lexer.readNumber()
+
+	return prefix + digits, err
+}
+
 func (lexer *Lexer) readString() (string, error) {
 	str := strings.Builder{}
 	for {
-		b, err := lexer.reader.ReadByte()
+		b, err := lexer.readByte()
 		if err != nil {
 			return str.String(), err
 		}
@@ -244,20 +462,87 @@ func (lexer *Lexer) readString() (string, error) {
 	}
 }
 
+// readByte consumes one byte of input, keeping track of the position.
+func (lexer *Lexer) readByte() (byte, error) {
+	b, err := lexer.reader.ReadByte()
+	if err != nil {
+		return b, err
+	}
+
+	// Continuation bytes of a multi-byte character do not advance the column.
+	lexer.position.Offset++
+	switch {
+	case b == '\n':
+		lexer.position.Line++
+		lexer.position.Column = 1
+		lexer.line = lexer.line[:0]
+		lexer.droppedCharacters = 0
+		return b, nil
+	case utf8.RuneStart(b):
+		lexer.position.Column++
+	}
+
+	lexer.line = append(lexer.line, b)
+	if len(lexer.line) > 2*maxLineLength {
+		lexer.dropLineStart()
+	}
+
+	return b, nil
+}
+
+// dropLineStart forgets the start of a long line, keeping its last
+// maxLineLength bytes or slightly fewer to start at a whole character.
+func (lexer *Lexer) dropLineStart() {
+	cut := len(lexer.line) - maxLineLength
+	for cut < len(lexer.line) && !utf8.RuneStart(lexer.line[cut]) {
+		cut++
+	}
+
+	lexer.droppedCharacters += utf8.RuneCount(lexer.line[:cut])
+	lexer.line = append(lexer.line[:0], lexer.line[cut:]...)
+}
+
+func (lexer *Lexer) skip(count int) error {
+	for i := 0; i < count; i++ {
+		_, err := lexer.readByte()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (lexer *Lexer) handleIOError(err error) (Token, error) {
 	if err == io.EOF {
-		return EOFToken, nil
+		eof := EOFToken
+		eof.Position = lexer.position
+		return eof, nil
 	}
 
 	return Token{}, err
 }
 
+// truncate shortens text to at most length bytes without splitting a
+// character.
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+
+	for length > 0 && !utf8.RuneStart(text[length]) {
+		length--
+	}
+
+	return text[:length]
+}
+
 func isIdentifierFirstCharacter(c byte) bool {
-	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
 }
 
 func isIdentifierCharacter(c byte) bool {
-	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z')
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z') || c == '_'
 }
 
 func isNumber(c byte) bool {
@@ -268,29 +553,17 @@ func isWhitespace(c byte) bool {
 	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
 }
 
-func lookupKeyword(literal string) *Token {
+func lookupKeyword(literal string) (Token, bool) {
 	token, ok := keywords[literal]
-	if !ok {
-		return nil
-	}
-
-	return &token
+	return token, ok
 }
 
-func lookupOneCharOperator(literal string) *Token {
-	token, ok := oneCharOperators[literal]
-	if !ok {
-		return nil
-	}
-
-	return &token
+func lookupOneCharOperator(literal []byte) (Token, bool) {
+	token, ok := oneCharOperators[string(literal)]
+	return token, ok
 }
 
-func lookupTwoCharOperator(literal string) *Token {
-	token, ok := twoCharOperators[literal]
-	if !ok {
-		return nil
-	}
-
-	return &token
+func lookupTwoCharOperator(literal []byte) (Token, bool) {
+	token, ok := twoCharOperators[string(literal)]
+	return token, ok
 }
@@ -20,6 +20,14 @@ func Test_Lexer_token(t *testing.T) {
 			input:         "false",
 			expectedToken: FalseToken,
 		},
+		{
+			input:         "_",
+			expectedToken: Token{Type: Identifier, Literal: "_"},
+		},
+		{
+			input:         "last_result2",
+			expectedToken: Token{Type: Identifier, Literal: "last_result2"},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -28,11 +36,12 @@ func Test_Lexer_token(t *testing.T) {
 
 			token, err := l.NextToken()
 			assert.NoError(t, err)
-			assert.Equal(t, testCase.expectedToken, token)
+			assert.Equal(t, testCase.expectedToken.Type, token.Type)
+			assert.Equal(t, testCase.expectedToken.Literal, token.Literal)
 
 			token, err = l.NextToken()
 			assert.NoError(t, err)
-			assert.Equal(t, EOFToken, token)
+			assert.Equal(t, Eof, token.Type)
 		})
 	}
 }
@@ -43,23 +52,24 @@ func Test_Lexer_code_sample(t *testing.T) {
 let variable = (10 + 20) * 5; 
 return variable2 ! VAR3 - true false / < > == !=
 <= >= || && if else { } fn , "hello world" [ ] :
+enum Color.Red
 `)
 	expectedTokens := []Token{
 		LetToken,
-		{Identifier, "variable"},
+		{Type: Identifier, Literal: "variable"},
 		AssignToken,
 		LeftParenthesisToken,
-		{Integer, "10"},
+		{Type: Integer, Literal: "10"},
 		PlusToken,
-		{Integer, "20"},
+		{Type: Integer, Literal: "20"},
 		RightParenthesisToken,
 		AsteriskToken,
-		{Integer, "5"},
+		{Type: Integer, Literal: "5"},
 		SemicolonToken,
 		ReturnToken,
-		{Identifier, "variable2"},
+		{Type: Identifier, Literal: "variable2"},
 		BangToken,
-		{Identifier, "VAR3"},
+		{Type: Identifier, Literal: "VAR3"},
 		MinusToken,
 		TrueToken,
 		FalseToken,
@@ -78,10 +88,14 @@ return variable2 ! VAR3 - true false / < > == !=
 		RightBraceToken,
 		FnToken,
 		CommaToken,
-		{String, "hello world"},
+		{Type: String, Literal: "hello world"},
 		LeftBracketToken,
 		RightBracketToken,
 		ColonToken,
+		EnumToken,
+		{Type: Identifier, Literal: "Color"},
+		DotToken,
+		{Type: Identifier, Literal: "Red"},
 	}
 
 	lexer := New(input)
@@ -94,11 +108,21 @@ return variable2 ! VAR3 - true false / < > == !=
 	assert.Exactly(t, expectedTokens, tokens)
 }
 
-func Test_Lexer_invalidToken(t *testing.T) {
+func Test_Lexer_comments(t *testing.T) {
 	// given
-	input := strings.NewReader("^")
+	input := strings.NewReader(`// leading comment
+let a = 10 / 2; // trailing comment
+//! header-style comment
+a //`)
 	expectedTokens := []Token{
-		{Invalid, "^"},
+		LetToken,
+		{Type: Identifier, Literal: "a"},
+		AssignToken,
+		{Type: Integer, Literal: "10"},
+		SlashToken,
+		{Type: Integer, Literal: "2"},
+		SemicolonToken,
+		{Type: Identifier, Literal: "a"},
 	}
 
 	lexer := New(input)
@@ -111,14 +135,274 @@ func Test_Lexer_invalidToken(t *testing.T) {
 	assert.Exactly(t, expectedTokens, tokens)
 }
 
+func Test_Lexer_shebang(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		options        []Option
+		expectedTokens []Token
+		expectedError  string
+	}{
+		{
+			name:           "skipped",
+			input:          "#!/usr/bin/env spike run\nx",
+			expectedTokens: []Token{{Type: Identifier, Literal: "x", Position: Position{Line: 2, Column: 1, Offset: 25}}},
+		},
+		{
+			name:           "skipped with comment tokens",
+			input:          "#!/usr/bin/env spike run\n// header\n",
+			options:        []Option{WithComments()},
+			expectedTokens: []Token{{Type: Comment, Literal: "// header", Position: Position{Line: 2, Column: 1, Offset: 25}}},
+		},
+		{
+			name:          "only on the first line",
+			input:         "x\n#!/usr/bin/env spike run",
+			expectedError: "illegal character \"#\" at 2:1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			lexer := New(strings.NewReader(testCase.input), testCase.options...)
+			tokens := []Token{}
+			for {
+				token, err := lexer.NextToken()
+				if testCase.expectedError != "" && err != nil {
+					assert.EqualError(t, err, testCase.expectedError)
+					return
+				}
+				assert.NoError(t, err)
+				if token.Type == Eof {
+					break
+				}
+				tokens = append(tokens, token)
+			}
+
+			assert.Empty(t, testCase.expectedError)
+			assert.Equal(t, testCase.expectedTokens, tokens)
+		})
+	}
+}
+
+func Test_Lexer_numbers(t *testing.T) {
+	// given
+	input := strings.NewReader("3.14 1e9 2.5e-3 7E+2 1.foo 2e x1e5")
+	expectedTokens := []Token{
+		{Type: Float, Literal: "3.14"},
+		{Type: Float, Literal: "1e9"},
+		{Type: Float, Literal: "2.5e-3"},
+		{Type: Float, Literal: "7E+2"},
+		{Type: Integer, Literal: "1"},
+		DotToken,
+		{Type: Identifier, Literal: "foo"},
+		{Type: Integer, Literal: "2"},
+		{Type: Identifier, Literal: "e"},
+		{Type: Identifier, Literal: "x1e5"},
+	}
+
+	lexer := New(input)
+
+	// when
+	tokens, err := iteratorToSlice(lexer)
+
+	// then
+	assert.NoError(t, err)
+	assert.Exactly(t, expectedTokens, tokens)
+}
+
+func Test_Lexer_commentTokens(t *testing.T) {
+	// given
+	input := strings.NewReader("// first\r\nlet a = 1; // second\n//")
+	expectedTokens := []Token{
+		{Type: Comment, Literal: "// first"},
+		LetToken,
+		{Type: Identifier, Literal: "a"},
+		AssignToken,
+		{Type: Integer, Literal: "1"},
+		SemicolonToken,
+		{Type: Comment, Literal: "// second"},
+		{Type: Comment, Literal: "//"},
+	}
+
+	lexer := New(input, WithComments())
+
+	// when
+	tokens, err := iteratorToSlice(lexer)
+
+	// then
+	assert.NoError(t, err)
+	assert.Exactly(t, expectedTokens, tokens)
+}
+
+func Test_Lexer_errors(t *testing.T) {
+	testCases := map[string]struct {
+		input         string
+		expectedToken Token
+		expectedError *Error
+	}{
+		"illegal character": {
+			input:         "a ^ b",
+			expectedToken: Token{Type: Invalid, Literal: "^", Position: Position{Line: 1, Column: 3, Offset: 2}},
+			expectedError: &Error{
+				Message:  `illegal character "^"`,
+				Position: Position{Line: 1, Column: 3, Offset: 2},
+				Line:     "a ^ b",
+			},
+		},
+		"multi-byte illegal character": {
+			input:         "let a = 1;\nlet ż = 2;\nb",
+			expectedToken: Token{Type: Invalid, Literal: "ż", Position: Position{Line: 2, Column: 5, Offset: 15}},
+			expectedError: &Error{
+				Message:  `illegal character "ż"`,
+				Position: Position{Line: 2, Column: 5, Offset: 15},
+				Line:     "let ż = 2;",
+			},
+		},
+		"unterminated string": {
+			input:         "let a = \"abc",
+			expectedToken: Token{Type: Invalid, Literal: "\"abc", Position: Position{Line: 1, Column: 9, Offset: 8}},
+			expectedError: &Error{
+				Message:  "unterminated string",
+				Position: Position{Line: 1, Column: 9, Offset: 8},
+				Line:     `let a = "abc`,
+			},
+		},
+		"unterminated multi-line string": {
+			input:         "let a = \"abc\ndef",
+			expectedToken: Token{Type: Invalid, Literal: "\"abc\ndef", Position: Position{Line: 1, Column: 9, Offset: 8}},
+			expectedError: &Error{
+				Message:           "unterminated string",
+				Position:          Position{Line: 1, Column: 9, Offset: 8},
+				Line:              `"abc`,
+				droppedCharacters: 8,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := New(strings.NewReader(testCase.input))
+
+			var token Token
+			var err error
+			for err == nil && token.Type != Eof {
+				token, err = lexer.NextToken()
+			}
+
+			assert.Equal(t, testCase.expectedToken, token)
+			assert.Equal(t, testCase.expectedError, err)
+		})
+	}
+}
+
+func Test_Error_Snippet(t *testing.T) {
+	err := &Error{
+		Message:  `illegal character "^"`,
+		Position: Position{Line: 1, Column: 3, Offset: 2},
+		Line:     "a ^ b",
+	}
+
+	assert.Equal(t, `illegal character "^" at 1:3`, err.Error())
+	assert.Equal(t, "a ^ b\n  ^", err.Snippet())
+}
+
+func Test_Lexer_positions(t *testing.T) {
+	input := strings.NewReader("let a = 1;\n// comment\n  \"żółw\" + b")
+	expectedPositions := []Position{
+		{Line: 1, Column: 1, Offset: 0},
+		{Line: 1, Column: 5, Offset: 4},
+		{Line: 1, Column: 7, Offset: 6},
+		{Line: 1, Column: 9, Offset: 8},
+		{Line: 1, Column: 10, Offset: 9},
+		{Line: 3, Column: 3, Offset: 24},
+		{Line: 3, Column: 10, Offset: 34},
+		{Line: 3, Column: 12, Offset: 36},
+		{Line: 3, Column: 13, Offset: 37},
+	}
+
+	lexer := New(input)
+	positions := make([]Position, 0)
+	for {
+		token, err := lexer.NextToken()
+		assert.NoError(t, err)
+
+		positions = append(positions, token.Position)
+		if token.Type == Eof {
+			break
+		}
+	}
+
+	assert.Equal(t, expectedPositions, positions)
+}
+
+func Test_Lexer_largeInput(t *testing.T) {
+	lines := 100000
+	lexer := New(strings.NewReader(strings.Repeat(largeInputLine, lines) + "end"))
+
+	var token Token
+	var err error
+	count := 0
+	for token, err = lexer.NextToken(); err == nil && token.Type != Eof; token, err = lexer.NextToken() {
+		count++
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 15*lines+1, count)
+	assert.Equal(t, Position{Line: lines + 1, Column: 4, Offset: len(largeInputLine)*lines + 3}, token.Position)
+}
+
+func Test_Lexer_errorOnLongLine(t *testing.T) {
+	input := strings.Repeat("a + ", 100000) + "^ + " + strings.Repeat("b", 5000)
+	lexer := New(strings.NewReader(input))
+
+	var err error
+	for token := (Token{}); err == nil && token.Type != Eof; {
+		token, err = lexer.NextToken()
+	}
+
+	lexerError, ok := err.(*Error)
+	assert.True(t, ok)
+	assert.Equal(t, Position{Line: 1, Column: 400001, Offset: 400000}, lexerError.Position)
+	assert.True(t, len(lexerError.Line) <= 3*maxLineLength)
+
+	snippet := strings.Split(lexerError.Snippet(), "\n")
+	caret := strings.Index(snippet[1], "^")
+	assert.Equal(t, "^ + b", snippet[0][caret:caret+5])
+}
+
+const largeInputLine = "let value = fn(x) { x * 2 + \"żółw\" }; // comment\n"
+
+func Benchmark_Lexer_manyLines(b *testing.B) {
+	benchmarkLexer(b, strings.Repeat(largeInputLine, 100000))
+}
+
+func Benchmark_Lexer_singleLine(b *testing.B) {
+	benchmarkLexer(b, strings.Repeat(strings.TrimSuffix(largeInputLine, "// comment\n"), 100000))
+}
+
+func benchmarkLexer(b *testing.B, input string) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		lexer := New(strings.NewReader(input))
+		for token, err := lexer.NextToken(); token.Type != Eof; token, err = lexer.NextToken() {
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
 func iteratorToSlice(iterator TokenIterator) ([]Token, error) {
 	result := make([]Token, 0)
 
-	for token, err := iterator.NextToken(); token != EOFToken; token, err = iterator.NextToken() {
+	for token, err := iterator.NextToken(); token.Type != Eof; token, err = iterator.NextToken() {
 		if err != nil {
 			return nil, err
 		}
 
+		token.Position = Position{}
 		result = append(result, token)
 	}
 
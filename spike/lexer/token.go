@@ -1,8 +1,47 @@
 package lexer
 
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
 type Token struct {
 	Type    TokenType
 	Literal string
+	Position
+}
+
+// Position locates a token in the source. Line and Column start at 1, Column
+// counts characters and Offset counts bytes from the start of the input.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+func (position Position) String() string {
+	return fmt.Sprintf("%d:%d", position.Line, position.Column)
+}
+
+// End returns the position just past the token's source text.
+func (token Token) End() Position {
+	text := token.Literal
+	if token.Type == String {
+		text = `"` + text + `"`
+	}
+
+	end := token.Position
+	for _, character := range text {
+		end.Offset += utf8.RuneLen(character)
+		if character == '\n' {
+			end.Line++
+			end.Column = 1
+		} else {
+			end.Column++
+		}
+	}
+
+	return end
 }
 
 type TokenType string
@@ -31,6 +70,7 @@ const (
 	LeftBracket      TokenType = "leftBracket"
 	RightBracket     TokenType = "rightBracket"
 	Colon            TokenType = "colon"
+	Dot              TokenType = "dot"
 )
 
 var oneCharOperators = map[string]Token{
@@ -51,6 +91,7 @@ var oneCharOperators = map[string]Token{
 	"[": LeftBracketToken,
 	"]": RightBracketToken,
 	":": ColonToken,
+	".": DotToken,
 }
 
 var twoCharOperators = map[string]Token{
@@ -71,6 +112,9 @@ const (
 	If     TokenType = "if"
 	Else   TokenType = "else"
 	Fn     TokenType = "fn"
+	Enum   TokenType = "enum"
+	Yield  TokenType = "yield"
+	Import TokenType = "import"
 )
 
 var keywords = map[string]Token{
@@ -81,6 +125,9 @@ var keywords = map[string]Token{
 	"if":     IfToken,
 	"else":   ElseToken,
 	"fn":     FnToken,
+	"enum":   EnumToken,
+	"yield":  YieldToken,
+	"import": ImportToken,
 }
 
 // Other
@@ -88,8 +135,10 @@ const (
 	Semicolon  TokenType = "semicolon"
 	Eof        TokenType = "eof"
 	Invalid    TokenType = "invalid"
+	Comment    TokenType = "comment"
 	Identifier TokenType = "identifier"
 	Integer    TokenType = "integer"
+	Float      TokenType = "float"
 	String     TokenType = "string"
 )
 
@@ -126,4 +175,8 @@ var (
 	LeftBracketToken      = Token{Type: LeftBracket, Literal: "["}
 	RightBracketToken     = Token{Type: RightBracket, Literal: "]"}
 	ColonToken            = Token{Type: Colon, Literal: ":"}
+	DotToken              = Token{Type: Dot, Literal: "."}
+	EnumToken             = Token{Type: Enum, Literal: "enum"}
+	YieldToken            = Token{Type: Yield, Literal: "yield"}
+	ImportToken           = Token{Type: Import, Literal: "import"}
 )
@@ -0,0 +1,133 @@
+package lexer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind groups token types the way editors highlight them.
+type Kind string
+
+const (
+	KeywordKind     Kind = "keyword"
+	IdentifierKind  Kind = "identifier"
+	NumberKind      Kind = "number"
+	StringKind      Kind = "string"
+	CommentKind     Kind = "comment"
+	OperatorKind    Kind = "operator"
+	PunctuationKind Kind = "punctuation"
+	ErrorKind       Kind = "error"
+)
+
+var punctuation = map[TokenType]bool{
+	LeftParenthesis:  true,
+	RightParenthesis: true,
+	LeftBrace:        true,
+	RightBrace:       true,
+	LeftBracket:      true,
+	RightBracket:     true,
+	Comma:            true,
+	Colon:            true,
+	Dot:              true,
+	Semicolon:        true,
+}
+
+// SourceToken is a token read by TokenizeAll, spanning the source from Start
+// up to End. Literal is the token as the lexer reads it, strings without
+// their quotes.
+type SourceToken struct {
+	Type    TokenType
+	Literal string
+	Kind    Kind
+	Start   Position
+	End     Position
+	// Err is what the lexer reported for Invalid tokens.
+	Err *Error
+}
+
+// TokenizeAll reads every token of src, comments included, for editors to
+// highlight. It never fails: a character no token starts with becomes an
+// Invalid token and lexing goes on after it, an unterminated string becomes
+// an Invalid token running to the end of src. A `#!` first line is returned
+// as a comment. The end of input is not a token.
+func TokenizeAll(src string) []SourceToken {
+	tokens := []SourceToken{}
+
+	lexer := New(strings.NewReader(src), WithComments())
+	if strings.HasPrefix(src, "#!") {
+		line := strings.TrimRight(strings.SplitN(src, "\n", 2)[0], "\r")
+		start := lexer.position
+		for i := 0; i < len(line); i++ {
+			_, _ = lexer.readByte()
+		}
+		tokens = append(tokens, SourceToken{Type: Comment, Literal: line, Kind: CommentKind, Start: start, End: lexer.position})
+	}
+
+	for {
+		token, err := lexer.NextToken()
+		if token.Type == Eof {
+			return tokens
+		}
+
+		sourceToken := SourceToken{Type: token.Type, Literal: token.Literal, Kind: kindOf(token.Type), Start: token.Position, End: lexer.position}
+		lexerError, ok := err.(*Error)
+		if err != nil && !ok {
+			// Reading from a string only fails with lexing errors.
+			return tokens
+		}
+		if !ok {
+			tokens = append(tokens, sourceToken)
+			continue
+		}
+
+		sourceToken.Err = lexerError
+		if strings.HasPrefix(token.Literal, `"`) {
+			// An unterminated string ran to the end of src.
+			tokens = append(tokens, sourceToken)
+			return tokens
+		}
+
+		// The lexer skipped the rest of the line after the illegal character
+		// to quote it, lexing starts over right after the character.
+		sourceToken.End = advance(token.Position, token.Literal)
+		tokens = append(tokens, sourceToken)
+
+		next := New(strings.NewReader(src[sourceToken.End.Offset:]), WithComments())
+		next.position = sourceToken.End
+		lexer = next
+	}
+}
+
+// advance returns the position after text starting at position, text being
+// on one line.
+func advance(position Position, text string) Position {
+	position.Offset += len(text)
+	position.Column += utf8.RuneCountInString(text)
+
+	return position
+}
+
+func kindOf(tokenType TokenType) Kind {
+	switch tokenType {
+	case Identifier:
+		return IdentifierKind
+	case Integer, Float:
+		return NumberKind
+	case String:
+		return StringKind
+	case Comment:
+		return CommentKind
+	case Invalid:
+		return ErrorKind
+	}
+
+	// Keyword token types are their literals.
+	if _, ok := keywords[string(tokenType)]; ok {
+		return KeywordKind
+	}
+	if punctuation[tokenType] {
+		return PunctuationKind
+	}
+
+	return OperatorKind
+}
@@ -0,0 +1,91 @@
+package lexer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// describe prints tokens as kind, literal and span, to compare them briefly.
+func describe(tokens []SourceToken) []string {
+	described := make([]string, len(tokens))
+	for i, token := range tokens {
+		described[i] = fmt.Sprintf("%s %q %s-%s", token.Kind, token.Literal, token.Start, token.End)
+	}
+
+	return described
+}
+
+func Test_TokenizeAll(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:  "statement",
+			input: "let x = [1.5, \"a\\nb\"]; // done",
+			expected: []string{
+				`keyword "let" 1:1-1:4`,
+				`identifier "x" 1:5-1:6`,
+				`operator "=" 1:7-1:8`,
+				`punctuation "[" 1:9-1:10`,
+				`number "1.5" 1:10-1:13`,
+				`punctuation "," 1:13-1:14`,
+				`string "a\\nb" 1:15-1:21`,
+				`punctuation "]" 1:21-1:22`,
+				`punctuation ";" 1:22-1:23`,
+				`comment "// done" 1:24-1:31`,
+			},
+		},
+		{
+			name:  "shebang",
+			input: "#!/usr/bin/env spike\nx",
+			expected: []string{
+				`comment "#!/usr/bin/env spike" 1:1-1:21`,
+				`identifier "x" 2:1-2:2`,
+			},
+		},
+		{
+			name:  "illegal character",
+			input: "a @ b\nc",
+			expected: []string{
+				`identifier "a" 1:1-1:2`,
+				`error "@" 1:3-1:4`,
+				`identifier "b" 1:5-1:6`,
+				`identifier "c" 2:1-2:2`,
+			},
+		},
+		{
+			name:  "unterminated string",
+			input: "x \"abc\ndef",
+			expected: []string{
+				`identifier "x" 1:1-1:2`,
+				`error "\"abc\ndef" 1:3-2:4`,
+			},
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: []string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, describe(TokenizeAll(testCase.input)))
+		})
+	}
+}
+
+func Test_TokenizeAll_errors(t *testing.T) {
+	tokens := TokenizeAll("a 🙂 b")
+
+	require.Len(t, tokens, 3)
+	assert.Equal(t, Invalid, tokens[1].Type)
+	assert.EqualError(t, tokens[1].Err, "illegal character \"🙂\" at 1:3")
+	assert.Equal(t, Position{Line: 1, Column: 5, Offset: 7}, tokens[2].Start)
+	assert.Nil(t, tokens[2].Err)
+}
@@ -0,0 +1,230 @@
+package lsp
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+)
+
+type definitionKind int
+
+const (
+	variableDefinition definitionKind = iota
+	parameterDefinition
+	functionDefinition
+	enumDefinition
+	moduleDefinition
+)
+
+// definition is a name bound by let, a parameter, an enum or an import.
+type definition struct {
+	Name  string
+	Kind  definitionKind
+	Start lexer.Position
+	End   lexer.Position
+	// Node is the function or enum statement defined, for hovers.
+	Node  ast.Node
+	scope *scope
+}
+
+// reference is an identifier in the source. Builtins are referenced with a
+// nil Definition, unresolved identifiers are not referenced at all.
+type reference struct {
+	Identifier *ast.Identifier
+	Definition *definition
+	Builtin    *object.BuiltinFunction
+}
+
+// scope holds the names defined in a function, or the whole program. Like in
+// the compiler, blocks do not start scopes of their own.
+type scope struct {
+	parent  *scope
+	names   map[string]*definition
+	start   lexer.Position
+	end     lexer.Position
+	program bool
+}
+
+func (scope *scope) resolve(name string) (*definition, bool) {
+	for current := scope; current != nil; current = current.parent {
+		if definition, ok := current.names[name]; ok {
+			return definition, true
+		}
+	}
+
+	return nil, false
+}
+
+// contains tells whether position is inside the scope, the program scope
+// holds everything.
+func (scope *scope) contains(position lexer.Position) bool {
+	return scope.program || (!before(position, scope.start) && !before(scope.end, position))
+}
+
+// index records which definition each identifier of a program refers to,
+// resolving names the way the compiler does.
+type index struct {
+	definitions []*definition
+	references  []*reference
+}
+
+func newIndex(program *ast.Program) *index {
+	index := &index{}
+	ast.Walk(&resolver{index: index, scope: &scope{names: map[string]*definition{}, program: true}}, program)
+
+	return index
+}
+
+// referenceAt returns the reference to the identifier at position.
+func (index *index) referenceAt(position lexer.Position) (*reference, bool) {
+	for _, reference := range index.references {
+		identifier := reference.Identifier
+		if !before(position, identifier.Pos()) && !before(identifier.End(), position) {
+			return reference, true
+		}
+	}
+
+	return nil, false
+}
+
+// visible returns the definitions made before position in the scopes holding
+// it, inner ones shadowing outer ones.
+func (index *index) visible(position lexer.Position) []*definition {
+	seen := map[string]bool{}
+	var definitions []*definition
+	for i := len(index.definitions) - 1; i >= 0; i-- {
+		definition := index.definitions[i]
+		if seen[definition.Name] || !before(definition.Start, position) || !definition.scope.contains(position) {
+			continue
+		}
+
+		seen[definition.Name] = true
+		definitions = append(definitions, definition)
+	}
+
+	return definitions
+}
+
+type resolver struct {
+	index *index
+	scope *scope
+}
+
+func (resolver *resolver) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.LetStatement:
+		kind := variableDefinition
+		if _, ok := node.Value.(*ast.FunctionExpression); ok {
+			kind = functionDefinition
+		}
+		resolver.define(node.Name, kind, node.Value)
+		ast.Walk(resolver, node.Value)
+		return nil
+
+	case *ast.DestructuringLetStatement:
+		ast.Walk(resolver, node.Value)
+		for _, name := range node.Names {
+			resolver.define(name, variableDefinition, nil)
+		}
+		return nil
+
+	case *ast.EnumStatement:
+		resolver.define(node.Name, enumDefinition, node)
+		return nil
+
+	case *ast.ImportStatement:
+		definition := &definition{
+			Name:  node.Module.Value,
+			Kind:  moduleDefinition,
+			Start: node.Module.Pos(),
+			End:   node.Module.End(),
+		}
+		resolver.add(definition)
+		return nil
+
+	case *ast.FunctionExpression:
+		inner := *resolver
+		inner.scope = &scope{parent: resolver.scope, names: map[string]*definition{}, start: node.Pos(), end: node.End()}
+		for _, parameter := range node.Parameters {
+			inner.define(parameter, parameterDefinition, nil)
+		}
+		ast.Walk(&inner, node.Body)
+		return nil
+
+	case *ast.MemberExpression:
+		// Members are looked up on the object, not in scope.
+		ast.Walk(resolver, node.Object)
+		return nil
+
+	case *ast.Identifier:
+		resolver.refer(node)
+	}
+
+	return resolver
+}
+
+func (resolver *resolver) define(name *ast.Identifier, kind definitionKind, node ast.Node) {
+	definition := &definition{Name: name.Value, Kind: kind, Start: name.Pos(), End: name.End(), Node: node}
+	resolver.add(definition)
+	resolver.index.references = append(resolver.index.references, &reference{Identifier: name, Definition: definition})
+}
+
+func (resolver *resolver) add(definition *definition) {
+	definition.scope = resolver.scope
+	resolver.scope.names[definition.Name] = definition
+	resolver.index.definitions = append(resolver.index.definitions, definition)
+}
+
+func (resolver *resolver) refer(identifier *ast.Identifier) {
+	if definition, ok := resolver.scope.resolve(identifier.Value); ok {
+		resolver.index.references = append(resolver.index.references, &reference{Identifier: identifier, Definition: definition})
+		return
+	}
+
+	if builtin := object.GetBuiltinByName(identifier.Value); builtin != nil {
+		resolver.index.references = append(resolver.index.references, &reference{Identifier: identifier, Builtin: builtin})
+	}
+}
+
+// describe returns the hover text for a reference, in markdown.
+func describe(reference *reference) string {
+	if reference.Builtin != nil {
+		return fmt.Sprintf("```spike\n(builtin) %s\n```", reference.Builtin.Name)
+	}
+
+	definition := reference.Definition
+	var signature string
+	switch definition.Kind {
+	case functionDefinition:
+		function := definition.Node.(*ast.FunctionExpression)
+		parameters := make([]string, len(function.Parameters))
+		for i, parameter := range function.Parameters {
+			parameters[i] = parameter.Value
+		}
+		keyword := "fn"
+		if function.Generator {
+			keyword = "fn*"
+		}
+		signature = fmt.Sprintf("%s %s(%s)", keyword, definition.Name, strings.Join(parameters, ", "))
+		if doc := function.Doc(); doc != "" {
+			return fmt.Sprintf("```spike\n%s\n```\n\n%s", signature, doc)
+		}
+	case enumDefinition:
+		signature = definition.Node.String()
+	case parameterDefinition:
+		signature = "(parameter) " + definition.Name
+	case moduleDefinition:
+		signature = "(module) " + definition.Name
+	default:
+		signature = "(variable) " + definition.Name
+	}
+
+	return fmt.Sprintf("```spike\n%s\n```", signature)
+}
+
+// before tells whether a comes before b in the source.
+func before(a lexer.Position, b lexer.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
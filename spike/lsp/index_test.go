@@ -0,0 +1,78 @@
+package lsp
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_referenceAt(t *testing.T) {
+	tests := map[string]struct {
+		source     string
+		column     int
+		definition int
+		builtin    bool
+	}{
+		"global": {
+			source:     "let x = 1; x",
+			column:     12,
+			definition: 5,
+		},
+		"parameter shadows global": {
+			source:     "let x = 1; fn(x) { x }",
+			column:     20,
+			definition: 15,
+		},
+		"destructured names are defined after the value": {
+			source:     "let x = 1; let x, y = f(x);",
+			column:     25,
+			definition: 5,
+		},
+		"recursive function": {
+			source:     "let f = fn(n) { f(n) };",
+			column:     17,
+			definition: 5,
+		},
+		"import": {
+			source:     `import "db"; db.get`,
+			column:     14,
+			definition: 8,
+		},
+		"builtin": {
+			source:  "len([])",
+			column:  2,
+			builtin: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(test.source))).ParseProgram()
+			require.NoError(t, err)
+
+			reference, ok := newIndex(program).referenceAt(lexer.Position{Line: 1, Column: test.column})
+
+			require.True(t, ok)
+			if test.builtin {
+				assert.Nil(t, reference.Definition)
+				assert.NotNil(t, reference.Builtin)
+				return
+			}
+			require.NotNil(t, reference.Definition)
+			assert.Equal(t, test.definition, reference.Definition.Start.Column)
+		})
+	}
+}
+
+func TestIndex_referenceAt_skipsMembers(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader("enum Color { Red }; let Red = 1; Color.Red"))).ParseProgram()
+	require.NoError(t, err)
+
+	_, ok := newIndex(program).referenceAt(lexer.Position{Line: 1, Column: 40})
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,82 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const contentLengthHeader = "Content-Length"
+
+// maxContentLength bounds the messages the server reads, far above any
+// document a client would open.
+const maxContentLength = 16 << 20
+
+// errMessageTooLarge is returned for messages longer than maxContentLength,
+// which are skipped without reading them into memory.
+var errMessageTooLarge = errors.Errorf("message is longer than %d bytes", maxContentLength)
+
+// readMessage reads the content of one message, which is preceded by headers
+// giving its length.
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF && line == "" && length < 0 {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read header")
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), contentLengthHeader) {
+			length, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil || length < 0 {
+				return nil, errors.Errorf("invalid %s: %s", contentLengthHeader, parts[1])
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, errors.Errorf("missing %s header", contentLengthHeader)
+	}
+
+	if length > maxContentLength {
+		_, err := io.CopyN(ioutil.Discard, reader, int64(length))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read message")
+		}
+
+		return nil, errMessageTooLarge
+	}
+
+	content := make([]byte, length)
+	_, err := io.ReadFull(reader, content)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read message")
+	}
+
+	return content, nil
+}
+
+func writeMessage(writer io.Writer, message interface{}) error {
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "%s: %d\r\n\r\n%s", contentLengthHeader, len(content), content)
+	return err
+}
@@ -0,0 +1,73 @@
+package lsp
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is an open text document, split in lines to convert between
+// lexer positions, which count lines from 1 and characters in runes, and
+// protocol positions, which count from 0 and characters in UTF-16 units.
+type document struct {
+	text  string
+	lines []string
+}
+
+func newDocument(text string) *document {
+	return &document{text: text, lines: strings.Split(text, "\n")}
+}
+
+func (document *document) line(number int) string {
+	if number < 0 || number >= len(document.lines) {
+		return ""
+	}
+
+	return strings.TrimSuffix(document.lines[number], "\r")
+}
+
+func (document *document) toLexer(position Position) lexer.Position {
+	column := 1
+	units := 0
+	for _, character := range document.line(position.Line) {
+		if units >= position.Character {
+			break
+		}
+		units += len(utf16.Encode([]rune{character}))
+		column++
+	}
+
+	return lexer.Position{Line: position.Line + 1, Column: column}
+}
+
+func (document *document) toProtocol(position lexer.Position) Position {
+	if position.Line < 1 {
+		return Position{}
+	}
+
+	units := 0
+	column := 1
+	for _, character := range document.line(position.Line - 1) {
+		if column >= position.Column {
+			break
+		}
+		units += len(utf16.Encode([]rune{character}))
+		column++
+	}
+
+	return Position{Line: position.Line - 1, Character: units}
+}
+
+// lineEnd returns the position at the end of a line, counted from 0.
+func (document *document) lineEnd(line int) Position {
+	return document.toProtocol(lexer.Position{Line: line + 1, Column: utf8.RuneCountInString(document.line(line)) + 1})
+}
+
+func (document *document) toRange(start lexer.Position, end lexer.Position) Range {
+	if end.Line < 1 {
+		end = start
+	}
+
+	return Range{Start: document.toProtocol(start), End: document.toProtocol(end)}
+}
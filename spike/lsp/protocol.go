@@ -0,0 +1,159 @@
+package lsp
+
+import "encoding/json"
+
+// The parts of the Language Server Protocol the server speaks, see
+// https://microsoft.github.io/language-server-protocol/specification.
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+const (
+	parseErrorCode     = -32700
+	invalidRequestCode = -32600
+	invalidParamsCode  = -32602
+	methodNotFoundCode = -32601
+)
+
+// Position is zero based, Character counts UTF-16 code units.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+const (
+	errorSeverity       = 1
+	warningSeverity     = 2
+	informationSeverity = 3
+)
+
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type didOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type Hover struct {
+	Contents markupContent `json:"contents"`
+	Range    Range         `json:"range"`
+}
+
+// Completion item kinds.
+const (
+	functionKind = 3
+	variableKind = 6
+	moduleKind   = 9
+	enumKind     = 13
+	keywordKind  = 14
+)
+
+type CompletionItem struct {
+	Label  string `json:"label"`
+	Kind   int    `json:"kind"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type semanticTokensParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+// SemanticTokens holds five numbers per token: its line and start character,
+// both relative to the previous token, its length, its type as an index into
+// semanticTokenTypes and its modifiers.
+type SemanticTokens struct {
+	Data []int `json:"data"`
+}
+
+// fullSync makes clients send the whole document on every change.
+const fullSync = 1
+
+type initializeResult struct {
+	Capabilities struct {
+		TextDocumentSync   int         `json:"textDocumentSync"`
+		HoverProvider      bool        `json:"hoverProvider"`
+		DefinitionProvider bool        `json:"definitionProvider"`
+		CompletionProvider interface{} `json:"completionProvider"`
+		// SemanticTokensProvider lists semanticTokenTypes as its legend.
+		SemanticTokensProvider struct {
+			Legend struct {
+				TokenTypes     []string `json:"tokenTypes"`
+				TokenModifiers []string `json:"tokenModifiers"`
+			} `json:"legend"`
+			Full bool `json:"full"`
+		} `json:"semanticTokensProvider"`
+	} `json:"capabilities"`
+	ServerInfo struct {
+		Name string `json:"name"`
+	} `json:"serverInfo"`
+}
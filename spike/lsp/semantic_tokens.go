@@ -0,0 +1,50 @@
+package lsp
+
+import "spike-interpreter-go/spike/lexer"
+
+// semanticTokenTypes is the legend of the token types semantic tokens refer
+// to by index.
+var semanticTokenTypes = []string{"keyword", "variable", "number", "string", "comment", "operator"}
+
+var semanticTokenTypeIndexes = map[lexer.Kind]int{
+	lexer.KeywordKind:    0,
+	lexer.IdentifierKind: 1,
+	lexer.NumberKind:     2,
+	lexer.StringKind:     3,
+	lexer.CommentKind:    4,
+	lexer.OperatorKind:   5,
+}
+
+// SemanticTokens classifies the tokens of an open document for highlighting.
+// Punctuation and invalid tokens are left out, and tokens spanning lines are
+// only highlighted on their first line.
+func (server *Server) SemanticTokens(uri string) *SemanticTokens {
+	result := &SemanticTokens{Data: []int{}}
+	document, ok := server.documents[uri]
+	if !ok {
+		return result
+	}
+
+	previous := Position{}
+	for _, token := range lexer.TokenizeAll(document.text) {
+		tokenType, ok := semanticTokenTypeIndexes[token.Kind]
+		if !ok {
+			continue
+		}
+
+		start := document.toProtocol(token.Start)
+		end := document.toProtocol(token.End)
+		if end.Line != start.Line {
+			end = document.lineEnd(start.Line)
+		}
+
+		character := start.Character
+		if start.Line == previous.Line {
+			character -= previous.Character
+		}
+		result.Data = append(result.Data, start.Line-previous.Line, character, end.Character-start.Character, tokenType, 0)
+		previous = start
+	}
+
+	return result
+}
@@ -0,0 +1,319 @@
+// Package lsp is a language server for spike, speaking the Language Server
+// Protocol over a stream. It reports parse and compile errors as
+// diagnostics, describes names on hover, finds their definitions and
+// completes builtins, keywords and names in scope.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const serverName = "spike-lsp"
+
+var keywords = []lexer.TokenType{
+	lexer.Let, lexer.Return, lexer.True, lexer.False, lexer.If, lexer.Else,
+	lexer.Fn, lexer.Enum, lexer.Yield, lexer.Import,
+}
+
+// Server holds the documents a client opened.
+type Server struct {
+	out       io.Writer
+	documents map[string]*document
+}
+
+// NewServer returns a server writing its responses and notifications to
+// out.
+func NewServer(out io.Writer) *Server {
+	return &Server{out: out, documents: map[string]*document{}}
+}
+
+// Serve answers the messages read from in until the client asks the server
+// to exit or closes in.
+func Serve(in io.Reader, out io.Writer) error {
+	server := NewServer(out)
+	reader := bufio.NewReader(in)
+	for {
+		content, err := readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err == errMessageTooLarge {
+			err = server.fail(nil, invalidRequestCode, err.Error())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		exit, err := server.Handle(content)
+		if err != nil || exit {
+			return err
+		}
+	}
+}
+
+// Handle answers one message, reporting true when it asks the server to
+// exit. Only failing to write the answer is an error, invalid requests are
+// answered with an error response.
+func (server *Server) Handle(content []byte) (bool, error) {
+	message := &request{}
+	err := json.Unmarshal(content, message)
+	if err != nil {
+		return false, server.fail(nil, parseErrorCode, "invalid message: "+err.Error())
+	}
+
+	if message.Method == "exit" {
+		return true, nil
+	}
+
+	if message.ID == nil {
+		return false, server.notify(message)
+	}
+
+	result, err := server.call(message)
+	if err == errUnknownMethod {
+		return false, server.fail(message.ID, methodNotFoundCode, "unknown method "+message.Method)
+	}
+	if err != nil {
+		return false, server.fail(message.ID, invalidParamsCode, err.Error())
+	}
+
+	return false, writeMessage(server.out, &response{JSONRPC: "2.0", ID: message.ID, Result: result})
+}
+
+var errUnknownMethod = errors.New("unknown method")
+
+// call answers a request.
+func (server *Server) call(message *request) (interface{}, error) {
+	switch message.Method {
+	case "initialize":
+		result := &initializeResult{}
+		result.Capabilities.TextDocumentSync = fullSync
+		result.Capabilities.HoverProvider = true
+		result.Capabilities.DefinitionProvider = true
+		result.Capabilities.CompletionProvider = map[string]interface{}{}
+		result.Capabilities.SemanticTokensProvider.Legend.TokenTypes = semanticTokenTypes
+		result.Capabilities.SemanticTokensProvider.Legend.TokenModifiers = []string{}
+		result.Capabilities.SemanticTokensProvider.Full = true
+		result.ServerInfo.Name = serverName
+		return result, nil
+
+	case "shutdown":
+		return nil, nil
+
+	case "textDocument/hover":
+		params := &textDocumentPositionParams{}
+		err := json.Unmarshal(message.Params, params)
+		if err != nil {
+			return nil, err
+		}
+		return server.Hover(params.TextDocument.URI, params.Position), nil
+
+	case "textDocument/definition":
+		params := &textDocumentPositionParams{}
+		err := json.Unmarshal(message.Params, params)
+		if err != nil {
+			return nil, err
+		}
+		return server.Definition(params.TextDocument.URI, params.Position), nil
+
+	case "textDocument/completion":
+		params := &textDocumentPositionParams{}
+		err := json.Unmarshal(message.Params, params)
+		if err != nil {
+			return nil, err
+		}
+		return server.Completion(params.TextDocument.URI, params.Position), nil
+
+	case "textDocument/semanticTokens/full":
+		params := &semanticTokensParams{}
+		err := json.Unmarshal(message.Params, params)
+		if err != nil {
+			return nil, err
+		}
+		return server.SemanticTokens(params.TextDocument.URI), nil
+	}
+
+	return nil, errUnknownMethod
+}
+
+// notify handles a notification. Notifications get no response, so the ones
+// that can not be read are dropped, as are unknown ones.
+func (server *Server) notify(message *request) error {
+	switch message.Method {
+	case "textDocument/didOpen":
+		params := &didOpenParams{}
+		if json.Unmarshal(message.Params, params) == nil {
+			return server.open(params.TextDocument.URI, params.TextDocument.Text)
+		}
+
+	case "textDocument/didChange":
+		params := &didChangeParams{}
+		if json.Unmarshal(message.Params, params) == nil && len(params.ContentChanges) > 0 {
+			return server.open(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		params := &didCloseParams{}
+		if json.Unmarshal(message.Params, params) == nil {
+			delete(server.documents, params.TextDocument.URI)
+			return server.publish(params.TextDocument.URI, []Diagnostic{})
+		}
+	}
+
+	return nil
+}
+
+func (server *Server) open(uri string, text string) error {
+	server.documents[uri] = newDocument(text)
+	return server.publish(uri, server.Diagnostics(uri))
+}
+
+func (server *Server) publish(uri string, diagnostics []Diagnostic) error {
+	return writeMessage(server.out, &notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  &publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+func (server *Server) fail(id *json.RawMessage, code int, message string) error {
+	return writeMessage(server.out, &response{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: message}})
+}
+
+var severities = map[diagnostic.Severity]int{
+	diagnostic.Warning: warningSeverity,
+	diagnostic.Info:    informationSeverity,
+}
+
+// Diagnostics returns the first parse error of an open document, or when it
+// parses, its first compile error. Documents that compile get the hints about
+// their recursive calls.
+func (server *Server) Diagnostics(uri string) []Diagnostic {
+	document, ok := server.documents[uri]
+	if !ok {
+		return []Diagnostic{}
+	}
+
+	program, err := parse(document)
+	if err == nil {
+		err = compiler.New().Compile(program)
+	}
+	if err == nil {
+		result := []Diagnostic{}
+		for _, hint := range compiler.TailCalls(program) {
+			result = append(result, Diagnostic{
+				Range:    document.toRange(hint.Start, hint.End),
+				Severity: severities[hint.Severity],
+				Code:     string(hint.Code),
+				Source:   serverName,
+				Message:  hint.Message,
+			})
+		}
+
+		return result
+	}
+
+	result := Diagnostic{Severity: errorSeverity, Source: serverName, Message: err.Error()}
+	if located, ok := err.(diagnostic.Diagnostic); ok {
+		start, end := located.Span()
+		result.Range = document.toRange(start, end)
+		result.Code = string(located.ErrorCode())
+		result.Message = strings.TrimSuffix(located.Error(), " at "+start.String())
+	}
+
+	return []Diagnostic{result}
+}
+
+// Hover describes the name at position, or returns nil.
+func (server *Server) Hover(uri string, position Position) *Hover {
+	document, reference, ok := server.referenceAt(uri, position)
+	if !ok {
+		return nil
+	}
+
+	return &Hover{
+		Contents: markupContent{Kind: "markdown", Value: describe(reference)},
+		Range:    document.toRange(reference.Identifier.Pos(), reference.Identifier.End()),
+	}
+}
+
+// Definition returns where the name at position is defined, or nil for
+// builtins and anything else not defined in the document.
+func (server *Server) Definition(uri string, position Position) *Location {
+	document, reference, ok := server.referenceAt(uri, position)
+	if !ok || reference.Definition == nil {
+		return nil
+	}
+
+	return &Location{URI: uri, Range: document.toRange(reference.Definition.Start, reference.Definition.End)}
+}
+
+// Completion lists the names in scope at position, then keywords and
+// builtins.
+func (server *Server) Completion(uri string, position Position) []CompletionItem {
+	items := []CompletionItem{}
+	if document, ok := server.documents[uri]; ok {
+		program, _ := parse(document)
+		for _, definition := range newIndex(program).visible(document.toLexer(position)) {
+			items = append(items, CompletionItem{Label: definition.Name, Kind: completionKind(definition.Kind)})
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Label < items[j].Label
+		})
+	}
+
+	for _, keyword := range keywords {
+		items = append(items, CompletionItem{Label: string(keyword), Kind: keywordKind})
+	}
+	for _, builtin := range object.Builtins {
+		items = append(items, CompletionItem{Label: builtin.Name, Kind: functionKind, Detail: "builtin"})
+	}
+
+	return items
+}
+
+func (server *Server) referenceAt(uri string, position Position) (*document, *reference, bool) {
+	document, ok := server.documents[uri]
+	if !ok {
+		return nil, nil, false
+	}
+
+	program, _ := parse(document)
+	reference, ok := newIndex(program).referenceAt(document.toLexer(position))
+	return document, reference, ok
+}
+
+// parse parses a document. On errors the statements before the first one
+// are returned, so names defined there can still be looked up.
+func parse(document *document) (*ast.Program, error) {
+	return parser.New(lexer.New(strings.NewReader(document.text))).ParseProgram()
+}
+
+func completionKind(kind definitionKind) int {
+	switch kind {
+	case functionDefinition:
+		return functionKind
+	case enumDefinition:
+		return enumKind
+	case moduleDefinition:
+		return moduleKind
+	default:
+		return variableKind
+	}
+}
@@ -0,0 +1,237 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const uri = "file:///script.spike"
+
+func frame(messages ...string) string {
+	framed := &strings.Builder{}
+	for _, message := range messages {
+		fmt.Fprintf(framed, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+
+	return framed.String()
+}
+
+func serve(t *testing.T, messages ...string) []map[string]interface{} {
+	output := &strings.Builder{}
+	err := Serve(strings.NewReader(frame(messages...)), output)
+	require.NoError(t, err)
+
+	var responses []map[string]interface{}
+	reader := bufio.NewReader(strings.NewReader(output.String()))
+	for {
+		content, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+		response := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(content, &response))
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
+func didOpen(text string) string {
+	params, _ := json.Marshal(map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "spike", "version": 1, "text": text},
+	})
+	return fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":%s}`, params)
+}
+
+func at(id int, method string, line int, character int) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":{"textDocument":{"uri":"%s"},"position":{"line":%d,"character":%d}}}`, id, method, uri, line, character)
+}
+
+func TestServe_initialize(t *testing.T) {
+	responses := serve(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"initialize","params":{}}`,
+	)
+
+	require.Len(t, responses, 2)
+	capabilities := responses[0]["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	assert.Equal(t, float64(fullSync), capabilities["textDocumentSync"])
+	assert.Equal(t, true, capabilities["hoverProvider"])
+	assert.Equal(t, true, capabilities["definitionProvider"])
+	assert.Equal(t, float64(2), responses[1]["id"])
+	assert.Nil(t, responses[1]["result"])
+}
+
+func TestServe_unknownMethod(t *testing.T) {
+	responses := serve(t,
+		`{"jsonrpc":"2.0","method":"workspace/didChangeConfiguration","params":{}}`,
+		`{"jsonrpc":"2.0","id":"a","method":"workspace/symbol","params":{}}`,
+	)
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, "a", responses[0]["id"])
+	assert.Equal(t, float64(methodNotFoundCode), responses[0]["error"].(map[string]interface{})["code"])
+}
+
+func TestServe_messageTooLarge(t *testing.T) {
+	responses := serve(t,
+		strings.Repeat(" ", maxContentLength+1),
+		`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`,
+	)
+
+	require.Len(t, responses, 2)
+	assert.Nil(t, responses[0]["id"])
+	assert.Equal(t, map[string]interface{}{
+		"code":    float64(invalidRequestCode),
+		"message": "message is longer than 16777216 bytes",
+	}, responses[0]["error"])
+	assert.Equal(t, float64(1), responses[1]["id"])
+}
+
+func TestServe_diagnostics(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected string
+	}{
+		"valid": {
+			source:   "let x = 1;\nx + 1",
+			expected: `[]`,
+		},
+		"parse error": {
+			source:   "let x = 1;\nlet = 2;",
+			expected: `[{"range":{"start":{"line":1,"character":4},"end":{"line":1,"character":5}},"severity":1,"code":"invalid-syntax","source":"spike-lsp","message":"expected identifier, got assign"}]`,
+		},
+		"compile error": {
+			source:   "let x = 1;\nx + y",
+			expected: `[{"range":{"start":{"line":1,"character":4},"end":{"line":1,"character":5}},"severity":1,"code":"unresolved-identifier","source":"spike-lsp","message":"unable to resolve identifier: y"}]`,
+		},
+		"recursive calls": {
+			source:   "let f = fn(n) {\n  if (n > 0) { f(n - 1) } else { 1 + f(0) }\n}",
+			expected: `[{"range":{"start":{"line":1,"character":15},"end":{"line":1,"character":23}},"severity":3,"code":"tail-call","source":"spike-lsp","message":"recursive call of f is in tail position, it still keeps a frame until it returns"},{"range":{"start":{"line":1,"character":37},"end":{"line":1,"character":41}},"severity":2,"code":"non-tail-call","source":"spike-lsp","message":"recursive call of f is not in tail position"}]`,
+		},
+		"wide characters before the error": {
+			source:   `"🙂" + y`,
+			expected: `[{"range":{"start":{"line":0,"character":7},"end":{"line":0,"character":8}},"severity":1,"code":"unresolved-identifier","source":"spike-lsp","message":"unable to resolve identifier: y"}]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := serve(t, didOpen(test.source))
+
+			require.Len(t, responses, 1)
+			assert.Equal(t, "textDocument/publishDiagnostics", responses[0]["method"])
+			params := responses[0]["params"].(map[string]interface{})
+			assert.Equal(t, uri, params["uri"])
+			diagnostics, _ := json.Marshal(params["diagnostics"])
+			assert.JSONEq(t, test.expected, string(diagnostics))
+		})
+	}
+}
+
+func TestServe_didChangeAndClose(t *testing.T) {
+	responses := serve(t,
+		didOpen("x"),
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"`+uri+`","version":2},"contentChanges":[{"text":"let x = 1; x"}]}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"`+uri+`"}}}`,
+	)
+
+	require.Len(t, responses, 3)
+	counts := make([]int, len(responses))
+	for i, response := range responses {
+		counts[i] = len(response["params"].(map[string]interface{})["diagnostics"].([]interface{}))
+	}
+	assert.Equal(t, []int{1, 0, 0}, counts)
+}
+
+func TestServe_hover(t *testing.T) {
+	source := "let add = fn(a, b) { \"Adds numbers.\"; a + b };\nadd(len([]), 2)"
+	tests := map[string]struct {
+		character int
+		expected  interface{}
+	}{
+		"function": {
+			character: 1,
+			expected:  "```spike\nfn add(a, b)\n```\n\nAdds numbers.",
+		},
+		"builtin": {
+			character: 5,
+			expected:  "```spike\n(builtin) len\n```",
+		},
+		"nothing": {
+			character: 12,
+			expected:  nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := serve(t, didOpen(source), at(1, "textDocument/hover", 1, test.character))
+
+			require.Len(t, responses, 2)
+			result, ok := responses[1]["result"].(map[string]interface{})
+			if test.expected == nil {
+				assert.Nil(t, responses[1]["result"])
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, test.expected, result["contents"].(map[string]interface{})["value"])
+		})
+	}
+}
+
+func TestServe_definition(t *testing.T) {
+	responses := serve(t,
+		didOpen("let x = 1;\nlet f = fn(x) { x };\nx + f(2) + len([])"),
+		at(1, "textDocument/definition", 1, 16),
+		at(2, "textDocument/definition", 2, 0),
+		at(3, "textDocument/definition", 2, 12),
+	)
+
+	require.Len(t, responses, 4)
+	parameter, _ := json.Marshal(responses[1]["result"])
+	assert.JSONEq(t, `{"uri":"`+uri+`","range":{"start":{"line":1,"character":11},"end":{"line":1,"character":12}}}`, string(parameter))
+	global, _ := json.Marshal(responses[2]["result"])
+	assert.JSONEq(t, `{"uri":"`+uri+`","range":{"start":{"line":0,"character":4},"end":{"line":0,"character":5}}}`, string(global))
+	assert.Nil(t, responses[3]["result"])
+}
+
+func TestServe_completion(t *testing.T) {
+	responses := serve(t,
+		didOpen("let total = 1;\nlet f = fn(count) {\n  \n};\nlet later = 2;"),
+		at(1, "textDocument/completion", 2, 2),
+	)
+
+	require.Len(t, responses, 2)
+	labels := map[string]float64{}
+	for _, item := range responses[1]["result"].([]interface{}) {
+		item := item.(map[string]interface{})
+		labels[item["label"].(string)] = item["kind"].(float64)
+	}
+	assert.Equal(t, float64(variableKind), labels["total"])
+	assert.Equal(t, float64(functionKind), labels["f"])
+	assert.Equal(t, float64(variableKind), labels["count"])
+	assert.Equal(t, float64(functionKind), labels["len"])
+	assert.Equal(t, float64(keywordKind), labels["let"])
+	assert.NotContains(t, labels, "later")
+}
+
+func TestServe_semanticTokens(t *testing.T) {
+	responses := serve(t,
+		didOpen("let s = \"🙂\"; // note\ns + \"a\nb\""),
+		`{"jsonrpc":"2.0","id":1,"method":"textDocument/semanticTokens/full","params":{"textDocument":{"uri":"`+uri+`"}}}`,
+	)
+
+	require.Len(t, responses, 2)
+	data, _ := json.Marshal(responses[1]["result"])
+	assert.JSONEq(t, `{"data":[0,0,3,0,0, 0,4,1,1,0, 0,2,1,5,0, 0,2,4,3,0, 0,6,7,4,0, 1,0,1,1,0, 0,2,1,5,0, 0,2,2,3,0]}`, string(data))
+}
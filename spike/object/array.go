@@ -4,6 +4,7 @@ import "strings"
 
 type Array struct {
 	Elements []Object
+	Frozen   bool
 }
 
 func (array *Array) Type() ObjectType {
@@ -11,11 +12,21 @@ func (array *Array) Type() ObjectType {
 }
 
 func (array *Array) Inspect() string {
+	return array.inspect(inspecting{})
+}
+
+func (array *Array) inspect(seen inspecting) string {
+	if seen[array] {
+		return "[...]"
+	}
+	seen[array] = true
+	defer delete(seen, array)
+
 	out := strings.Builder{}
 
 	out.WriteString("[")
 	for i, element := range array.Elements {
-		out.WriteString(element.Inspect())
+		out.WriteString(inspectNested(element, seen))
 		if i < len(array.Elements)-1 {
 			out.WriteString(", ")
 		}
@@ -26,6 +37,10 @@ func (array *Array) Inspect() string {
 }
 
 func (array *Array) Equal(other Object) bool {
+	return array.equal(other, comparing{})
+}
+
+func (array *Array) equal(other Object, seen comparing) bool {
 	otherArray, ok := other.(*Array)
 	if !ok {
 		return false
@@ -35,8 +50,15 @@ func (array *Array) Equal(other Object) bool {
 		return false
 	}
 
+	pair := comparison{left: array, right: otherArray}
+	if seen[pair] {
+		return true
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
 	for i := range array.Elements {
-		if !array.Elements[i].Equal(otherArray.Elements[i]) {
+		if !equalNested(array.Elements[i], otherArray.Elements[i], seen) {
 			return false
 		}
 	}
@@ -0,0 +1,49 @@
+package object
+
+import "github.com/pkg/errors"
+
+// IndexOutOfRange reports index missing from an array or string of length
+// elements, telling which indexes there are.
+func IndexOutOfRange(index int64, length int) error {
+	if length == 0 {
+		return errors.Errorf("index %d out of range, length is 0", index)
+	}
+
+	return errors.Errorf("index %d out of range [0..%d]", index, length-1)
+}
+
+// SetIndex stores value in container at index, as in `array[0] = value`.
+// Arrays are not extended, index has to be an existing position.
+func SetIndex(container Object, index Object, value Object) error {
+	err := CheckMutable(container)
+	if err != nil {
+		return err
+	}
+
+	switch container := container.(type) {
+	case *Array:
+		position, ok := index.(*Integer)
+		if !ok {
+			return errors.Errorf("Array index must be an integer, got: %s", index.Type())
+		}
+
+		if position.Value < 0 || position.Value >= int64(len(container.Elements)) {
+			return IndexOutOfRange(position.Value, len(container.Elements))
+		}
+
+		container.Elements[position.Value] = value
+
+	case *Hash:
+		_, err := HashKeyOf(index)
+		if err != nil {
+			return err
+		}
+
+		container.Set(index, value)
+
+	default:
+		return errors.Errorf("index assignment not supported: %s", container.Type())
+	}
+
+	return nil
+}
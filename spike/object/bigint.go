@@ -0,0 +1,106 @@
+package object
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+type BigInt struct {
+	Value *big.Int
+}
+
+func (bigInt *BigInt) Type() ObjectType {
+	return BigIntType
+}
+
+func (bigInt *BigInt) Inspect() string {
+	return bigInt.Value.String()
+}
+
+func (bigInt *BigInt) Equal(other Object) bool {
+	otherValue, ok := bigValue(other)
+	if !ok {
+		return false
+	}
+
+	return bigInt.Value.Cmp(otherValue) == 0
+}
+
+// BigIntOperation applies an arithmetic or comparison operator when at least
+// one operand is a BigInt, promoting an Integer on the other side. It
+// reports false when neither operand is a BigInt.
+func BigIntOperation(operator string, left Object, right Object) (Object, bool, error) {
+	_, leftIsBig := left.(*BigInt)
+	_, rightIsBig := right.(*BigInt)
+	if !leftIsBig && !rightIsBig {
+		return nil, false, nil
+	}
+
+	leftValue, leftOk := bigValue(left)
+	rightValue, rightOk := bigValue(right)
+	if !leftOk || !rightOk {
+		return nil, true, errors.Errorf("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	result := new(big.Int)
+	switch operator {
+	case "+":
+		return &BigInt{Value: result.Add(leftValue, rightValue)}, true, nil
+	case "-":
+		return &BigInt{Value: result.Sub(leftValue, rightValue)}, true, nil
+	case "*":
+		return &BigInt{Value: result.Mul(leftValue, rightValue)}, true, nil
+	case "/":
+		if rightValue.Sign() == 0 {
+			return nil, true, errors.New("division by zero")
+		}
+		return &BigInt{Value: result.Quo(leftValue, rightValue)}, true, nil
+	case "==":
+		return nativeBoolToBoolean(leftValue.Cmp(rightValue) == 0), true, nil
+	case "!=":
+		return nativeBoolToBoolean(leftValue.Cmp(rightValue) != 0), true, nil
+	case "<":
+		return nativeBoolToBoolean(leftValue.Cmp(rightValue) < 0), true, nil
+	case ">":
+		return nativeBoolToBoolean(leftValue.Cmp(rightValue) > 0), true, nil
+	}
+
+	return nil, true, errors.Errorf("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+func bigValue(value Object) (*big.Int, bool) {
+	switch value := value.(type) {
+	case *BigInt:
+		return value.Value, true
+	case *Integer:
+		return big.NewInt(value.Value), true
+	}
+
+	return nil, false
+}
+
+func bigint(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("bigint", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch argument := args[0].(type) {
+	case *Integer:
+		return &BigInt{Value: big.NewInt(argument.Value)}, nil
+
+	case *BigInt:
+		return argument, nil
+
+	case *String:
+		value, ok := new(big.Int).SetString(argument.Value, 10)
+		if !ok {
+			return nil, errors.Errorf("bigint: invalid integer %q", argument.Value)
+		}
+
+		return &BigInt{Value: value}, nil
+	}
+
+	return nil, unsupportedArgument("bigint", args[0])
+}
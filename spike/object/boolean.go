@@ -35,3 +35,11 @@ func (boolean *Boolean) GetHashKey() HashKey {
 
 	return HashKey{Type: BooleanType, Value: 0}
 }
+
+func nativeBoolToBoolean(nativeBool bool) *Boolean {
+	if nativeBool {
+		return &True
+	}
+
+	return &False
+}
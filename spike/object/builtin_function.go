@@ -4,7 +4,10 @@ import "fmt"
 
 type BuiltinFunction struct {
 	Name     string
-	Function func(args ...Object) (Object, error)
+	Function func(runtime Runtime, args ...Object) (Object, error)
+	// Freezes marks builtins returning their only argument frozen, the
+	// compiler then freezes literal arguments once instead of on every call.
+	Freezes bool
 }
 
 func (builtin *BuiltinFunction) Type() ObjectType {
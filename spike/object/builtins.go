@@ -1,51 +1,379 @@
 package object
 
 import (
-	"fmt"
 	"github.com/pkg/errors"
 )
 
 var Builtins = []*BuiltinFunction{
 	{
 		Name: "len",
-		Function: func(args ...Object) (Object, error) {
-			if len(args) != 1 {
-				return nil, errors.New("1 function argument expected")
+		Function: func(_ Runtime, args ...Object) (Object, error) {
+			err := checkArgumentsCount("len", args, 1)
+			if err != nil {
+				return nil, err
 			}
 
 			switch argument := args[0].(type) {
 			case *String:
-				return &Integer{Value: int64(len(argument.Value))}, nil
+				return &Integer{Value: int64(argument.Length())}, nil
 
 			case *Array:
 				return &Integer{Value: int64(len(argument.Elements))}, nil
+
+			case *Tuple:
+				return &Integer{Value: int64(len(argument.Elements))}, nil
+
+			case *Hash:
+				return &Integer{Value: int64(len(argument.Pairs))}, nil
+
+			case *Bytes:
+				return &Integer{Value: int64(len(argument.Value))}, nil
+			}
+
+			return nil, unsupportedArgument("len", args[0])
+		},
+	},
+	{
+		Name: "type",
+		Function: func(_ Runtime, args ...Object) (Object, error) {
+			err := checkArgumentsCount("type", args, 1)
+			if err != nil {
+				return nil, err
 			}
 
-			stringObject := args[0].(*String)
-			return &Integer{Value: int64(len(stringObject.Value))}, nil
+			return &String{Value: string(args[0].Type())}, nil
 		},
 	},
 	{
-		Name: "print",
-		Function: func(args ...Object) (Object, error) {
-			stringObject := args[0].(*String)
-			fmt.Printf(stringObject.Value)
+		Name: "deepCopy",
+		Function: func(_ Runtime, args ...Object) (Object, error) {
+			err := checkArgumentsCount("deepCopy", args, 1)
+			if err != nil {
+				return nil, err
+			}
 
-			return nil, nil
+			return DeepCopy(args[0]), nil
 		},
 	},
 	{
-		Name: "read",
-		Function: func(args ...Object) (Object, error) {
-			var result string
-			_, err := fmt.Scan(&result)
+		Name:    "freeze",
+		Freezes: true,
+		Function: func(_ Runtime, args ...Object) (Object, error) {
+			err := checkArgumentsCount("freeze", args, 1)
 			if err != nil {
 				return nil, err
 			}
 
-			return &String{Value: result}, nil
+			return Freeze(args[0]), nil
 		},
 	},
+	{
+		Name:     "assert",
+		Function: assertCondition,
+	},
+	{
+		Name:     "bigint",
+		Function: bigint,
+	},
+	{
+		Name:     "split",
+		Function: split,
+	},
+	{
+		Name:     "join",
+		Function: join,
+	},
+	{
+		Name:     "trim",
+		Function: trim,
+	},
+	{
+		Name:     "replace",
+		Function: replace,
+	},
+	{
+		Name:     "upper",
+		Function: upper,
+	},
+	{
+		Name:     "lower",
+		Function: lower,
+	},
+	{
+		Name:     "contains",
+		Function: contains,
+	},
+	{
+		Name:     "startsWith",
+		Function: startsWith,
+	},
+	{
+		Name:     "endsWith",
+		Function: endsWith,
+	},
+	{
+		Name:     "indexOf",
+		Function: indexOf,
+	},
+	{
+		Name:     "regex",
+		Function: regex,
+	},
+	{
+		Name:     "regexMatch",
+		Function: regexMatch,
+	},
+	{
+		Name:     "regexFind",
+		Function: regexFind,
+	},
+	{
+		Name:     "regexReplace",
+		Function: regexReplace,
+	},
+	{
+		Name:     "bytes",
+		Function: bytesOf,
+	},
+	{
+		Name:     "slice",
+		Function: slice,
+	},
+	{
+		Name:     "first",
+		Function: first,
+	},
+	{
+		Name:     "last",
+		Function: last,
+	},
+	{
+		Name:     "rest",
+		Function: rest,
+	},
+	{
+		Name:     "push",
+		Function: push,
+	},
+	{
+		Name:     "pop",
+		Function: pop,
+	},
+	{
+		Name:     "keys",
+		Function: keys,
+	},
+	{
+		Name:     "values",
+		Function: values,
+	},
+	{
+		Name:     "has",
+		Function: has,
+	},
+	{
+		Name:     "delete",
+		Function: deleteKey,
+	},
+	{
+		Name:     "merge",
+		Function: merge,
+	},
+	{
+		Name:     "iter",
+		Function: iter,
+	},
+	{
+		Name:     "next",
+		Function: next,
+	},
+	{
+		Name:     "range",
+		Function: rangeOf,
+	},
+	{
+		Name:     "map",
+		Function: mapArray,
+	},
+	{
+		Name:     "filter",
+		Function: filter,
+	},
+	{
+		Name:     "reduce",
+		Function: reduce,
+	},
+	{
+		Name:     "callDepth",
+		Function: callDepth,
+	},
+	{
+		Name:     "stackTrace",
+		Function: stackTrace,
+	},
+	{
+		Name:     "doc",
+		Function: doc,
+	},
+	{
+		Name:     "rand",
+		Function: random,
+	},
+	{
+		Name:     "randInt",
+		Function: randInt,
+	},
+	{
+		Name:     "seed",
+		Function: seed,
+	},
+	{
+		Name:     "readFile",
+		Function: readFile,
+	},
+	{
+		Name:     "writeFile",
+		Function: writeFile,
+	},
+	{
+		Name:     "appendFile",
+		Function: appendFile,
+	},
+	{
+		Name:     "readFileBytes",
+		Function: readFileBytes,
+	},
+	{
+		Name:     "writeFileBytes",
+		Function: writeFileBytes,
+	},
+	{
+		Name:     "listDir",
+		Function: listDir,
+	},
+	{
+		Name:     "now",
+		Function: now,
+	},
+	{
+		Name:     "clock",
+		Function: clock,
+	},
+	{
+		Name:     "sleep",
+		Function: sleep,
+	},
+	{
+		Name:     "httpGet",
+		Function: httpGet,
+	},
+	{
+		Name:     "httpRequest",
+		Function: httpRequest,
+	},
+	{
+		Name:     "exec",
+		Function: execCommand,
+	},
+	{
+		Name:     "print",
+		Function: builtinPrint,
+	},
+	{
+		Name:     "println",
+		Function: builtinPrintln,
+	},
+	{
+		Name:     "normalizeNFC",
+		Function: normalizeNFC,
+	},
+	{
+		Name:     "caseFold",
+		Function: caseFold,
+	},
+	{
+		Name:     "equalsIgnoreCase",
+		Function: equalsIgnoreCase,
+	},
+	{
+		Name:     "printf",
+		Function: printf,
+	},
+	{
+		Name:     "format",
+		Function: format,
+	},
+	{
+		Name:     "read",
+		Function: read,
+	},
+	{
+		Name:     "input",
+		Function: input,
+	},
+	{
+		Name:     "exit",
+		Function: exit,
+	},
+	{
+		Name:     "args",
+		Function: arguments,
+	},
+	{
+		Name:     "eprint",
+		Function: builtinEprint,
+	},
+	{
+		Name:     "eprintln",
+		Function: builtinEprintln,
+	},
+	{
+		Name:     "forAll",
+		Function: forAll,
+	},
+}
+
+func checkArgumentsCount(name string, args []Object, expected int) error {
+	if len(args) == expected {
+		return nil
+	}
+
+	if expected == 1 {
+		return errors.Errorf("%s: expected 1 argument, got %d", name, len(args))
+	}
+
+	return errors.Errorf("%s: expected %d arguments, got %d", name, expected, len(args))
+}
+
+func stringArgument(name string, args []Object, position int) (string, error) {
+	str, ok := args[position].(*String)
+	if !ok {
+		return "", errors.Errorf("%s: argument %d must be %s, got %s", name, position+1, StringType, args[position].Type())
+	}
+
+	return str.Value, nil
+}
+
+func integerArgument(name string, args []Object, position int) (int64, error) {
+	integer, ok := args[position].(*Integer)
+	if !ok {
+		return 0, errors.Errorf("%s: argument %d must be %s, got %s", name, position+1, IntegerType, args[position].Type())
+	}
+
+	return integer.Value, nil
+}
+
+func arrayArgument(name string, args []Object, position int) ([]Object, error) {
+	array, ok := args[position].(*Array)
+	if !ok {
+		return nil, errors.Errorf("%s: argument %d must be %s, got %s", name, position+1, ArrayType, args[position].Type())
+	}
+
+	return array.Elements, nil
+}
+
+func unsupportedArgument(name string, argument Object) error {
+	return errors.Errorf("%s: argument of type %s is not supported", name, argument.Type())
 }
 
 func GetBuiltinByName(name string) *BuiltinFunction {
@@ -0,0 +1,90 @@
+package object
+
+// Array builtins never modify their argument, push, pop and rest always
+// return a new array.
+
+func first(_ Runtime, args ...Object) (Object, error) {
+	elements, err := singleArrayArgument("first", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return &NullObject, nil
+	}
+
+	return elements[0], nil
+}
+
+func last(_ Runtime, args ...Object) (Object, error) {
+	elements, err := singleArrayArgument("last", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return &NullObject, nil
+	}
+
+	return elements[len(elements)-1], nil
+}
+
+func rest(_ Runtime, args ...Object) (Object, error) {
+	elements, err := singleArrayArgument("rest", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return &NullObject, nil
+	}
+
+	return newArray(elements[1:]), nil
+}
+
+func pop(_ Runtime, args ...Object) (Object, error) {
+	elements, err := singleArrayArgument("pop", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return &NullObject, nil
+	}
+
+	return newArray(elements[:len(elements)-1]), nil
+}
+
+func push(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("push", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := arrayArgument("push", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pushed := make([]Object, len(elements)+1)
+	copy(pushed, elements)
+	pushed[len(elements)] = args[1]
+
+	return &Array{Elements: pushed}, nil
+}
+
+func singleArrayArgument(name string, args []Object) ([]Object, error) {
+	err := checkArgumentsCount(name, args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrayArgument(name, args, 0)
+}
+
+func newArray(elements []Object) *Array {
+	copied := make([]Object, len(elements))
+	copy(copied, elements)
+
+	return &Array{Elements: copied}
+}
@@ -0,0 +1,129 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ArrayBuiltins(t *testing.T) {
+	one, two := &Integer{Value: 1}, &Integer{Value: 2}
+
+	testCases := []struct {
+		name           string
+		builtin        string
+		args           []Object
+		expectedResult Object
+	}{
+		{
+			name:           "first",
+			builtin:        "first",
+			args:           []Object{&Array{Elements: []Object{one, two}}},
+			expectedResult: one,
+		},
+		{
+			name:           "first of empty array",
+			builtin:        "first",
+			args:           []Object{&Array{}},
+			expectedResult: &NullObject,
+		},
+		{
+			name:           "last",
+			builtin:        "last",
+			args:           []Object{&Array{Elements: []Object{one, two}}},
+			expectedResult: two,
+		},
+		{
+			name:           "rest",
+			builtin:        "rest",
+			args:           []Object{&Array{Elements: []Object{one, two}}},
+			expectedResult: &Array{Elements: []Object{two}},
+		},
+		{
+			name:           "rest of empty array",
+			builtin:        "rest",
+			args:           []Object{&Array{}},
+			expectedResult: &NullObject,
+		},
+		{
+			name:           "push",
+			builtin:        "push",
+			args:           []Object{&Array{Elements: []Object{one}}, two},
+			expectedResult: &Array{Elements: []Object{one, two}},
+		},
+		{
+			name:           "pop",
+			builtin:        "pop",
+			args:           []Object{&Array{Elements: []Object{one, two}}},
+			expectedResult: &Array{Elements: []Object{one}},
+		},
+		{
+			name:           "pop of empty array",
+			builtin:        "pop",
+			args:           []Object{&Array{}},
+			expectedResult: &NullObject,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := GetBuiltinByName(testCase.builtin).Function(nil, testCase.args...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+func Test_ArrayBuiltins_doNotModifyArgument(t *testing.T) {
+	elements := make([]Object, 1, 4)
+	elements[0] = &Integer{Value: 1}
+	array := &Array{Elements: elements}
+
+	pushed, err := GetBuiltinByName("push").Function(nil, array, &Integer{Value: 2})
+	assert.NoError(t, err)
+	pushed.(*Array).Elements[0] = &Integer{Value: 3}
+
+	popped, err := GetBuiltinByName("pop").Function(nil, array)
+	assert.NoError(t, err)
+	assert.Equal(t, &Array{Elements: []Object{}}, popped)
+
+	assert.Equal(t, &Array{Elements: []Object{&Integer{Value: 1}}}, array)
+}
+
+func Test_ArrayBuiltins_invalidArguments(t *testing.T) {
+	testCases := []struct {
+		builtin       string
+		args          []Object
+		expectedError string
+	}{
+		{
+			builtin:       "first",
+			args:          []Object{},
+			expectedError: "first: expected 1 argument, got 0",
+		},
+		{
+			builtin:       "rest",
+			args:          []Object{&String{Value: "abc"}},
+			expectedError: "rest: argument 1 must be array, got string",
+		},
+		{
+			builtin:       "push",
+			args:          []Object{&Array{}},
+			expectedError: "push: expected 2 arguments, got 1",
+		},
+		{
+			builtin:       "push",
+			args:          []Object{&Integer{Value: 1}, &Array{}},
+			expectedError: "push: argument 1 must be array, got integer",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			_, err := GetBuiltinByName(testCase.builtin).Function(nil, testCase.args...)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package object
+
+import "github.com/pkg/errors"
+
+func assertCondition(_ Runtime, args ...Object) (Object, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.Errorf("assert: expected 1 or 2 arguments, got %d", len(args))
+	}
+
+	condition, ok := args[0].(*Boolean)
+	if !ok {
+		return nil, errors.Errorf("assert: argument 1 must be %s, got %s", BooleanType, args[0].Type())
+	}
+
+	message := "assertion failed"
+	if len(args) == 2 {
+		detail, err := stringArgument("assert", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		message += ": " + detail
+	}
+
+	if !condition.Value {
+		return nil, errors.New(message)
+	}
+
+	return &NullObject, nil
+}
@@ -0,0 +1,130 @@
+package object
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// bytesOf converts a string to its UTF-8 bytes, or an array of integers
+// between 0 and 255 to the corresponding bytes.
+func bytesOf(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("bytes", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch argument := args[0].(type) {
+	case *String:
+		return &Bytes{Value: []byte(argument.Value)}, nil
+
+	case *Bytes:
+		return &Bytes{Value: append([]byte{}, argument.Value...)}, nil
+
+	case *Array:
+		value := make([]byte, len(argument.Elements))
+		for i, element := range argument.Elements {
+			integer, ok := element.(*Integer)
+			if !ok || integer.Value < 0 || integer.Value > 255 {
+				return nil, errors.Errorf("bytes: element %d must be an integer between 0 and 255, got %s", i, element.Inspect())
+			}
+			value[i] = byte(integer.Value)
+		}
+
+		return &Bytes{Value: value}, nil
+	}
+
+	return nil, unsupportedArgument("bytes", args[0])
+}
+
+// slice returns the part of an array, string or bytes between start,
+// inclusive, and end, exclusive. Strings are sliced by byte offsets.
+func slice(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("slice", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := integerArgument("slice", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := integerArgument("slice", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	var length int
+	switch argument := args[0].(type) {
+	case *Array:
+		length = len(argument.Elements)
+	case *String:
+		length = argument.Length()
+	case *Bytes:
+		length = len(argument.Value)
+	default:
+		return nil, unsupportedArgument("slice", args[0])
+	}
+
+	if start < 0 || end < start || end > int64(length) {
+		return nil, errors.Errorf("slice: bounds [%d:%d] out of range for length %d", start, end, length)
+	}
+
+	switch argument := args[0].(type) {
+	case *Array:
+		return newArray(argument.Elements[start:end]), nil
+	case *String:
+		return &String{Value: string([]rune(argument.Value)[start:end])}, nil
+	}
+
+	return &Bytes{Value: append([]byte{}, args[0].(*Bytes).Value[start:end]...)}, nil
+}
+
+func readFileBytes(runtime Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("readFileBytes", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runtime.Policy().Check("readFileBytes", FileReadCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(strs[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "readFileBytes")
+	}
+
+	return &Bytes{Value: content}, nil
+}
+
+func writeFileBytes(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("writeFileBytes", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := stringArgument("writeFileBytes", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := args[1].(*Bytes)
+	if !ok {
+		return nil, errors.Errorf("writeFileBytes: argument 2 must be %s, got %s", BytesType, args[1].Type())
+	}
+
+	err = runtime.Policy().Check("writeFileBytes", FileWriteCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(path, content.Value, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "writeFileBytes")
+	}
+
+	return &NullObject, nil
+}
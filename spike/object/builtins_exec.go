@@ -0,0 +1,50 @@
+package object
+
+import (
+	"bytes"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// execCommand runs a program without a shell and returns its stdout, stderr
+// and exit code. A non-zero exit code is not an error.
+func execCommand(runtime Runtime, args ...Object) (Object, error) {
+	if len(args) == 0 {
+		return nil, errors.New("exec: expected at least 1 argument, got 0")
+	}
+
+	strs := make([]string, len(args))
+	for i := range args {
+		str, err := stringArgument("exec", args, i)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = str
+	}
+
+	err := runtime.Policy().Check("exec", ProcessCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	command := exec.CommandContext(runtime.Context(), strs[0], strs[1:]...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	exitCode := 0
+	err = command.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && runtime.Context().Err() == nil {
+		exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, errors.Wrap(err, "exec")
+	}
+
+	result := NewHash(3)
+	setStringKey(result, "stdout", &String{Value: stdout.String()})
+	setStringKey(result, "stderr", &String{Value: stderr.String()})
+	setStringKey(result, "exitCode", &Integer{Value: int64(exitCode)})
+
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func readFile(runtime Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("readFile", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runtime.Policy().Check("readFile", FileReadCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(strs[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "readFile")
+	}
+
+	return &String{Value: string(content)}, nil
+}
+
+func writeFile(runtime Runtime, args ...Object) (Object, error) {
+	return writeToFile(runtime, "writeFile", os.O_TRUNC, args)
+}
+
+func appendFile(runtime Runtime, args ...Object) (Object, error) {
+	return writeToFile(runtime, "appendFile", os.O_APPEND, args)
+}
+
+// listDir returns the names of the directory entries sorted by name.
+func listDir(runtime Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("listDir", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runtime.Policy().Check("listDir", FileReadCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(strs[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "listDir")
+	}
+
+	names := make([]Object, len(entries))
+	for i, entry := range entries {
+		names[i] = &String{Value: entry.Name()}
+	}
+
+	return &Array{Elements: names}, nil
+}
+
+func writeToFile(runtime Runtime, name string, mode int, args []Object) (Object, error) {
+	strs, err := stringArguments(name, args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runtime.Policy().Check(name, FileWriteCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(strs[0], os.O_WRONLY|os.O_CREATE|mode, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	_, err = file.WriteString(strs[1])
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, name)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	return &NullObject, nil
+}
@@ -0,0 +1,110 @@
+package object
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func format(_ Runtime, args ...Object) (Object, error) {
+	formatted, err := formatArguments("format", args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: formatted}, nil
+}
+
+func printf(runtime Runtime, args ...Object) (Object, error) {
+	formatted, err := formatArguments("printf", args)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprint(runtime.Stdout(), formatted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NullObject, nil
+}
+
+// formatArguments expands the verbs of the format string in args[0]: %d for
+// integers, %f for numbers, %s for strings, %v for any value as print shows
+// it and %% for a percent sign.
+func formatArguments(name string, args []Object) (string, error) {
+	if len(args) == 0 {
+		return "", errors.Errorf("%s: expected at least 1 argument, got 0", name)
+	}
+
+	template, err := stringArgument(name, args, 0)
+	if err != nil {
+		return "", err
+	}
+
+	out := strings.Builder{}
+	next := 1
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			out.WriteByte(template[i])
+			continue
+		}
+
+		i++
+		if i == len(template) {
+			return "", errors.Errorf("%s: format string ends with %%", name)
+		}
+
+		verb := template[i]
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		if next == len(args) {
+			return "", errors.Errorf("%s: missing argument for %%%c", name, verb)
+		}
+
+		formatted, err := formatVerb(name, verb, args[next])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(formatted)
+		next++
+	}
+
+	if next < len(args) {
+		return "", errors.Errorf("%s: %d unused arguments", name, len(args)-next)
+	}
+
+	return out.String(), nil
+}
+
+func formatVerb(name string, verb byte, arg Object) (string, error) {
+	switch verb {
+	case 'd':
+		if integer, ok := arg.(*Integer); ok {
+			return strconv.FormatInt(integer.Value, 10), nil
+		}
+
+	case 'f':
+		if integer, ok := arg.(*Integer); ok {
+			return strconv.FormatFloat(float64(integer.Value), 'f', 6, 64), nil
+		}
+
+	case 's':
+		if str, ok := arg.(*String); ok {
+			return str.Value, nil
+		}
+
+	case 'v':
+		return joinForPrinting([]Object{arg}), nil
+
+	default:
+		return "", errors.Errorf("%s: unknown verb %%%c", name, verb)
+	}
+
+	return "", errors.Errorf("%s: %%%c does not accept %s", name, verb, arg.Type())
+}
@@ -0,0 +1,85 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Format(t *testing.T) {
+	testCases := []struct {
+		args           []Object
+		expectedResult string
+	}{
+		{
+			args:           []Object{&String{Value: "no verbs"}},
+			expectedResult: "no verbs",
+		},
+		{
+			args:           []Object{&String{Value: "x=%d y=%s"}, &Integer{Value: -4}, &String{Value: "żółw"}},
+			expectedResult: "x=-4 y=żółw",
+		},
+		{
+			args:           []Object{&String{Value: "%f%%"}, &Integer{Value: 3}},
+			expectedResult: "3.000000%",
+		},
+		{
+			args: []Object{
+				&String{Value: "%v %v %v"},
+				&String{Value: "raw"},
+				&Array{Elements: []Object{&String{Value: "a"}}},
+				&NullObject,
+			},
+			expectedResult: `raw ["a"] null`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedResult, func(t *testing.T) {
+			result, err := GetBuiltinByName("format").Function(nil, testCase.args...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, &String{Value: testCase.expectedResult}, result)
+		})
+	}
+}
+
+func Test_Format_invalidArguments(t *testing.T) {
+	testCases := []struct {
+		args          []Object
+		expectedError string
+	}{
+		{
+			args:          []Object{},
+			expectedError: "format: expected at least 1 argument, got 0",
+		},
+		{
+			args:          []Object{&String{Value: "%d and %d"}, &Integer{Value: 1}},
+			expectedError: "format: missing argument for %d",
+		},
+		{
+			args:          []Object{&String{Value: "%s"}, &String{Value: "a"}, &String{Value: "b"}},
+			expectedError: "format: 1 unused arguments",
+		},
+		{
+			args:          []Object{&String{Value: "%d"}, &String{Value: "1"}},
+			expectedError: "format: %d does not accept string",
+		},
+		{
+			args:          []Object{&String{Value: "%x"}, &Integer{Value: 1}},
+			expectedError: "format: unknown verb %x",
+		},
+		{
+			args:          []Object{&String{Value: "100%"}},
+			expectedError: "format: format string ends with %",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			_, err := GetBuiltinByName("format").Function(nil, testCase.args...)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
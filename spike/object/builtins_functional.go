@@ -0,0 +1,79 @@
+package object
+
+func mapArray(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("map", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := iterableArgument("map", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := []Object{}
+	err = Each(iterable, func(element Object) error {
+		result, err := runtime.Call(args[1], element)
+		mapped = append(mapped, result)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Array{Elements: mapped}, nil
+}
+
+func filter(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("filter", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := iterableArgument("filter", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []Object{}
+	err = Each(iterable, func(element Object) error {
+		result, err := runtime.Call(args[1], element)
+		if err != nil {
+			return err
+		}
+
+		if Truthy(result) {
+			filtered = append(filtered, element)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Array{Elements: filtered}, nil
+}
+
+func reduce(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("reduce", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := iterableArgument("reduce", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	accumulator := args[1]
+	err = Each(iterable, func(element Object) error {
+		accumulator, err = runtime.Call(args[2], accumulator, element)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accumulator, nil
+}
@@ -0,0 +1,142 @@
+package object
+
+import "github.com/pkg/errors"
+
+// Hash builtins never modify their arguments, delete and merge return a new
+// hash. keys and values list entries in insertion order.
+
+func keys(_ Runtime, args ...Object) (Object, error) {
+	pairs, err := singleHashArgument("keys", args)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Key
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+func values(_ Runtime, args ...Object) (Object, error) {
+	pairs, err := singleHashArgument("values", args)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]Object, len(pairs))
+	for i, pair := range pairs {
+		elements[i] = pair.Value
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+func has(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("has", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashArgument("has", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hashKeyArgument("has", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := hash.Pairs[key]
+
+	return nativeBoolToBoolean(ok), nil
+}
+
+func deleteKey(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("delete", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashArgument("delete", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hashKeyArgument("delete", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := copyHash(hash)
+	result.Delete(key)
+
+	return result, nil
+}
+
+func merge(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("merge", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := hashArgument("merge", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := hashArgument("merge", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := copyHash(left)
+	for _, pair := range right.OrderedPairs() {
+		result.Set(pair.Key, pair.Value)
+	}
+
+	return result, nil
+}
+
+func singleHashArgument(name string, args []Object) ([]HashPair, error) {
+	err := checkArgumentsCount(name, args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashArgument(name, args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return hash.OrderedPairs(), nil
+}
+
+func hashArgument(name string, args []Object, position int) (*Hash, error) {
+	hash, ok := args[position].(*Hash)
+	if !ok {
+		return nil, errors.Errorf("%s: argument %d must be %s, got %s", name, position+1, HashType, args[position].Type())
+	}
+
+	return hash, nil
+}
+
+func hashKeyArgument(name string, args []Object, position int) (HashKey, error) {
+	key, err := HashKeyOf(args[position])
+	if err != nil {
+		return HashKey{}, errors.Wrap(err, name)
+	}
+
+	return key, nil
+}
+
+func copyHash(hash *Hash) *Hash {
+	result := NewHash(len(hash.Pairs))
+	for _, pair := range hash.OrderedPairs() {
+		result.Set(pair.Key, pair.Value)
+	}
+
+	return result
+}
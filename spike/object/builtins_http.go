@@ -0,0 +1,137 @@
+package object
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+func httpGet(runtime Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("httpGet", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return doHTTPRequest(runtime, "httpGet", http.MethodGet, strs[0], nil, "")
+}
+
+// httpRequest sends the request described by a hash with an url and optional
+// method, headers and body entries.
+func httpRequest(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("httpRequest", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := hashArgument("httpRequest", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := stringEntry("httpRequest", request, "url", "")
+	if err != nil {
+		return nil, err
+	}
+	if url == "" {
+		return nil, errors.New(`httpRequest: missing "url"`)
+	}
+
+	method, err := stringEntry("httpRequest", request, "method", http.MethodGet)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := stringEntry("httpRequest", request, "body", "")
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{}
+	if entry, ok := request.Pairs[(&String{Value: "headers"}).GetHashKey()]; ok {
+		headerHash, ok := entry.Value.(*Hash)
+		if !ok {
+			return nil, errors.Errorf(`httpRequest: "headers" must be %s, got %s`, HashType, entry.Value.Type())
+		}
+
+		for _, pair := range headerHash.Pairs {
+			name, nameOk := pair.Key.(*String)
+			value, valueOk := pair.Value.(*String)
+			if !nameOk || !valueOk {
+				return nil, errors.New(`httpRequest: "headers" must map strings to strings`)
+			}
+			headers[name.Value] = value.Value
+		}
+	}
+
+	return doHTTPRequest(runtime, "httpRequest", strings.ToUpper(method), url, headers, body)
+}
+
+func doHTTPRequest(runtime Runtime, name string, method string, url string, headers map[string]string, body string) (Object, error) {
+	err := runtime.Policy().Check(name, NetworkCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	request = request.WithContext(runtime.Context())
+
+	for header, value := range headers {
+		request.Header.Set(header, value)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	names := make([]string, 0, len(response.Header))
+	for header := range response.Header {
+		names = append(names, header)
+	}
+	sort.Strings(names)
+
+	responseHeaders := NewHash(len(names))
+	for _, header := range names {
+		setStringKey(responseHeaders, header, &String{Value: strings.Join(response.Header[header], ", ")})
+	}
+
+	result := NewHash(3)
+	setStringKey(result, "status", &Integer{Value: int64(response.StatusCode)})
+	setStringKey(result, "headers", responseHeaders)
+	setStringKey(result, "body", &String{Value: string(responseBody)})
+
+	return result, nil
+}
+
+func stringEntry(name string, hash *Hash, key string, defaultValue string) (string, error) {
+	pair, ok := hash.Pairs[(&String{Value: key}).GetHashKey()]
+	if !ok {
+		return defaultValue, nil
+	}
+
+	str, ok := pair.Value.(*String)
+	if !ok {
+		return "", errors.Errorf("%s: %q must be %s, got %s", name, key, StringType, pair.Value.Type())
+	}
+
+	return str.Value, nil
+}
+
+func setStringKey(hash *Hash, key string, value Object) {
+	hash.Set(&String{Value: key}, value)
+}
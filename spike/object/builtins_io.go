@@ -0,0 +1,121 @@
+package object
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func builtinPrint(runtime Runtime, args ...Object) (Object, error) {
+	_, err := fmt.Fprint(runtime.Stdout(), joinForPrinting(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NullObject, nil
+}
+
+func builtinPrintln(runtime Runtime, args ...Object) (Object, error) {
+	_, err := fmt.Fprintln(runtime.Stdout(), joinForPrinting(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NullObject, nil
+}
+
+// builtinEprint prints like print, to the engine's standard error.
+func builtinEprint(runtime Runtime, args ...Object) (Object, error) {
+	_, err := fmt.Fprint(runtime.Stderr(), joinForPrinting(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NullObject, nil
+}
+
+func builtinEprintln(runtime Runtime, args ...Object) (Object, error) {
+	_, err := fmt.Fprintln(runtime.Stderr(), joinForPrinting(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NullObject, nil
+}
+
+// joinForPrinting renders strings without quotes and everything else the
+// way Inspect does, separating values with a single space.
+func joinForPrinting(args []Object) string {
+	printed := make([]string, len(args))
+	for i, arg := range args {
+		if str, ok := arg.(*String); ok {
+			printed[i] = str.Value
+		} else {
+			printed[i] = arg.Inspect()
+		}
+	}
+
+	return strings.Join(printed, " ")
+}
+
+// input writes the optional prompt and reads one line, without its line
+// ending. It returns null once the input is exhausted.
+func input(runtime Runtime, args ...Object) (Object, error) {
+	if len(args) > 1 {
+		return nil, errors.Errorf("input: expected at most 1 argument, got %d", len(args))
+	}
+
+	if len(args) == 1 {
+		prompt, err := stringArgument("input", args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = fmt.Fprint(runtime.Stdout(), prompt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	line, err := runtime.Stdin().ReadString('\n')
+	if err == io.EOF && line == "" {
+		return &NullObject, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "input")
+	}
+
+	return &String{Value: strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")}, nil
+}
+
+func read(runtime Runtime, args ...Object) (Object, error) {
+	var result string
+	_, err := fmt.Fscan(runtime.Stdin(), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: result}, nil
+}
+
+func exit(_ Runtime, args ...Object) (Object, error) {
+	if len(args) > 1 {
+		return nil, errors.Errorf("exit: expected at most 1 argument, got %d", len(args))
+	}
+
+	if len(args) == 0 {
+		return nil, &ExitError{Code: 0}
+	}
+
+	code, err := integerArgument("exit", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if code < 0 || code > MaxExitCode {
+		return nil, errors.Errorf("exit: code must be between 0 and %d, got %d", MaxExitCode, code)
+	}
+
+	return nil, &ExitError{Code: int(code)}
+}
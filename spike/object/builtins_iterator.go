@@ -0,0 +1,79 @@
+package object
+
+import "github.com/pkg/errors"
+
+func iter(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("iter", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := iterableArgument("iter", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return iterable.Iterate(), nil
+}
+
+// next returns the following value of the iterator, or null once it is
+// exhausted.
+func next(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("next", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	it, ok := args[0].(Iterator)
+	if !ok {
+		return nil, errors.Errorf("next: argument 1 must be %s, got %s", IteratorType, args[0].Type())
+	}
+
+	value, ok, err := it.Next()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &NullObject, nil
+	}
+
+	return value, nil
+}
+
+// rangeOf builds range(end), range(start, end) or range(start, end, step).
+func rangeOf(_ Runtime, args ...Object) (Object, error) {
+	if len(args) == 0 || len(args) > 3 {
+		return nil, errors.Errorf("range: expected 1 to 3 arguments, got %d", len(args))
+	}
+
+	bounds := make([]int64, len(args))
+	for i := range args {
+		bound, err := integerArgument("range", args, i)
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = bound
+	}
+
+	switch len(bounds) {
+	case 1:
+		return &Range{Start: 0, End: bounds[0], Step: 1}, nil
+	case 2:
+		return &Range{Start: bounds[0], End: bounds[1], Step: 1}, nil
+	}
+
+	if bounds[2] == 0 {
+		return nil, errors.New("range: step must not be zero")
+	}
+
+	return &Range{Start: bounds[0], End: bounds[1], Step: bounds[2]}, nil
+}
+
+func iterableArgument(name string, args []Object, position int) (Iterable, error) {
+	iterable, ok := args[position].(Iterable)
+	if !ok {
+		return nil, errors.Errorf("%s: argument of type %s is not iterable", name, args[position].Type())
+	}
+
+	return iterable, nil
+}
@@ -0,0 +1,378 @@
+package object
+
+import (
+	"context"
+	"math/rand"
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// propertyRuns is how many random inputs forAll checks a property with.
+const propertyRuns = 100
+
+// propertyShrinks bounds how many smaller inputs forAll checks a property
+// with once it failed.
+const propertyShrinks = 1000
+
+// inputGenerator makes random values of one type for forAll, integers between
+// min and max, or strings and arrays with between min and max elements.
+type inputGenerator struct {
+	kind    string
+	min     int64
+	max     int64
+	element *inputGenerator
+}
+
+// forAll checks that property holds for inputs of the types generators map
+// the property's parameters to, in the order of the hash. A property fails by
+// returning false or an error, as assert does, forAll then looks for the
+// smallest inputs it still fails for and fails with them.
+func forAll(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("forAll", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := hashArgument("forAll", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := hash.OrderedPairs()
+	generators := make([]*inputGenerator, len(pairs))
+	for i, pair := range pairs {
+		generators[i], err = newInputGenerator(pair.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "forAll: generator %s", pair.Key.Inspect())
+		}
+	}
+
+	for run := 0; run < propertyRuns; run++ {
+		inputs := make([]Object, len(generators))
+		for i, generator := range generators {
+			inputs[i] = generator.generate(runtime.Rand())
+		}
+
+		reason, failed, err := checkProperty(runtime, args[1], inputs)
+		if err != nil {
+			return nil, err
+		}
+		if !failed {
+			continue
+		}
+
+		inputs, reason, err = shrinkInputs(runtime, args[1], generators, inputs, reason)
+		if err != nil {
+			return nil, err
+		}
+
+		counterexample := NewHash(len(pairs))
+		for i, pair := range pairs {
+			counterexample.Set(pair.Key, inputs[i])
+		}
+		if reason == "" {
+			return nil, errors.Errorf("forAll: property failed for %s", counterexample.Inspect())
+		}
+
+		return nil, errors.Errorf("forAll: property failed for %s: %s", counterexample.Inspect(), reason)
+	}
+
+	return &NullObject, nil
+}
+
+// checkProperty calls property with copies of inputs, so the property cannot
+// change them. A property failing with an error gives the error's message as
+// the reason, errors that stop the whole program are returned instead.
+func checkProperty(runtime Runtime, property Object, inputs []Object) (string, bool, error) {
+	copies := make([]Object, len(inputs))
+	for i, input := range inputs {
+		copies[i] = DeepCopy(input)
+	}
+
+	result, err := runtime.Call(property, copies...)
+	if err != nil {
+		if stopsProgram(runtime, err) {
+			return "", false, err
+		}
+
+		return failureReason(err), true, nil
+	}
+
+	boolean, ok := result.(*Boolean)
+
+	return "", ok && !boolean.Value, nil
+}
+
+// shrinkInputs replaces inputs one at a time with smaller values the property
+// still fails for, until none of them can be made smaller or it tried
+// propertyShrinks values.
+func shrinkInputs(runtime Runtime, property Object, generators []*inputGenerator, inputs []Object, reason string) ([]Object, string, error) {
+	shrinks := 0
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for i, generator := range generators {
+			for _, candidate := range generator.shrink(inputs[i]) {
+				if shrinks == propertyShrinks {
+					return inputs, reason, nil
+				}
+				shrinks++
+
+				smaller := append([]Object{}, inputs...)
+				smaller[i] = candidate
+
+				candidateReason, failed, err := checkProperty(runtime, property, smaller)
+				if err != nil {
+					return nil, "", err
+				}
+				if failed {
+					inputs, reason, shrunk = smaller, candidateReason, true
+					break
+				}
+			}
+		}
+	}
+
+	return inputs, reason, nil
+}
+
+// stopsProgram tells errors that end the program apart from a property
+// failing.
+func stopsProgram(runtime Runtime, err error) bool {
+	if runtime.Context().Err() != nil {
+		return true
+	}
+
+	switch cause := errors.Cause(err); cause.(type) {
+	case *ExitError, *StepLimitError, *PermissionError:
+		return true
+	default:
+		return cause == context.Canceled || cause == context.DeadlineExceeded
+	}
+}
+
+// failureReason is the message of err without the position some engines
+// append to it, forAll reports where it was called from instead.
+func failureReason(err error) string {
+	located, ok := err.(interface {
+		Span() (lexer.Position, lexer.Position)
+	})
+	if !ok {
+		return err.Error()
+	}
+
+	start, _ := located.Span()
+
+	return strings.TrimSuffix(err.Error(), " at "+start.String())
+}
+
+// newInputGenerator reads a generator from the name of a type, "integer",
+// "string", "boolean" or "array", or from a hash with the name under "type",
+// bounds under "min" and "max", and for arrays the generator of their elements
+// under "of".
+func newInputGenerator(spec Object) (*inputGenerator, error) {
+	var hash *Hash
+	switch spec := spec.(type) {
+	case *String:
+		hash = NewHash(1)
+		hash.Set(&String{Value: "type"}, spec)
+	case *Hash:
+		hash = spec
+	default:
+		return nil, errors.Errorf("must be %s or %s, got %s", StringType, HashType, spec.Type())
+	}
+
+	kind, err := generatorField(hash, "type", StringType)
+	if err != nil {
+		return nil, err
+	}
+	if kind == nil {
+		return nil, errors.New("type is missing")
+	}
+
+	generator := &inputGenerator{kind: kind.(*String).Value, max: 10}
+	switch generator.kind {
+	case "integer":
+		generator.min, generator.max = -1000, 1000
+	case "string", "boolean":
+	case "array":
+		of, ok := hash.Pairs[(&String{Value: "of"}).GetHashKey()]
+		if !ok {
+			return nil, errors.New("array elements are missing under of")
+		}
+
+		generator.element, err = newInputGenerator(of.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "of")
+		}
+	default:
+		return nil, errors.Errorf("unknown type %s", generator.kind)
+	}
+
+	for _, bound := range []struct {
+		name  string
+		value *int64
+	}{{"min", &generator.min}, {"max", &generator.max}} {
+		value, err := generatorField(hash, bound.name, IntegerType)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			*bound.value = value.(*Integer).Value
+		}
+	}
+
+	if generator.min > generator.max {
+		return nil, errors.Errorf("min %d is greater than max %d", generator.min, generator.max)
+	}
+	if generator.kind != "integer" && generator.min < 0 {
+		return nil, errors.Errorf("min must not be negative, got %d", generator.min)
+	}
+
+	return generator, nil
+}
+
+// generatorField returns the value of field in a generator hash, nil when it
+// is not set.
+func generatorField(hash *Hash, field string, fieldType ObjectType) (Object, error) {
+	pair, ok := hash.Pairs[(&String{Value: field}).GetHashKey()]
+	if !ok {
+		return nil, nil
+	}
+	if pair.Value.Type() != fieldType {
+		return nil, errors.Errorf("%s must be %s, got %s", field, fieldType, pair.Value.Type())
+	}
+
+	return pair.Value, nil
+}
+
+func (generator *inputGenerator) generate(random *rand.Rand) Object {
+	switch generator.kind {
+	case "integer":
+		return &Integer{Value: randomBetween(random, generator.min, generator.max)}
+	case "boolean":
+		return nativeBoolToBoolean(random.Intn(2) == 1)
+	case "string":
+		letters := make([]rune, randomBetween(random, generator.min, generator.max))
+		for i := range letters {
+			letters[i] = 'a' + rune(random.Intn(26))
+		}
+
+		return &String{Value: string(letters)}
+	}
+
+	elements := make([]Object, randomBetween(random, generator.min, generator.max))
+	for i := range elements {
+		elements[i] = generator.element.generate(random)
+	}
+
+	return &Array{Elements: elements}
+}
+
+// shrink returns values smaller than value the generator could have made,
+// the smallest first.
+func (generator *inputGenerator) shrink(value Object) []Object {
+	switch generator.kind {
+	case "integer":
+		return generator.shrinkInteger(value.(*Integer).Value)
+	case "boolean":
+		if value.(*Boolean).Value {
+			return []Object{&False}
+		}
+
+		return nil
+	case "string":
+		runes := []rune(value.(*String).Value)
+		var candidates []Object
+		for _, kept := range shrinkLength(len(runes), generator.min) {
+			candidates = append(candidates, &String{Value: string(pickRunes(runes, kept))})
+		}
+
+		return candidates
+	}
+
+	elements := value.(*Array).Elements
+	var candidates []Object
+	for _, kept := range shrinkLength(len(elements), generator.min) {
+		smaller := make([]Object, len(kept))
+		for i, index := range kept {
+			smaller[i] = elements[index]
+		}
+		candidates = append(candidates, &Array{Elements: smaller})
+	}
+
+	for i, element := range elements {
+		for _, candidate := range generator.element.shrink(element) {
+			smaller := append([]Object{}, elements...)
+			smaller[i] = candidate
+			candidates = append(candidates, &Array{Elements: smaller})
+		}
+	}
+
+	return candidates
+}
+
+// shrinkInteger moves value towards zero, or the bound closest to it.
+func (generator *inputGenerator) shrinkInteger(value int64) []Object {
+	target := int64(0)
+	if target < generator.min {
+		target = generator.min
+	} else if target > generator.max {
+		target = generator.max
+	}
+	if value == target {
+		return nil
+	}
+
+	candidates := []Object{&Integer{Value: target}}
+	if half := value - (value-target)/2; half != value && half != target {
+		candidates = append(candidates, &Integer{Value: half})
+	}
+	if value > target && value-1 != target {
+		candidates = append(candidates, &Integer{Value: value - 1})
+	} else if value < target && value+1 != target {
+		candidates = append(candidates, &Integer{Value: value + 1})
+	}
+
+	return candidates
+}
+
+// shrinkLength returns the indexes of length elements to keep when removing
+// all of them, either half or one of them, leaving at least min.
+func shrinkLength(length int, min int64) [][]int {
+	var candidates [][]int
+	remove := func(from, to int) {
+		if int64(length-(to-from)) < min || to == from {
+			return
+		}
+
+		kept := make([]int, 0, length-(to-from))
+		for i := 0; i < length; i++ {
+			if i < from || i >= to {
+				kept = append(kept, i)
+			}
+		}
+		candidates = append(candidates, kept)
+	}
+
+	remove(0, length)
+	if length > 2 {
+		remove(0, length/2)
+		remove(length/2, length)
+	}
+	for i := 0; i < length; i++ {
+		remove(i, i+1)
+	}
+
+	return candidates
+}
+
+func pickRunes(runes []rune, indexes []int) []rune {
+	picked := make([]rune, len(indexes))
+	for i, index := range indexes {
+		picked[i] = runes[index]
+	}
+
+	return picked
+}
@@ -0,0 +1,69 @@
+package object
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// random returns a non-negative integer spread over the whole int64 range.
+func random(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("rand", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integer{Value: runtime.Rand().Int63()}, nil
+}
+
+// randInt returns an integer between min and max, both inclusive.
+func randInt(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("randInt", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	min, err := integerArgument("randInt", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	max, err := integerArgument("randInt", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if min > max {
+		return nil, errors.Errorf("randInt: min %d is greater than max %d", min, max)
+	}
+
+	return &Integer{Value: randomBetween(runtime.Rand(), min, max)}, nil
+}
+
+// randomBetween returns an integer between min and max, both inclusive, min
+// not being greater than max.
+func randomBetween(random *rand.Rand, min, max int64) int64 {
+	span := uint64(max) - uint64(min)
+	if span >= math.MaxInt64 {
+		return int64(uint64(min) + random.Uint64()%(span+1))
+	}
+
+	return min + random.Int63n(int64(span)+1)
+}
+
+func seed(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("seed", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := integerArgument("seed", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime.Rand().Seed(value)
+
+	return &NullObject, nil
+}
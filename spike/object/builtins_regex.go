@@ -0,0 +1,115 @@
+package object
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+func regex(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("regex", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexArgument("regex", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Regex{Pattern: pattern}, nil
+}
+
+func regexMatch(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("regexMatch", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexArgument("regexMatch", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := stringArgument("regexMatch", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolean(pattern.MatchString(str)), nil
+}
+
+// regexFind returns the leftmost match followed by its capture groups, or
+// null when the pattern does not match.
+func regexFind(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("regexFind", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexArgument("regexFind", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := stringArgument("regexFind", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	match := pattern.FindStringSubmatch(str)
+	if match == nil {
+		return &NullObject, nil
+	}
+
+	elements := make([]Object, len(match))
+	for i, group := range match {
+		elements[i] = &String{Value: group}
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+// regexReplace replaces every match, expanding $1 style references to
+// capture groups in the replacement.
+func regexReplace(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("regexReplace", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexArgument("regexReplace", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := stringArgument("regexReplace", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := stringArgument("regexReplace", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: pattern.ReplaceAllString(str, replacement)}, nil
+}
+
+// regexArgument accepts a regex object or a pattern string.
+func regexArgument(name string, args []Object, position int) (*regexp.Regexp, error) {
+	switch argument := args[position].(type) {
+	case *Regex:
+		return argument.Pattern, nil
+
+	case *String:
+		compiled, err := CompileRegex(argument.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+
+		return compiled, nil
+	}
+
+	return nil, errors.Errorf("%s: argument %d must be %s or %s, got %s", name, position+1, RegexType, StringType, args[position].Type())
+}
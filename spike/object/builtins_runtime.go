@@ -0,0 +1,69 @@
+package object
+
+import "github.com/pkg/errors"
+
+func callDepth(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("callDepth", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integer{Value: int64(runtime.CallDepth())}, nil
+}
+
+func stackTrace(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("stackTrace", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := runtime.StackTrace()
+	elements := make([]Object, len(trace))
+	for i, name := range trace {
+		elements[i] = &String{Value: name}
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+// arguments returns the command line arguments the script was run with.
+func arguments(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("args", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	values := runtime.Args()
+	elements := make([]Object, len(values))
+	for i, value := range values {
+		elements[i] = &String{Value: value}
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+// doc returns the docstring of a function, the string literal its body starts
+// with, or null when it has none.
+func doc(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("doc", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var docstring string
+	switch function := args[0].(type) {
+	case *Closure:
+		docstring = function.Function.Doc
+	case *Function:
+		docstring = function.Doc
+	case *BuiltinFunction:
+	default:
+		return nil, errors.Errorf("doc: argument 1 must be function, got %s", args[0].Type())
+	}
+
+	if docstring == "" {
+		return &NullObject, nil
+	}
+
+	return &String{Value: docstring}, nil
+}
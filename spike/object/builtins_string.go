@@ -0,0 +1,202 @@
+package object
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+func normalizeNFC(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("normalizeNFC", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := stringArgument("normalizeNFC", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: norm.NFC.String(str)}, nil
+}
+
+func caseFold(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("caseFold", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := stringArgument("caseFold", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: cases.Fold().String(str)}, nil
+}
+
+func equalsIgnoreCase(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("equalsIgnoreCase", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := stringArgument("equalsIgnoreCase", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := stringArgument("equalsIgnoreCase", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolean(caselessKey(left) == caselessKey(right)), nil
+}
+
+// caselessKey folds case on the composed form of str, so strings that differ
+// only in case or in how accents are encoded compare equal.
+func caselessKey(str string) string {
+	return norm.NFC.String(cases.Fold().String(norm.NFC.String(str)))
+}
+
+func split(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("split", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strs[0], strs[1])
+	elements := make([]Object, len(parts))
+	for i, part := range parts {
+		elements[i] = &String{Value: part}
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+func join(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("join", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := arrayArgument("join", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	separator, err := stringArgument("join", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, len(elements))
+	for i, element := range elements {
+		str, ok := element.(*String)
+		if !ok {
+			return nil, errors.Errorf("join: element %d must be %s, got %s", i, StringType, element.Type())
+		}
+		parts[i] = str.Value
+	}
+
+	return &String{Value: strings.Join(parts, separator)}, nil
+}
+
+func trim(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("trim", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: strings.TrimSpace(strs[0])}, nil
+}
+
+func replace(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("replace", args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: strings.Replace(strs[0], strs[1], strs[2], -1)}, nil
+}
+
+func upper(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("upper", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: strings.ToUpper(strs[0])}, nil
+}
+
+func lower(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("lower", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &String{Value: strings.ToLower(strs[0])}, nil
+}
+
+func contains(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("contains", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolean(strings.Contains(strs[0], strs[1])), nil
+}
+
+func startsWith(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("startsWith", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolean(strings.HasPrefix(strs[0], strs[1])), nil
+}
+
+func endsWith(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("endsWith", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return nativeBoolToBoolean(strings.HasSuffix(strs[0], strs[1])), nil
+}
+
+// indexOf returns the character index of the first occurrence of the
+// substring, or -1 when there is none.
+func indexOf(_ Runtime, args ...Object) (Object, error) {
+	strs, err := stringArguments("indexOf", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	index := strings.Index(strs[0], strs[1])
+	if index > 0 {
+		index = utf8.RuneCountInString(strs[0][:index])
+	}
+
+	return &Integer{Value: int64(index)}, nil
+}
+
+func stringArguments(name string, args []Object, expected int) ([]string, error) {
+	err := checkArgumentsCount(name, args, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(args))
+	for i := range args {
+		strs[i], err = stringArgument(name, args, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return strs, nil
+}
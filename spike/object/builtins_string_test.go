@@ -0,0 +1,149 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StringBuiltins(t *testing.T) {
+	testCases := []struct {
+		builtin        string
+		args           []Object
+		expectedResult Object
+	}{
+		{
+			builtin:        "normalizeNFC",
+			args:           []Object{&String{Value: "é"}},
+			expectedResult: &String{Value: "é"},
+		},
+		{
+			builtin:        "caseFold",
+			args:           []Object{&String{Value: "Straße"}},
+			expectedResult: &String{Value: "strasse"},
+		},
+		{
+			builtin:        "equalsIgnoreCase",
+			args:           []Object{&String{Value: "STRASSE"}, &String{Value: "straße"}},
+			expectedResult: &True,
+		},
+		{
+			builtin:        "equalsIgnoreCase",
+			args:           []Object{&String{Value: "Café"}, &String{Value: "CAFÉ"}},
+			expectedResult: &True,
+		},
+		{
+			builtin:        "equalsIgnoreCase",
+			args:           []Object{&String{Value: "cafe"}, &String{Value: "café"}},
+			expectedResult: &False,
+		},
+		{
+			builtin: "split",
+			args:    []Object{&String{Value: "a,b,"}, &String{Value: ","}},
+			expectedResult: &Array{Elements: []Object{
+				&String{Value: "a"}, &String{Value: "b"}, &String{Value: ""},
+			}},
+		},
+		{
+			builtin:        "join",
+			args:           []Object{&Array{Elements: []Object{&String{Value: "a"}, &String{Value: "b"}}}, &String{Value: ", "}},
+			expectedResult: &String{Value: "a, b"},
+		},
+		{
+			builtin:        "trim",
+			args:           []Object{&String{Value: " \t spike\n"}},
+			expectedResult: &String{Value: "spike"},
+		},
+		{
+			builtin:        "replace",
+			args:           []Object{&String{Value: "a-b-c"}, &String{Value: "-"}, &String{Value: "+"}},
+			expectedResult: &String{Value: "a+b+c"},
+		},
+		{
+			builtin:        "upper",
+			args:           []Object{&String{Value: "Spike"}},
+			expectedResult: &String{Value: "SPIKE"},
+		},
+		{
+			builtin:        "lower",
+			args:           []Object{&String{Value: "Spike"}},
+			expectedResult: &String{Value: "spike"},
+		},
+		{
+			builtin:        "contains",
+			args:           []Object{&String{Value: "spike"}, &String{Value: "ik"}},
+			expectedResult: &True,
+		},
+		{
+			builtin:        "startsWith",
+			args:           []Object{&String{Value: "spike"}, &String{Value: "ik"}},
+			expectedResult: &False,
+		},
+		{
+			builtin:        "endsWith",
+			args:           []Object{&String{Value: "spike"}, &String{Value: "ke"}},
+			expectedResult: &True,
+		},
+		{
+			builtin:        "indexOf",
+			args:           []Object{&String{Value: "spike"}, &String{Value: "x"}},
+			expectedResult: &Integer{Value: -1},
+		},
+		{
+			builtin:        "indexOf",
+			args:           []Object{&String{Value: "żółw"}, &String{Value: "w"}},
+			expectedResult: &Integer{Value: 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.builtin, func(t *testing.T) {
+			result, err := GetBuiltinByName(testCase.builtin).Function(nil, testCase.args...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+func Test_StringBuiltins_invalidArguments(t *testing.T) {
+	testCases := []struct {
+		builtin       string
+		args          []Object
+		expectedError string
+	}{
+		{
+			builtin:       "caseFold",
+			args:          []Object{},
+			expectedError: "caseFold: expected 1 argument, got 0",
+		},
+		{
+			builtin:       "normalizeNFC",
+			args:          []Object{&Integer{Value: 1}},
+			expectedError: "normalizeNFC: argument 1 must be string, got integer",
+		},
+		{
+			builtin:       "equalsIgnoreCase",
+			args:          []Object{&String{Value: "a"}},
+			expectedError: "equalsIgnoreCase: expected 2 arguments, got 1",
+		},
+		{
+			builtin:       "replace",
+			args:          []Object{&String{Value: "a"}, &String{Value: "b"}, &Integer{Value: 1}},
+			expectedError: "replace: argument 3 must be string, got integer",
+		},
+		{
+			builtin:       "join",
+			args:          []Object{&Array{Elements: []Object{&String{Value: "a"}, &Integer{Value: 1}}}, &String{Value: ""}},
+			expectedError: "join: element 1 must be string, got integer",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			_, err := GetBuiltinByName(testCase.builtin).Function(nil, testCase.args...)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package object
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var clockStart = time.Now()
+
+// now returns the wall clock time in milliseconds since the Unix epoch.
+func now(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("now", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integer{Value: time.Now().UnixNano() / int64(time.Millisecond)}, nil
+}
+
+// clock returns monotonic nanoseconds, only meaningful as a difference
+// between two calls.
+func clock(_ Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("clock", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Integer{Value: int64(time.Since(clockStart))}, nil
+}
+
+// sleep pauses for the given number of milliseconds, returning early with an
+// error when the run is cancelled.
+func sleep(runtime Runtime, args ...Object) (Object, error) {
+	err := checkArgumentsCount("sleep", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	milliseconds, err := integerArgument("sleep", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if milliseconds < 0 {
+		return nil, errors.Errorf("sleep: duration must not be negative, got %d", milliseconds)
+	}
+
+	timer := time.NewTimer(time.Duration(milliseconds) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return &NullObject, nil
+	case <-runtime.Context().Done():
+		return nil, runtime.Context().Err()
+	}
+}
@@ -0,0 +1,48 @@
+package object
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+type Bytes struct {
+	Value []byte
+}
+
+func (b *Bytes) Type() ObjectType {
+	return BytesType
+}
+
+func (b *Bytes) Inspect() string {
+	out := strings.Builder{}
+
+	out.WriteString("bytes[")
+	for i, value := range b.Value {
+		out.WriteString(strconv.Itoa(int(value)))
+		if i < len(b.Value)-1 {
+			out.WriteString(", ")
+		}
+	}
+	out.WriteString("]")
+
+	return out.String()
+}
+
+func (b *Bytes) Equal(other Object) bool {
+	otherBytes, ok := other.(*Bytes)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(b.Value, otherBytes.Value)
+}
+
+func (b *Bytes) Iterate() Iterator {
+	elements := make([]Object, len(b.Value))
+	for i, value := range b.Value {
+		elements[i] = &Integer{Value: int64(value)}
+	}
+
+	return &arrayIterator{elements: elements}
+}
@@ -9,6 +9,19 @@ type CompiledFunction struct {
 	Instructions    code.Instructions
 	LocalsCount     int
 	ParametersCount int
+	Name            string
+	Generator       bool
+	Doc             string
+	SourceMap       code.SourceMap
+	// LocalNames names the locals by index, for debuggers.
+	LocalNames []string
+	// Line is where the function is defined, 0 for programs built before it
+	// was recorded.
+	Line int
+	// Registers is the function's code when compiled for the register
+	// machine, which gives every call RegistersCount registers.
+	Registers      code.RegisterInstructions
+	RegistersCount int
 }
 
 func (function *CompiledFunction) Type() ObjectType {
@@ -0,0 +1,316 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// FromGo converts a Go value for use in scripts. Booleans, strings, integers
+// of any size, whole floats, byte slices, *big.Int, slices, arrays, maps and
+// structs are converted recursively; nil and nil pointers become null and
+// objects are kept as they are. Scripts have no floats yet, so whole floats
+// become integers and floats with a fractional part, or out of the range of
+// int64, fail to convert. Map entries are added in the order of their
+// sorted keys, struct fields in the order they are declared, named by their
+// `spike` tag. Values that refer to themselves through pointers, maps or
+// slices fail to convert.
+func FromGo(value interface{}) (Object, error) {
+	if object, ok := value.(Object); ok {
+		return object, nil
+	}
+
+	return fromGo(reflect.ValueOf(value), visiting{})
+}
+
+// visit identifies a pointer, map or slice by what it points to. Slices of
+// the same array differ by their length.
+type visit struct {
+	valueType reflect.Type
+	pointer   uintptr
+	length    int
+}
+
+// visiting holds the pointers, maps and slices being converted, the ones
+// fromGo is inside of.
+type visiting map[visit]bool
+
+// enter fails when value is being converted already, converting a value that
+// refers to itself would never end. Otherwise leave has to be called once
+// value is converted.
+func (visiting visiting) enter(value reflect.Value) error {
+	key := visitOf(value)
+	if visiting[key] {
+		return errors.Errorf("unable to convert %s to a spike value: it refers to itself", value.Type())
+	}
+	visiting[key] = true
+
+	return nil
+}
+
+func (visiting visiting) leave(value reflect.Value) {
+	delete(visiting, visitOf(value))
+}
+
+func visitOf(value reflect.Value) visit {
+	key := visit{valueType: value.Type(), pointer: value.Pointer()}
+	if value.Kind() == reflect.Slice {
+		key.length = value.Len()
+	}
+
+	return key
+}
+
+func fromGo(value reflect.Value, visiting visiting) (Object, error) {
+	if !value.IsValid() {
+		return &NullObject, nil
+	}
+	if object, ok := value.Interface().(Object); ok && value.Kind() != reflect.Interface {
+		return object, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return nativeBoolToBoolean(value.Bool()), nil
+
+	case reflect.String:
+		return &String{Value: value.String()}, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: value.Int()}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return &BigInt{Value: new(big.Int).SetUint64(value.Uint())}, nil
+		}
+		return &Integer{Value: int64(value.Uint())}, nil
+
+	case reflect.Float32, reflect.Float64:
+		float := value.Float()
+		if float != math.Trunc(float) || float < math.MinInt64 || float >= math.MaxInt64 {
+			return nil, errors.Errorf("float values are not supported yet: %v", float)
+		}
+		return &Integer{Value: int64(float)}, nil
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return &NullObject, nil
+		}
+		return fromGo(value.Elem(), visiting)
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return &NullObject, nil
+		}
+		if value.Type().Elem() == bigIntType {
+			return &BigInt{Value: new(big.Int).Set(value.Interface().(*big.Int))}, nil
+		}
+
+		err := visiting.enter(value)
+		if err != nil {
+			return nil, err
+		}
+		defer visiting.leave(value)
+
+		return fromGo(value.Elem(), visiting)
+
+	case reflect.Struct:
+		if value.Type() == bigIntType {
+			integer := value.Interface().(big.Int)
+			return &BigInt{Value: new(big.Int).Set(&integer)}, nil
+		}
+		return fromStruct(value, visiting)
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return &Bytes{Value: append([]byte{}, value.Bytes()...)}, nil
+		}
+		if value.Kind() == reflect.Slice && !value.IsNil() {
+			err := visiting.enter(value)
+			if err != nil {
+				return nil, err
+			}
+			defer visiting.leave(value)
+		}
+
+		array := &Array{Elements: make([]Object, value.Len())}
+		for i := range array.Elements {
+			element, err := fromGo(value.Index(i), visiting)
+			if err != nil {
+				return nil, err
+			}
+			array.Elements[i] = element
+		}
+
+		return array, nil
+
+	case reflect.Map:
+		if !value.IsNil() {
+			err := visiting.enter(value)
+			if err != nil {
+				return nil, err
+			}
+			defer visiting.leave(value)
+		}
+
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i], keys[j])
+		})
+
+		hash := NewHash(len(keys))
+		for _, key := range keys {
+			hashKey, err := fromGo(key, visiting)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := HashKeyOf(hashKey); err != nil {
+				return nil, err
+			}
+
+			element, err := fromGo(value.MapIndex(key), visiting)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(hashKey, element)
+		}
+
+		return hash, nil
+	}
+
+	return nil, errors.Errorf("unable to convert %s to a spike value", value.Type())
+}
+
+// lessKey orders map keys, numbers by value and anything else by how it is
+// printed.
+func lessKey(left reflect.Value, right reflect.Value) bool {
+	for left.Kind() == reflect.Interface && !left.IsNil() {
+		left = left.Elem()
+	}
+	for right.Kind() == reflect.Interface && !right.IsNil() {
+		right = right.Elem()
+	}
+
+	switch {
+	case isInt(left) && isInt(right):
+		return left.Int() < right.Int()
+	case isUint(left) && isUint(right):
+		return left.Uint() < right.Uint()
+	}
+
+	return fmt.Sprint(left.Interface()) < fmt.Sprint(right.Interface())
+}
+
+func isInt(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+func isUint(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// ToGo converts a script value to Go: integers to int64, bigints to
+// *big.Int, strings, booleans, bytes to []byte, null to nil, arrays and
+// tuples to []interface{} and hashes to map[string]interface{} when all their
+// keys are strings, map[interface{}]interface{} otherwise. Enum members
+// become their names. Anything else, like functions, is returned as is.
+func ToGo(value Object) interface{} {
+	return toGo(value, map[Object]interface{}{})
+}
+
+func toGo(value Object, converted map[Object]interface{}) interface{} {
+	switch value := value.(type) {
+	case *Integer:
+		return value.Value
+
+	case *BigInt:
+		return new(big.Int).Set(value.Value)
+
+	case *String:
+		return value.Value
+
+	case *Boolean:
+		return value.Value
+
+	case *Null:
+		return nil
+
+	case *Bytes:
+		return append([]byte{}, value.Value...)
+
+	case *EnumMember:
+		return value.Name
+
+	case *Tuple:
+		elements := make([]interface{}, len(value.Elements))
+		for i, element := range value.Elements {
+			elements[i] = toGo(element, converted)
+		}
+
+		return elements
+
+	case *Array:
+		if result, ok := converted[value]; ok {
+			return result
+		}
+
+		elements := make([]interface{}, len(value.Elements))
+		converted[value] = elements
+		for i, element := range value.Elements {
+			elements[i] = toGo(element, converted)
+		}
+
+		return elements
+
+	case *Hash:
+		if result, ok := converted[value]; ok {
+			return result
+		}
+
+		if stringKeys(value) {
+			result := make(map[string]interface{}, len(value.Pairs))
+			converted[value] = result
+			for _, pair := range value.Pairs {
+				result[pair.Key.(*String).Value] = toGo(pair.Value, converted)
+			}
+
+			return result
+		}
+
+		result := make(map[interface{}]interface{}, len(value.Pairs))
+		converted[value] = result
+		for _, pair := range value.Pairs {
+			result[toGo(pair.Key, converted)] = toGo(pair.Value, converted)
+		}
+
+		return result
+	}
+
+	return value
+}
+
+func stringKeys(hash *Hash) bool {
+	for _, pair := range hash.Pairs {
+		if _, ok := pair.Key.(*String); !ok {
+			return false
+		}
+	}
+
+	return true
+}
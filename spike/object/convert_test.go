@@ -0,0 +1,256 @@
+package object
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FromGo(t *testing.T) {
+	hash := NewHash(3)
+	hash.Set(&Integer{Value: -1}, &Array{Elements: []Object{&True}})
+	hash.Set(&Integer{Value: 2}, &String{Value: "b"})
+	hash.Set(&Integer{Value: 10}, &NullObject)
+
+	var nilPointer *int
+	seven := 7
+
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected Object
+	}{
+		{name: "nil", value: nil, expected: &NullObject},
+		{name: "nil pointer", value: nilPointer, expected: &NullObject},
+		{name: "pointer", value: &seven, expected: &Integer{Value: 7}},
+		{name: "bool", value: true, expected: &True},
+		{name: "string", value: "spike", expected: &String{Value: "spike"}},
+		{name: "int8", value: int8(-3), expected: &Integer{Value: -3}},
+		{name: "uint32", value: uint32(3), expected: &Integer{Value: 3}},
+		{name: "large uint64", value: uint64(math.MaxUint64), expected: &BigInt{Value: new(big.Int).SetUint64(math.MaxUint64)}},
+		{name: "whole float", value: 2.0, expected: &Integer{Value: 2}},
+		{name: "big.Int", value: big.NewInt(5), expected: &BigInt{Value: big.NewInt(5)}},
+		{name: "bytes", value: []byte{1, 2}, expected: &Bytes{Value: []byte{1, 2}}},
+		{name: "object", value: &String{Value: "kept"}, expected: &String{Value: "kept"}},
+		{
+			name:  "nested slices",
+			value: []interface{}{1, []string{"a"}, [1]bool{false}, nil},
+			expected: &Array{Elements: []Object{
+				&Integer{Value: 1},
+				&Array{Elements: []Object{&String{Value: "a"}}},
+				&Array{Elements: []Object{&False}},
+				&NullObject,
+			}},
+		},
+		{
+			name:     "map with sorted keys",
+			value:    map[int]interface{}{10: nil, 2: "b", -1: []bool{true}},
+			expected: hash,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result, err := FromGo(testCase.value)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func Test_FromGo_errors(t *testing.T) {
+	testCases := []struct {
+		value         interface{}
+		expectedError string
+	}{
+		{value: 1.5, expectedError: "float values are not supported yet: 1.5"},
+		{value: []interface{}{make(chan int)}, expectedError: "unable to convert chan int to a spike value"},
+		{value: map[[1]int]int{{1}: 1}, expectedError: "unusable as hash key: array"},
+		{value: func() {}, expectedError: "unable to convert func() to a spike value"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			_, err := FromGo(testCase.value)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func Test_ToGo(t *testing.T) {
+	mixed := NewHash(2)
+	mixed.Set(&Integer{Value: 1}, &String{Value: "one"})
+	mixed.Set(&True, &Tuple{Elements: []Object{&Integer{Value: 2}}})
+
+	named := NewHash(1)
+	named.Set(&String{Value: "items"}, &Array{Elements: []Object{&NullObject, &Bytes{Value: []byte{1}}, mixed}})
+	function := &BuiltinFunction{Name: "len"}
+
+	result := ToGo(&Array{Elements: []Object{
+		named,
+		&BigInt{Value: big.NewInt(3)},
+		NewEnum("Color", []string{"Red"}).Members[0],
+		function,
+	}})
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"items": []interface{}{
+				nil,
+				[]byte{1},
+				map[interface{}]interface{}{int64(1): "one", true: []interface{}{int64(2)}},
+			},
+		},
+		big.NewInt(3),
+		"Red",
+		function,
+	}, result)
+}
+
+func Test_ToGo_cycles(t *testing.T) {
+	hash := NewHash(1)
+	hash.Set(&String{Value: "self"}, hash)
+
+	result := ToGo(hash).(map[string]interface{})
+
+	assert.Equal(t, result["self"].(map[string]interface{})["self"], result["self"])
+}
+
+type testNode struct {
+	Next *testNode
+}
+
+func Test_FromGo_cycles(t *testing.T) {
+	node := &testNode{}
+	node.Next = node
+	slice := []interface{}{nil}
+	slice[0] = slice
+	hash := map[string]interface{}{}
+	hash["self"] = hash
+
+	_, err := FromGo(node)
+	assert.EqualError(t, err, "unable to convert *object.testNode to a spike value: it refers to itself")
+	_, err = FromGo(slice)
+	assert.EqualError(t, err, "unable to convert []interface {} to a spike value: it refers to itself")
+	_, err = FromGo(hash)
+	assert.EqualError(t, err, "unable to convert map[string]interface {} to a spike value: it refers to itself")
+
+	shared := &testNode{}
+	result, err := FromGo([]*testNode{shared, shared})
+	assert.NoError(t, err)
+	assert.Equal(t, "[{\"Next\": null}, {\"Next\": null}]", result.Inspect())
+}
+
+func Test_FromGo_ToGo_roundTrip(t *testing.T) {
+	value := map[string]interface{}{"a": []interface{}{int64(1), "b", true, nil}, "c": map[string]interface{}{}}
+
+	object, err := FromGo(value)
+	assert.NoError(t, err)
+
+	assert.Equal(t, value, ToGo(object))
+}
+
+type testAddress struct {
+	City string `spike:"city"`
+	Zip  string `spike:"zip,omitempty"`
+}
+
+type testMeta struct {
+	Version int `spike:"version"`
+}
+
+type testConfig struct {
+	testMeta
+	Name     string            `spike:"name"`
+	Ports    []int             `spike:"ports"`
+	Address  *testAddress      `spike:"address"`
+	Labels   map[string]string `spike:"labels"`
+	Secret   string            `spike:"-"`
+	Untagged bool
+	internal int
+}
+
+func Test_FromGo_struct(t *testing.T) {
+	address := NewHash(1)
+	address.Set(&String{Value: "city"}, &String{Value: "Oslo"})
+	labels := NewHash(1)
+	labels.Set(&String{Value: "env"}, &String{Value: "prod"})
+
+	expected := NewHash(6)
+	expected.Set(&String{Value: "version"}, &Integer{Value: 2})
+	expected.Set(&String{Value: "name"}, &String{Value: "api"})
+	expected.Set(&String{Value: "ports"}, &Array{Elements: []Object{&Integer{Value: 80}, &Integer{Value: 443}}})
+	expected.Set(&String{Value: "address"}, address)
+	expected.Set(&String{Value: "labels"}, labels)
+	expected.Set(&String{Value: "Untagged"}, &True)
+
+	result, err := FromGo(testConfig{
+		testMeta: testMeta{Version: 2},
+		Name:     "api",
+		Ports:    []int{80, 443},
+		Address:  &testAddress{City: "Oslo"},
+		Labels:   map[string]string{"env": "prod"},
+		Secret:   "hidden",
+		Untagged: true,
+		internal: 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func Test_Decode(t *testing.T) {
+	config := testConfig{
+		testMeta: testMeta{Version: 2},
+		Name:     "api",
+		Ports:    []int{80, 443},
+		Address:  &testAddress{City: "Oslo", Zip: "0150"},
+		Labels:   map[string]string{"env": "prod"},
+		Untagged: true,
+	}
+	object, err := FromGo(config)
+	assert.NoError(t, err)
+
+	decoded := testConfig{Secret: "kept"}
+	err = Decode(object, &decoded)
+
+	assert.NoError(t, err)
+	config.Secret = "kept"
+	assert.Equal(t, config, decoded)
+
+	var anything interface{}
+	assert.NoError(t, Decode(&Array{Elements: []Object{&Integer{Value: 1}, &NullObject}}, &anything))
+	assert.Equal(t, []interface{}{int64(1), nil}, anything)
+
+	var pair [2]*big.Int
+	assert.NoError(t, Decode(&Tuple{Elements: []Object{&Integer{Value: 1}, &BigInt{Value: big.NewInt(2)}}}, &pair))
+	assert.Equal(t, [2]*big.Int{big.NewInt(1), big.NewInt(2)}, pair)
+}
+
+func Test_Decode_errors(t *testing.T) {
+	ports := NewHash(1)
+	ports.Set(&String{Value: "ports"}, &Array{Elements: []Object{&Integer{Value: 80}, &String{Value: "https"}}})
+
+	testCases := []struct {
+		value         Object
+		target        interface{}
+		expectedError string
+	}{
+		{value: &Integer{Value: 1}, target: testConfig{}, expectedError: "unable to decode into object.testConfig, it must be a non-nil pointer"},
+		{value: ports, target: &testConfig{}, expectedError: "field ports: element 1: unable to decode string into int"},
+		{value: &Integer{Value: 300}, target: new(int8), expectedError: "300 does not fit in int8"},
+		{value: &Integer{Value: -1}, target: new(uint), expectedError: "-1 does not fit in uint"},
+		{value: &Array{}, target: new([1]int), expectedError: "unable to decode 0 elements into [1]int"},
+		{value: &String{Value: "a"}, target: new(bool), expectedError: "unable to decode string into bool"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			assert.EqualError(t, Decode(testCase.value, testCase.target), testCase.expectedError)
+		})
+	}
+}
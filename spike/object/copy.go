@@ -0,0 +1,40 @@
+package object
+
+// DeepCopy copies arrays and hashes recursively. Values that are reachable
+// more than once, including through cycles, are copied once and stay shared
+// the same way in the copy. Other objects are immutable and returned as is.
+func DeepCopy(value Object) Object {
+	return deepCopy(value, map[Object]Object{})
+}
+
+func deepCopy(value Object, copies map[Object]Object) Object {
+	switch value := value.(type) {
+	case *Array:
+		if copied, ok := copies[value]; ok {
+			return copied
+		}
+
+		array := &Array{Elements: make([]Object, len(value.Elements))}
+		copies[value] = array
+		for i, element := range value.Elements {
+			array.Elements[i] = deepCopy(element, copies)
+		}
+
+		return array
+
+	case *Hash:
+		if copied, ok := copies[value]; ok {
+			return copied
+		}
+
+		hash := NewHash(len(value.Pairs))
+		copies[value] = hash
+		for _, pair := range value.OrderedPairs() {
+			hash.Set(pair.Key, deepCopy(pair.Value, copies))
+		}
+
+		return hash
+	}
+
+	return value
+}
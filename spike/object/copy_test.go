@@ -0,0 +1,32 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DeepCopy(t *testing.T) {
+	shared := &Array{Elements: []Object{&Integer{Value: 1}}}
+	hash := &Hash{Pairs: map[HashKey]HashPair{}}
+	setStringKey(hash, "shared", shared)
+	original := &Array{Elements: []Object{shared, hash, &String{Value: "s"}}}
+
+	copied := DeepCopy(original).(*Array)
+
+	assert.Equal(t, original, copied)
+	assert.False(t, copied == original)
+	assert.False(t, copied.Elements[0] == shared)
+	copiedHash := copied.Elements[1].(*Hash)
+	assert.True(t, copiedHash.Pairs[(&String{Value: "shared"}).GetHashKey()].Value == copied.Elements[0])
+}
+
+func Test_DeepCopy_cycles(t *testing.T) {
+	cyclic := &Array{}
+	cyclic.Elements = []Object{&Integer{Value: 1}, cyclic}
+
+	copied := DeepCopy(cyclic).(*Array)
+
+	assert.False(t, copied == cyclic)
+	assert.True(t, copied.Elements[1] == copied)
+}
@@ -0,0 +1,46 @@
+package object
+
+// Index assignment can put an array or hash inside itself. Inspect and Equal
+// walk arrays, hashes and tuples with the containers they are already in, so
+// they stop where a value contains itself instead of recursing forever.
+
+// inspecting holds the containers being printed. One reached again inside
+// itself is printed as [...], {...} or (...).
+type inspecting map[Object]bool
+
+func inspectNested(value Object, seen inspecting) string {
+	switch value := value.(type) {
+	case *Array:
+		return value.inspect(seen)
+	case *Hash:
+		return value.inspect(seen)
+	case *Tuple:
+		return value.inspect(seen)
+	}
+
+	return value.Inspect()
+}
+
+// comparison is a pair of containers being compared.
+type comparison struct {
+	left  Object
+	right Object
+}
+
+// comparing holds the pairs of containers being compared. A pair reached
+// again inside itself is taken as equal, whether they are is decided by the
+// rest of the elements.
+type comparing map[comparison]bool
+
+func equalNested(left, right Object, seen comparing) bool {
+	switch left := left.(type) {
+	case *Array:
+		return left.equal(right, seen)
+	case *Hash:
+		return left.equal(right, seen)
+	case *Tuple:
+		return left.equal(right, seen)
+	}
+
+	return left.Equal(right)
+}
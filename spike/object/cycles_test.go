@@ -0,0 +1,41 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Inspect_cycles(t *testing.T) {
+	array := &Array{}
+	hash := NewHash(1)
+	hash.Set(&String{Value: "self"}, hash)
+	hash.Set(&String{Value: "array"}, array)
+	tuple := &Tuple{Elements: []Object{array}}
+	shared := &Array{Elements: []Object{&Integer{Value: 2}}}
+	array.Elements = []Object{&Integer{Value: 1}, array, hash, tuple, shared, shared}
+
+	assert.Equal(t, `[1, [...], {"self": {...}, "array": [...]}, ([...]), [2], [2]]`, array.Inspect())
+	assert.Equal(t, `{"self": {...}, "array": [1, [...], {...}, ([...]), [2], [2]]}`, hash.Inspect())
+	assert.Equal(t, `([1, [...], {"self": {...}, "array": [...]}, (...), [2], [2]])`, tuple.Inspect())
+}
+
+func Test_Equal_cycles(t *testing.T) {
+	left := &Array{}
+	left.Elements = []Object{&Integer{Value: 1}, left}
+	right := &Array{}
+	right.Elements = []Object{&Integer{Value: 1}, right}
+	other := &Array{}
+	other.Elements = []Object{&Integer{Value: 2}, other}
+
+	leftHash := NewHash(1)
+	leftHash.Set(&String{Value: "self"}, leftHash)
+	rightHash := NewHash(1)
+	rightHash.Set(&String{Value: "self"}, rightHash)
+
+	assert.True(t, left.Equal(right))
+	assert.True(t, left.Equal(left))
+	assert.False(t, left.Equal(other))
+	assert.True(t, leftHash.Equal(rightHash))
+	assert.False(t, leftHash.Equal(left))
+}
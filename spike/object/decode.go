@@ -0,0 +1,213 @@
+package object
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+var objectType = reflect.TypeOf((*Object)(nil)).Elem()
+
+// Decode stores value in the Go value target points to, the reverse of
+// FromGo. Hashes fill structs by the same field names FromGo uses, leaving
+// fields without a key as they were, and maps of any key type. Arrays and
+// tuples fill slices and arrays, null sets the zero value and interface{}
+// targets get what ToGo returns.
+func Decode(value Object, target interface{}) error {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Ptr || pointer.IsNil() {
+		return errors.Errorf("unable to decode into %T, it must be a non-nil pointer", target)
+	}
+
+	return decode(value, pointer.Elem())
+}
+
+func decode(value Object, target reflect.Value) error {
+	if target.Type() == objectType {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+	if _, ok := value.(*Null); ok {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if target.NumMethod() == 0 {
+			converted := ToGo(value)
+			if converted != nil {
+				target.Set(reflect.ValueOf(converted))
+			}
+			return nil
+		}
+
+	case reflect.Ptr:
+		if target.Type().Elem() == bigIntType {
+			integer, ok := bigIntOf(value)
+			if !ok {
+				break
+			}
+			target.Set(reflect.ValueOf(integer))
+			return nil
+		}
+
+		element := reflect.New(target.Type().Elem())
+		err := decode(value, element.Elem())
+		if err != nil {
+			return err
+		}
+		target.Set(element)
+		return nil
+
+	case reflect.Bool:
+		if boolean, ok := value.(*Boolean); ok {
+			target.SetBool(boolean.Value)
+			return nil
+		}
+
+	case reflect.String:
+		if str, ok := value.(*String); ok {
+			target.SetString(str.Value)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := value.(*Integer); ok {
+			if target.OverflowInt(integer.Value) {
+				return errors.Errorf("%d does not fit in %s", integer.Value, target.Type())
+			}
+			target.SetInt(integer.Value)
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		integer, ok := bigIntOf(value)
+		if ok {
+			if integer.Sign() < 0 || !integer.IsUint64() || target.OverflowUint(integer.Uint64()) {
+				return errors.Errorf("%s does not fit in %s", integer, target.Type())
+			}
+			target.SetUint(integer.Uint64())
+			return nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if integer, ok := value.(*Integer); ok {
+			target.SetFloat(float64(integer.Value))
+			return nil
+		}
+
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.Uint8 {
+			if bytes, ok := value.(*Bytes); ok {
+				target.SetBytes(append([]byte{}, bytes.Value...))
+				return nil
+			}
+		}
+
+		elements, ok := elementsOf(value)
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			err := decode(element, slice.Index(i))
+			if err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+		target.Set(slice)
+		return nil
+
+	case reflect.Array:
+		elements, ok := elementsOf(value)
+		if !ok {
+			break
+		}
+		if len(elements) != target.Len() {
+			return errors.Errorf("unable to decode %d elements into %s", len(elements), target.Type())
+		}
+		for i, element := range elements {
+			err := decode(element, target.Index(i))
+			if err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		hash, ok := value.(*Hash)
+		if !ok {
+			break
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(hash.Pairs))
+		for _, key := range hash.Keys {
+			pair := hash.Pairs[key]
+			mapKey := reflect.New(target.Type().Key()).Elem()
+			err := decode(pair.Key, mapKey)
+			if err != nil {
+				return errors.Wrapf(err, "key %s", pair.Key.Inspect())
+			}
+			mapValue := reflect.New(target.Type().Elem()).Elem()
+			err = decode(pair.Value, mapValue)
+			if err != nil {
+				return errors.Wrapf(err, "key %s", pair.Key.Inspect())
+			}
+			result.SetMapIndex(mapKey, mapValue)
+		}
+		target.Set(result)
+		return nil
+
+	case reflect.Struct:
+		if target.Type() == bigIntType {
+			integer, ok := bigIntOf(value)
+			if !ok {
+				break
+			}
+			target.Set(reflect.ValueOf(*integer))
+			return nil
+		}
+
+		hash, ok := value.(*Hash)
+		if !ok {
+			break
+		}
+		for _, field := range structFields(target.Type()) {
+			element, err := hash.Get(&String{Value: field.name})
+			if err != nil {
+				continue
+			}
+
+			err = decode(element, target.FieldByIndex(field.index))
+			if err != nil {
+				return errors.Wrapf(err, "field %s", field.name)
+			}
+		}
+		return nil
+	}
+
+	return errors.Errorf("unable to decode %s into %s", value.Type(), target.Type())
+}
+
+func bigIntOf(value Object) (*big.Int, bool) {
+	switch value := value.(type) {
+	case *Integer:
+		return big.NewInt(value.Value), true
+	case *BigInt:
+		return new(big.Int).Set(value.Value), true
+	}
+
+	return nil, false
+}
+
+func elementsOf(value Object) ([]Object, bool) {
+	switch value := value.(type) {
+	case *Array:
+		return value.Elements, true
+	case *Tuple:
+		return value.Elements, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Enum is the value bound by `enum Color { Red, Green, Blue }`. Its members
+// are distinct objects, each equal only to itself.
+type Enum struct {
+	Name    string
+	Members []*EnumMember
+}
+
+type EnumMember struct {
+	Enum    string
+	Name    string
+	Ordinal int
+}
+
+func NewEnum(name string, members []string) *Enum {
+	enum := &Enum{Name: name, Members: make([]*EnumMember, len(members))}
+	for i, member := range members {
+		enum.Members[i] = &EnumMember{Enum: name, Name: member, Ordinal: i}
+	}
+
+	return enum
+}
+
+func (enum *Enum) Type() ObjectType {
+	return EnumType
+}
+
+func (enum *Enum) Inspect() string {
+	members := make([]string, len(enum.Members))
+	for i, member := range enum.Members {
+		members[i] = member.Name
+	}
+
+	return "enum " + enum.Name + " { " + strings.Join(members, ", ") + " }"
+}
+
+func (enum *Enum) Equal(other Object) bool {
+	return enum == other
+}
+
+func (member *EnumMember) Type() ObjectType {
+	return EnumMemberType
+}
+
+func (member *EnumMember) Inspect() string {
+	return member.Enum + "." + member.Name
+}
+
+func (member *EnumMember) Equal(other Object) bool {
+	return member == other
+}
+
+// Member returns the member called name of value, as in `Color.Red` or
+// `db.query`.
+func Member(value Object, name string) (Object, error) {
+	if module, ok := value.(*Module); ok {
+		member, ok := module.Members[name]
+		if !ok {
+			return nil, errors.Errorf("module %s has no member %s", module.Name, name)
+		}
+
+		return member, nil
+	}
+
+	enum, ok := value.(*Enum)
+	if !ok {
+		return nil, errors.Errorf("%s has no members", value.Type())
+	}
+
+	for _, member := range enum.Members {
+		if member.Name == name {
+			return member, nil
+		}
+	}
+
+	return nil, errors.Errorf("%s has no member %s", enum.Name, name)
+}
@@ -23,6 +23,33 @@ func (e Environment) Set(name string, value Object) {
 	e.variables[name] = value
 }
 
+// Assign changes an existing variable, defined either in this environment or
+// in the outermost one, which holds the globals. Variables of enclosing
+// functions cannot be assigned, closures only capture their values.
+func (e Environment) Assign(name string, value Object) error {
+	if _, ok := e.variables[name]; ok {
+		e.variables[name] = value
+		return nil
+	}
+
+	global := e.inner
+	for global != nil && global.inner != nil {
+		if _, ok := global.variables[name]; ok {
+			return errors.Errorf("cannot assign to captured variable %s", name)
+		}
+		global = global.inner
+	}
+
+	if global != nil {
+		if _, ok := global.variables[name]; ok {
+			global.variables[name] = value
+			return nil
+		}
+	}
+
+	return errors.Errorf("unable to resolve identifier: %s", name)
+}
+
 func (e Environment) Get(name string) (Object, error) {
 	if value, ok := e.variables[name]; ok {
 		return value, nil
@@ -32,5 +59,5 @@ func (e Environment) Get(name string) (Object, error) {
 		return e.inner.Get(name)
 	}
 
-	return nil, errors.Errorf("undefined identifier: %s", name)
+	return nil, errors.Errorf("unable to resolve identifier: %s", name)
 }
@@ -60,3 +60,24 @@ func Test_Environment_Get_not_existing_enclosedEnvironment(t *testing.T) {
 	// then
 	assert.Error(t, err)
 }
+
+func Test_Environment_Assign(t *testing.T) {
+	global := NewEnvironment()
+	global.Set("g", &True)
+	enclosing := ExtendEnvironment(global)
+	enclosing.Set("captured", &True)
+	local := ExtendEnvironment(enclosing)
+	local.Set("l", &True)
+
+	assert.NoError(t, local.Assign("l", &False))
+	assert.NoError(t, local.Assign("g", &False))
+	assert.EqualError(t, local.Assign("captured", &False), "cannot assign to captured variable captured")
+	assert.EqualError(t, local.Assign("missing", &False), "unable to resolve identifier: missing")
+
+	value, _ := local.Get("l")
+	assert.Equal(t, &False, value)
+	value, _ = global.Get("g")
+	assert.Equal(t, &False, value)
+	value, _ = enclosing.Get("captured")
+	assert.Equal(t, &True, value)
+}
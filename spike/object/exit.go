@@ -0,0 +1,17 @@
+package object
+
+import "fmt"
+
+// MaxExitCode is the largest code scripts can exit with, processes only keep
+// the lowest byte of their exit code.
+const MaxExitCode = 255
+
+// ExitError is returned by the exit builtin. Engines stop executing and pass
+// it up like any other error so the host can end the run with Code.
+type ExitError struct {
+	Code int
+}
+
+func (exit *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", exit.Code)
+}
@@ -0,0 +1,50 @@
+package object
+
+import "github.com/pkg/errors"
+
+// Freeze marks value and every array and hash reachable from it as frozen and
+// returns value. Frozen objects can still be read and copied, deepCopy of a
+// frozen object is not frozen.
+func Freeze(value Object) Object {
+	switch value := value.(type) {
+	case *Array:
+		if value.Frozen {
+			return value
+		}
+
+		value.Frozen = true
+		for _, element := range value.Elements {
+			Freeze(element)
+		}
+
+	case *Hash:
+		if value.Frozen {
+			return value
+		}
+
+		value.Frozen = true
+		for _, pair := range value.Pairs {
+			Freeze(pair.Value)
+		}
+	}
+
+	return value
+}
+
+// CheckMutable returns an error if value is frozen. Anything that modifies an
+// array or hash in place must call it first.
+func CheckMutable(value Object) error {
+	frozen := false
+	switch value := value.(type) {
+	case *Array:
+		frozen = value.Frozen
+	case *Hash:
+		frozen = value.Frozen
+	}
+
+	if frozen {
+		return errors.Errorf("cannot modify frozen %s", value.Type())
+	}
+
+	return nil
+}
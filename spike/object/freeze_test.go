@@ -0,0 +1,25 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Freeze(t *testing.T) {
+	inner := &Array{Elements: []Object{&Integer{Value: 1}}}
+	hash := NewHash(1)
+	setStringKey(hash, "inner", inner)
+	cyclic := &Array{}
+	cyclic.Elements = []Object{hash, cyclic}
+
+	assert.True(t, Freeze(cyclic) == cyclic)
+
+	assert.True(t, cyclic.Frozen)
+	assert.True(t, hash.Frozen)
+	assert.True(t, inner.Frozen)
+	assert.EqualError(t, CheckMutable(inner), "cannot modify frozen array")
+	assert.EqualError(t, CheckMutable(hash), "cannot modify frozen hash")
+	assert.NoError(t, CheckMutable(DeepCopy(hash)))
+	assert.NoError(t, CheckMutable(&Integer{Value: 1}))
+}
@@ -9,6 +9,11 @@ type Function struct {
 	Parameters  []*ast.Identifier
 	Body        ast.Statement
 	Environment *Environment
+	Name        string
+	Generator   bool
+	Doc         string
+	// Line is where the function is defined.
+	Line int
 }
 
 func (function *Function) Type() ObjectType {
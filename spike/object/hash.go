@@ -1,9 +1,10 @@
 package object
 
 import (
-	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 type HashPair struct {
@@ -11,8 +12,16 @@ type HashPair struct {
 	Value Object
 }
 
+// Hash keeps its pairs in insertion order, which Inspect, keys, values and
+// iteration follow. Use NewHash and Set so Keys stays in sync with Pairs.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	Pairs  map[HashKey]HashPair
+	Keys   []HashKey
+	Frozen bool
+}
+
+func NewHash(size int) *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair, size)}
 }
 
 func (hash *Hash) Type() ObjectType {
@@ -20,14 +29,24 @@ func (hash *Hash) Type() ObjectType {
 }
 
 func (hash *Hash) Inspect() string {
+	return hash.inspect(inspecting{})
+}
+
+func (hash *Hash) inspect(seen inspecting) string {
+	if seen[hash] {
+		return "{...}"
+	}
+	seen[hash] = true
+	defer delete(seen, hash)
+
 	out := strings.Builder{}
 
 	out.WriteString("{")
 	inspectedPairs := make([]string, 0, len(hash.Pairs))
-	for _, pair := range hash.Pairs {
+	for _, pair := range hash.OrderedPairs() {
 		inspectedPairs = append(
 			inspectedPairs,
-			fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()),
+			fmt.Sprintf("%s: %s", pair.Key.Inspect(), inspectNested(pair.Value, seen)),
 		)
 	}
 
@@ -38,17 +57,28 @@ func (hash *Hash) Inspect() string {
 }
 
 func (hash *Hash) Equal(other Object) bool {
+	return hash.equal(other, comparing{})
+}
+
+func (hash *Hash) equal(other Object, seen comparing) bool {
 	otherHash, ok := other.(*Hash)
 	if !ok {
 		return false
 	}
 
+	pair := comparison{left: hash, right: otherHash}
+	if seen[pair] {
+		return true
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
 	for key, val := range hash.Pairs {
 		val2, ok := otherHash.Pairs[key]
 		if !ok {
 			return false
 		}
-		if !val.Value.Equal(val2.Value) {
+		if !equalNested(val.Value, val2.Value, seen) {
 			return false
 		}
 	}
@@ -65,3 +95,48 @@ func (hash *Hash) Get(key1 Hashable) (Object, error) {
 
 	return pair.Value, nil
 }
+
+// HashKeyOf returns the hash key for key or an error if it is not Hashable.
+func HashKeyOf(key Object) (HashKey, error) {
+	hashable, ok := key.(Hashable)
+	if !ok {
+		return HashKey{}, errors.Errorf("unusable as hash key: %s", key.Type())
+	}
+
+	return hashable.GetHashKey(), nil
+}
+
+// Set stores value under key, which must be Hashable. A new key is added
+// after the existing ones, replacing a value keeps the key's position.
+func (hash *Hash) Set(key Object, value Object) {
+	hashKey := key.(Hashable).GetHashKey()
+	if _, ok := hash.Pairs[hashKey]; !ok {
+		hash.Keys = append(hash.Keys, hashKey)
+	}
+
+	hash.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (hash *Hash) Delete(key HashKey) {
+	if _, ok := hash.Pairs[key]; !ok {
+		return
+	}
+
+	delete(hash.Pairs, key)
+	for i, existing := range hash.Keys {
+		if existing == key {
+			hash.Keys = append(hash.Keys[:i:i], hash.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// OrderedPairs returns the pairs in the order their keys were inserted.
+func (hash *Hash) OrderedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(hash.Keys))
+	for _, key := range hash.Keys {
+		pairs = append(pairs, hash.Pairs[key])
+	}
+
+	return pairs
+}
@@ -90,3 +90,28 @@ func TestHash_GetByKey(t *testing.T) {
 	assert.Equal(t, expectedValueForKey, value)
 	assert.NoError(t, err)
 }
+
+func TestHash_Inspect_keepsInsertionOrder(t *testing.T) {
+	hash := NewHash(0)
+	for _, key := range []Object{
+		&String{Value: "b"},
+		&Integer{Value: 10},
+		&True,
+		&String{Value: "a"},
+	} {
+		hash.Set(key, &NullObject)
+	}
+	hash.Set(&Integer{Value: 10}, &Integer{Value: 1})
+	hash.Delete((&True).GetHashKey())
+
+	assert.Equal(t, `{"b": null, 10: 1, "a": null}`, hash.Inspect())
+}
+
+func hashOf(keysAndValues ...Object) *Hash {
+	hash := NewHash(len(keysAndValues) / 2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		hash.Set(keysAndValues[i], keysAndValues[i+1])
+	}
+
+	return hash
+}
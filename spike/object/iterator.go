@@ -0,0 +1,116 @@
+package object
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Iterator produces the values of an Iterable one at a time. Next reports
+// false once the values are exhausted.
+type Iterator interface {
+	Object
+	Next() (Object, bool, error)
+}
+
+type Iterable interface {
+	Iterate() Iterator
+}
+
+func (array *Array) Iterate() Iterator {
+	return &arrayIterator{elements: array.Elements}
+}
+
+// Iterate yields the keys of the hash in insertion order.
+func (hash *Hash) Iterate() Iterator {
+	pairs := hash.OrderedPairs()
+	keys := make([]Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+
+	return &arrayIterator{elements: keys}
+}
+
+// Iterate yields the characters of the string as one-character strings.
+func (str *String) Iterate() Iterator {
+	return &stringIterator{value: str.Value}
+}
+
+type iterator struct{}
+
+func (iterator) Type() ObjectType {
+	return IteratorType
+}
+
+type arrayIterator struct {
+	iterator
+	elements []Object
+	position int
+}
+
+func (it *arrayIterator) Inspect() string {
+	return fmt.Sprintf("iterator[%p]", it)
+}
+
+func (it *arrayIterator) Equal(other Object) bool {
+	return other == it
+}
+
+func (it *arrayIterator) Iterate() Iterator {
+	return it
+}
+
+func (it *arrayIterator) Next() (Object, bool, error) {
+	if it.position >= len(it.elements) {
+		return nil, false, nil
+	}
+
+	it.position++
+
+	return it.elements[it.position-1], true, nil
+}
+
+type stringIterator struct {
+	iterator
+	value string
+}
+
+func (it *stringIterator) Inspect() string {
+	return fmt.Sprintf("iterator[%p]", it)
+}
+
+func (it *stringIterator) Equal(other Object) bool {
+	return other == it
+}
+
+func (it *stringIterator) Iterate() Iterator {
+	return it
+}
+
+func (it *stringIterator) Next() (Object, bool, error) {
+	if it.value == "" {
+		return nil, false, nil
+	}
+
+	_, size := utf8.DecodeRuneInString(it.value)
+	character := it.value[:size]
+	it.value = it.value[size:]
+
+	return &String{Value: character}, true, nil
+}
+
+// Each calls f with every value of the iterable, stopping at the first error.
+func Each(iterable Iterable, f func(value Object) error) error {
+	it := iterable.Iterate()
+	for {
+		value, ok, err := it.Next()
+		if err != nil || !ok {
+			return err
+		}
+
+		err = f(value)
+		if err != nil {
+			return err
+		}
+	}
+}
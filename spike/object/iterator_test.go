@@ -0,0 +1,70 @@
+package object
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Iterate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		iterable       Iterable
+		expectedValues []Object
+	}{
+		{
+			name:           "array",
+			iterable:       &Array{Elements: []Object{&Integer{Value: 1}, &String{Value: "a"}}},
+			expectedValues: []Object{&Integer{Value: 1}, &String{Value: "a"}},
+		},
+		{
+			name:           "hash keys",
+			iterable:       hashOf(&String{Value: "b"}, &NullObject, &Integer{Value: 2}, &NullObject),
+			expectedValues: []Object{&String{Value: "b"}, &Integer{Value: 2}},
+		},
+		{
+			name:           "string characters",
+			iterable:       &String{Value: "hé!"},
+			expectedValues: []Object{&String{Value: "h"}, &String{Value: "é"}, &String{Value: "!"}},
+		},
+		{
+			name:           "range",
+			iterable:       &Range{Start: 1, End: 4, Step: 1},
+			expectedValues: []Object{&Integer{Value: 1}, &Integer{Value: 2}, &Integer{Value: 3}},
+		},
+		{
+			name:           "descending range",
+			iterable:       &Range{Start: 5, End: 0, Step: -2},
+			expectedValues: []Object{&Integer{Value: 5}, &Integer{Value: 3}, &Integer{Value: 1}},
+		},
+		{
+			name:           "empty range",
+			iterable:       &Range{Start: 3, End: 3, Step: 1},
+			expectedValues: []Object{},
+		},
+		{
+			name:           "range ending at the integer limit",
+			iterable:       &Range{Start: math.MaxInt64 - 1, End: math.MaxInt64, Step: 5},
+			expectedValues: []Object{&Integer{Value: math.MaxInt64 - 1}},
+		},
+		{
+			name:           "range stepping over the integer limit",
+			iterable:       &Range{Start: math.MaxInt64 - 1, End: math.MaxInt64, Step: 1},
+			expectedValues: []Object{&Integer{Value: math.MaxInt64 - 1}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			values := []Object{}
+			err := Each(testCase.iterable, func(value Object) error {
+				values = append(values, value)
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedValues, values)
+		})
+	}
+}
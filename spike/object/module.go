@@ -0,0 +1,20 @@
+package object
+
+// Module is the value bound by `import "db"`, holding the functions an
+// embedder registered as db. They are reached as members, as in `db.query()`.
+type Module struct {
+	Name    string
+	Members map[string]Object
+}
+
+func (module *Module) Type() ObjectType {
+	return ModuleType
+}
+
+func (module *Module) Inspect() string {
+	return "module " + module.Name
+}
+
+func (module *Module) Equal(other Object) bool {
+	return module == other
+}
@@ -1,5 +1,6 @@
 package object
 
+// NullObject is the only null value, both engines return &NullObject.
 var NullObject = Null{}
 
 type Null struct{}
@@ -20,3 +21,16 @@ func (null *Null) Equal(other Object) bool {
 
 	return true
 }
+
+// Truthy reports whether value counts as true in conditions: false and null
+// are falsy, every other value, including 0, "" and [], is truthy.
+func Truthy(value Object) bool {
+	switch value := value.(type) {
+	case *Boolean:
+		return value.Value
+	case *Null:
+		return false
+	}
+
+	return true
+}
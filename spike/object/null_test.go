@@ -29,3 +29,23 @@ func Test_Null_Equal(t *testing.T) {
 		})
 	}
 }
+
+func Test_Truthy(t *testing.T) {
+	testCases := []struct {
+		value          Object
+		expectedResult bool
+	}{
+		{value: &True, expectedResult: true},
+		{value: &False, expectedResult: false},
+		{value: &NullObject, expectedResult: false},
+		{value: &Integer{Value: 0}, expectedResult: true},
+		{value: &String{Value: ""}, expectedResult: true},
+		{value: &Array{}, expectedResult: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value.Inspect(), func(t *testing.T) {
+			assert.Equal(t, testCase.expectedResult, Truthy(testCase.value))
+		})
+	}
+}
@@ -14,6 +14,15 @@ const (
 	HashType             ObjectType = "hash"
 	CompiledFunctionType ObjectType = "compiledFunction"
 	ClosureType          ObjectType = "closure"
+	RegexType            ObjectType = "regex"
+	IteratorType         ObjectType = "iterator"
+	RangeType            ObjectType = "range"
+	BytesType            ObjectType = "bytes"
+	BigIntType           ObjectType = "bigint"
+	TupleType            ObjectType = "tuple"
+	EnumType             ObjectType = "enum"
+	EnumMemberType       ObjectType = "enumMember"
+	ModuleType           ObjectType = "module"
 )
 
 type Ordering int8
@@ -34,6 +43,8 @@ type Comparable interface {
 	Compare(other Comparable) (Ordering, error)
 }
 
+// Hashable objects can be used as hash keys. Integers, strings and booleans
+// are hashable, using any other value as a key is a runtime error.
 type Hashable interface {
 	GetHashKey() HashKey
 }
@@ -0,0 +1,65 @@
+package object
+
+import "fmt"
+
+// Range is a lazy sequence of integers from Start up to, but not including,
+// End, advancing by Step.
+type Range struct {
+	Start int64
+	End   int64
+	Step  int64
+}
+
+func (r *Range) Type() ObjectType {
+	return RangeType
+}
+
+func (r *Range) Inspect() string {
+	return fmt.Sprintf("range(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+func (r *Range) Equal(other Object) bool {
+	otherRange, ok := other.(*Range)
+	if !ok {
+		return false
+	}
+
+	return *r == *otherRange
+}
+
+func (r *Range) Iterate() Iterator {
+	return &rangeIterator{r: *r, next: r.Start}
+}
+
+type rangeIterator struct {
+	iterator
+	r    Range
+	next int64
+	done bool
+}
+
+func (it *rangeIterator) Inspect() string {
+	return fmt.Sprintf("iterator[%p]", it)
+}
+
+func (it *rangeIterator) Equal(other Object) bool {
+	return other == it
+}
+
+func (it *rangeIterator) Iterate() Iterator {
+	return it
+}
+
+func (it *rangeIterator) Next() (Object, bool, error) {
+	if it.done || (it.r.Step > 0 && it.next >= it.r.End) || (it.r.Step < 0 && it.next <= it.r.End) {
+		return nil, false, nil
+	}
+
+	value := it.next
+	it.next += it.r.Step
+	if (it.r.Step > 0 && it.next < value) || (it.r.Step < 0 && it.next > value) {
+		it.done = true
+	}
+
+	return &Integer{Value: value}, true, nil
+}
@@ -0,0 +1,58 @@
+package object
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+const regexCacheSize = 256
+
+type Regex struct {
+	Pattern *regexp.Regexp
+}
+
+func (regex *Regex) Type() ObjectType {
+	return RegexType
+}
+
+func (regex *Regex) Inspect() string {
+	return fmt.Sprintf("regex(\"%s\")", regex.Pattern.String())
+}
+
+func (regex *Regex) Equal(other Object) bool {
+	otherRegex, ok := other.(*Regex)
+	if !ok {
+		return false
+	}
+
+	return regex.Pattern.String() == otherRegex.Pattern.String()
+}
+
+var regexCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{patterns: map[string]*regexp.Regexp{}}
+
+// CompileRegex compiles pattern, reusing the result of earlier calls so that
+// regex builtins called in a loop don't recompile their pattern each time.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.Lock()
+	defer regexCache.Unlock()
+
+	if compiled, ok := regexCache.patterns[pattern]; ok {
+		return compiled, nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regexCache.patterns) >= regexCacheSize {
+		regexCache.patterns = map[string]*regexp.Regexp{}
+	}
+	regexCache.patterns[pattern] = compiled
+
+	return compiled, nil
+}
@@ -0,0 +1,77 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CompileRegex_reusesCompiledPatterns(t *testing.T) {
+	first, err := CompileRegex(`a+b`)
+	assert.NoError(t, err)
+
+	second, err := CompileRegex(`a+b`)
+	assert.NoError(t, err)
+
+	assert.True(t, first == second)
+
+	_, err = CompileRegex(`a(`)
+	assert.EqualError(t, err, "error parsing regexp: missing closing ): `a(`")
+}
+
+func Test_RegexBuiltins(t *testing.T) {
+	pattern, err := GetBuiltinByName("regex").Function(nil, &String{Value: `(\w+)@(\w+)`})
+	assert.NoError(t, err)
+	assert.Equal(t, `regex("(\w+)@(\w+)")`, pattern.Inspect())
+
+	testCases := []struct {
+		builtin        string
+		args           []Object
+		expectedResult Object
+	}{
+		{
+			builtin:        "regexMatch",
+			args:           []Object{pattern, &String{Value: "mail: joe@example"}},
+			expectedResult: &True,
+		},
+		{
+			builtin:        "regexMatch",
+			args:           []Object{&String{Value: `^\d+$`}, &String{Value: "12a"}},
+			expectedResult: &False,
+		},
+		{
+			builtin: "regexFind",
+			args:    []Object{pattern, &String{Value: "joe@example, ann@test"}},
+			expectedResult: &Array{Elements: []Object{
+				&String{Value: "joe@example"}, &String{Value: "joe"}, &String{Value: "example"},
+			}},
+		},
+		{
+			builtin:        "regexFind",
+			args:           []Object{pattern, &String{Value: "nothing here"}},
+			expectedResult: &NullObject,
+		},
+		{
+			builtin:        "regexReplace",
+			args:           []Object{pattern, &String{Value: "joe@example, ann@test"}, &String{Value: "$2/$1"}},
+			expectedResult: &String{Value: "example/joe, test/ann"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.builtin, func(t *testing.T) {
+			result, err := GetBuiltinByName(testCase.builtin).Function(nil, testCase.args...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+func Test_RegexBuiltins_invalidArguments(t *testing.T) {
+	_, err := GetBuiltinByName("regexMatch").Function(nil, &Integer{Value: 1}, &String{Value: ""})
+	assert.EqualError(t, err, "regexMatch: argument 1 must be regex or string, got integer")
+
+	_, err = GetBuiltinByName("regex").Function(nil, &String{Value: "["})
+	assert.EqualError(t, err, "regex: error parsing regexp: missing closing ]: `[`")
+}
@@ -0,0 +1,82 @@
+package object
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Stdin is the reader used by engines that were not given their own, shared
+// so that input buffered by one run is not lost to the next.
+var Stdin = bufio.NewReader(os.Stdin)
+
+const (
+	TopLevelFrameName  = "<main>"
+	AnonymousFrameName = "<anonymous>"
+)
+
+// MainFunctionName is the entry point engines call after running a script.
+const MainFunctionName = "main"
+
+// Runtime is the view of the executing engine that builtins receive, giving
+// them access to per-engine state instead of process globals.
+type Runtime interface {
+	Stdout() io.Writer
+	Stderr() io.Writer
+	Stdin() *bufio.Reader
+	// Context is cancelled when the run is interrupted, blocking builtins
+	// should return its error.
+	Context() context.Context
+	Call(function Object, args ...Object) (Object, error)
+	Rand() *rand.Rand
+	Policy() *Policy
+	// Args are the command line arguments the script was run with.
+	Args() []string
+
+	// CallDepth is the number of function calls currently in progress.
+	CallDepth() int
+	// StackTrace names the active calls, innermost first, ending with
+	// TopLevelFrameName.
+	StackTrace() []string
+}
+
+// StepLimitError stops engines that ran more steps than they were limited to.
+type StepLimitError struct {
+	Limit int
+}
+
+func (err *StepLimitError) Error() string {
+	return fmt.Sprintf("step limit of %d exceeded", err.Limit)
+}
+
+// ArityError reports a call giving a function defined at line got arguments
+// instead of the want it takes. Line is 0 when the definition is unknown.
+func ArityError(want, got, line int) error {
+	if line == 0 {
+		return errors.Errorf("wrong number of arguments: want %d, got %d", want, got)
+	}
+
+	return errors.Errorf("wrong number of arguments: want %d, got %d (function defined at line %d)", want, got, line)
+}
+
+func FrameName(name string) string {
+	if name == "" {
+		return AnonymousFrameName
+	}
+
+	return name
+}
+
+// BufferedReader wraps reader for Runtime.Stdin unless it is buffered already.
+func BufferedReader(reader io.Reader) *bufio.Reader {
+	if buffered, ok := reader.(*bufio.Reader); ok {
+		return buffered
+	}
+
+	return bufio.NewReader(reader)
+}
@@ -0,0 +1,128 @@
+package object
+
+import "fmt"
+
+type Capability string
+
+const (
+	FileReadCapability  Capability = "file read"
+	FileWriteCapability Capability = "file write"
+	NetworkCapability   Capability = "network access"
+	ProcessCapability   Capability = "running processes"
+)
+
+// Policy decides which capabilities builtins may use and which builtins may be
+// called at all. Capabilities that were never allowed are denied, builtins are
+// allowed unless denied or left out of AllowOnlyBuiltins.
+type Policy struct {
+	allowed map[Capability]bool
+	// builtins lists the only builtins that may be called, nil allows all.
+	builtins       map[string]bool
+	deniedBuiltins map[string]bool
+}
+
+func NewPolicy(allowed ...Capability) *Policy {
+	return (&Policy{allowed: map[Capability]bool{}}).Allow(allowed...)
+}
+
+// DefaultPolicy allows everything except running processes, which embedders
+// have to allow explicitly.
+func DefaultPolicy() *Policy {
+	return NewPolicy(FileReadCapability, FileWriteCapability, NetworkCapability)
+}
+
+// impureBuiltins reach outside of the engine, or depend on when they run.
+var impureBuiltins = []string{
+	"readFile", "writeFile", "appendFile", "readFileBytes", "writeFileBytes", "listDir",
+	"httpGet", "httpRequest", "exec",
+	"now", "clock", "sleep", "read", "input", "exit",
+}
+
+// PurePolicy is for untrusted scripts: it allows no capabilities and denies
+// every builtin touching files, the network, processes, the clock or input.
+// Printing is left to the engine's stdout.
+func PurePolicy() *Policy {
+	return NewPolicy().DenyBuiltins(impureBuiltins...)
+}
+
+func (policy *Policy) Allow(capabilities ...Capability) *Policy {
+	for _, capability := range capabilities {
+		policy.allowed[capability] = true
+	}
+
+	return policy
+}
+
+func (policy *Policy) Deny(capabilities ...Capability) *Policy {
+	for _, capability := range capabilities {
+		delete(policy.allowed, capability)
+	}
+
+	return policy
+}
+
+func (policy *Policy) Allows(capability Capability) bool {
+	return policy.allowed[capability]
+}
+
+func (policy *Policy) Check(name string, capability Capability) error {
+	if policy.Allows(capability) {
+		return nil
+	}
+
+	return &PermissionError{Name: name, Capability: capability}
+}
+
+// AllowOnlyBuiltins makes names the only builtins that may be called, on top
+// of the ones denied with DenyBuiltins.
+func (policy *Policy) AllowOnlyBuiltins(names ...string) *Policy {
+	policy.builtins = map[string]bool{}
+	for _, name := range names {
+		policy.builtins[name] = true
+	}
+
+	return policy
+}
+
+func (policy *Policy) DenyBuiltins(names ...string) *Policy {
+	if policy.deniedBuiltins == nil {
+		policy.deniedBuiltins = map[string]bool{}
+	}
+	for _, name := range names {
+		policy.deniedBuiltins[name] = true
+	}
+
+	return policy
+}
+
+func (policy *Policy) AllowsBuiltin(name string) bool {
+	if policy.deniedBuiltins[name] {
+		return false
+	}
+
+	return policy.builtins == nil || policy.builtins[name]
+}
+
+// CheckBuiltin is called by the engines before calling a builtin.
+func (policy *Policy) CheckBuiltin(name string) error {
+	if policy.AllowsBuiltin(name) {
+		return nil
+	}
+
+	return &PermissionError{Name: name}
+}
+
+// PermissionError is returned for builtins the policy denies, or denies the
+// Capability of when it is set.
+type PermissionError struct {
+	Name       string
+	Capability Capability
+}
+
+func (err *PermissionError) Error() string {
+	if err.Capability == "" {
+		return fmt.Sprintf("%s is not permitted by the sandbox policy", err.Name)
+	}
+
+	return fmt.Sprintf("%s: %s is not permitted by the sandbox policy", err.Name, err.Capability)
+}
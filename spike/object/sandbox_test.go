@@ -0,0 +1,44 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Policy(t *testing.T) {
+	policy := DefaultPolicy()
+	assert.NoError(t, policy.Check("readFile", FileReadCapability))
+	assert.True(t, policy.Allows(FileWriteCapability))
+	assert.False(t, policy.Allows(ProcessCapability))
+
+	policy.Deny(FileWriteCapability)
+	assert.False(t, policy.Allows(FileWriteCapability))
+	assert.EqualError(
+		t,
+		policy.Check("writeFile", FileWriteCapability),
+		"writeFile: file write is not permitted by the sandbox policy",
+	)
+
+	assert.False(t, NewPolicy().Allows(FileReadCapability))
+}
+
+func Test_Policy_builtins(t *testing.T) {
+	policy := DefaultPolicy()
+	assert.True(t, policy.AllowsBuiltin("exec"))
+
+	policy.DenyBuiltins("exec")
+	assert.False(t, policy.AllowsBuiltin("exec"))
+	assert.EqualError(t, policy.CheckBuiltin("exec"), "exec is not permitted by the sandbox policy")
+
+	policy.AllowOnlyBuiltins("len", "exec")
+	assert.True(t, policy.AllowsBuiltin("len"))
+	assert.False(t, policy.AllowsBuiltin("exec"))
+	assert.False(t, policy.AllowsBuiltin("print"))
+
+	pure := PurePolicy()
+	assert.True(t, pure.AllowsBuiltin("map"))
+	assert.False(t, pure.AllowsBuiltin("readFile"))
+	assert.False(t, pure.AllowsBuiltin("now"))
+	assert.False(t, pure.Allows(NetworkCapability))
+}
@@ -3,6 +3,7 @@ package object
 import (
 	"fmt"
 	"hash/fnv"
+	"unicode/utf8"
 )
 
 type String struct {
@@ -38,3 +39,25 @@ func (str *String) GetHashKey() HashKey {
 		Value: h.Sum64(),
 	}
 }
+
+// Length returns the number of characters in the string. Strings are
+// indexed, sliced and iterated by character, bytes() gives access to the
+// underlying UTF-8 encoding.
+func (str *String) Length() int {
+	return utf8.RuneCountInString(str.Value)
+}
+
+// Character returns the character at index as a one-character string.
+func (str *String) Character(index int64) (Object, error) {
+	if index >= 0 {
+		remaining := index
+		for _, char := range str.Value {
+			if remaining == 0 {
+				return &String{Value: string(char)}, nil
+			}
+			remaining--
+		}
+	}
+
+	return nil, IndexOutOfRange(index, str.Length())
+}
@@ -0,0 +1,79 @@
+package object
+
+import (
+	"reflect"
+	"strings"
+)
+
+// structTag names struct fields in scripts, as in `spike:"name"`. A name of
+// "-" leaves the field out, the omitempty option leaves it out of hashes when
+// it holds its zero value.
+const structTag = "spike"
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields lists the exported fields of structType in declaration order.
+// Fields of embedded structs without a tag name are listed as if they were
+// declared in structType.
+func structFields(structType reflect.Type) []structField {
+	fields := []structField{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, options := parseStructTag(field.Tag.Get(structTag))
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{name: name, index: []int{i}, omitEmpty: options == "omitempty"})
+	}
+
+	return fields
+}
+
+func parseStructTag(tag string) (string, string) {
+	parts := strings.SplitN(tag, ",", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+func fromStruct(value reflect.Value, visiting visiting) (Object, error) {
+	fields := structFields(value.Type())
+
+	hash := NewHash(len(fields))
+	for _, field := range fields {
+		fieldValue := value.FieldByIndex(field.index)
+		if field.omitEmpty && reflect.DeepEqual(fieldValue.Interface(), reflect.Zero(fieldValue.Type()).Interface()) {
+			continue
+		}
+
+		element, err := fromGo(fieldValue, visiting)
+		if err != nil {
+			return nil, err
+		}
+		hash.Set(&String{Value: field.name}, element)
+	}
+
+	return hash, nil
+}
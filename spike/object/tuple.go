@@ -0,0 +1,81 @@
+package object
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Tuple holds the values of `return a, b` until `let x, y = ...` takes them
+// apart again.
+type Tuple struct {
+	Elements []Object
+}
+
+func (tuple *Tuple) Type() ObjectType {
+	return TupleType
+}
+
+func (tuple *Tuple) Inspect() string {
+	return tuple.inspect(inspecting{})
+}
+
+func (tuple *Tuple) inspect(seen inspecting) string {
+	if seen[tuple] {
+		return "(...)"
+	}
+	seen[tuple] = true
+	defer delete(seen, tuple)
+
+	elements := make([]string, len(tuple.Elements))
+	for i, element := range tuple.Elements {
+		elements[i] = inspectNested(element, seen)
+	}
+
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+func (tuple *Tuple) Equal(other Object) bool {
+	return tuple.equal(other, comparing{})
+}
+
+func (tuple *Tuple) equal(other Object, seen comparing) bool {
+	otherTuple, ok := other.(*Tuple)
+	if !ok || len(tuple.Elements) != len(otherTuple.Elements) {
+		return false
+	}
+
+	pair := comparison{left: tuple, right: otherTuple}
+	if seen[pair] {
+		return true
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
+	for i := range tuple.Elements {
+		if !equalNested(tuple.Elements[i], otherTuple.Elements[i], seen) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tuple *Tuple) Iterate() Iterator {
+	return &arrayIterator{elements: tuple.Elements}
+}
+
+// Destructure returns the elements of value, which must be a tuple of
+// exactly count elements.
+func Destructure(value Object, count int) ([]Object, error) {
+	tuple, ok := value.(*Tuple)
+	if !ok {
+		return nil, errors.Errorf("cannot destructure %s into %d names", value.Type(), count)
+	}
+
+	if len(tuple.Elements) != count {
+		return nil, errors.Errorf("cannot destructure tuple of %d elements into %d names", len(tuple.Elements), count)
+	}
+
+	return tuple.Elements, nil
+}
@@ -8,12 +8,21 @@ import (
 type Array struct {
 	Token    lexer.Token
 	Elements []Expression
+	Closing  lexer.Token
 }
 
 func (array *Array) TokenLiteral() string {
 	return array.Token.Literal
 }
 
+func (array *Array) Pos() lexer.Position {
+	return array.Token.Position
+}
+
+func (array *Array) End() lexer.Position {
+	return array.Closing.End()
+}
+
 func (array *Array) String() string {
 	out := strings.Builder{}
 
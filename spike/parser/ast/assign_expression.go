@@ -0,0 +1,32 @@
+package ast
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+)
+
+// AssignExpression is `Target = Value`, Target is an *Identifier or an
+// *IndexExpression.
+type AssignExpression struct {
+	Token  lexer.Token
+	Target Expression
+	Value  Expression
+}
+
+func (assign *AssignExpression) TokenLiteral() string {
+	return assign.Token.Literal
+}
+
+func (assign *AssignExpression) Pos() lexer.Position {
+	return assign.Target.Pos()
+}
+
+func (assign *AssignExpression) End() lexer.Position {
+	return assign.Value.End()
+}
+
+func (assign *AssignExpression) expression() {}
+
+func (assign *AssignExpression) String() string {
+	return fmt.Sprintf("(%s = %s)", assign.Target.String(), assign.Value.String())
+}
@@ -1,8 +1,14 @@
 package ast
 
+import "spike-interpreter-go/spike/lexer"
+
+// Node is a piece of parsed source. Pos is the position of its first
+// character and End the position just past its last one.
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() lexer.Position
+	End() lexer.Position
 }
 
 type Statement interface {
@@ -23,6 +29,14 @@ func (statement *ExpressionStatement) TokenLiteral() string {
 	return "Expression"
 }
 
+func (statement *ExpressionStatement) Pos() lexer.Position {
+	return statement.Expression.Pos()
+}
+
+func (statement *ExpressionStatement) End() lexer.Position {
+	return statement.Expression.End()
+}
+
 func (statement *ExpressionStatement) statement() {
 }
 
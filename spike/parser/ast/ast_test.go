@@ -20,7 +20,7 @@ func Test_Node2String(t *testing.T) {
 				},
 				Operator: "!",
 				Right: &Identifier{
-					Token: lexer.Token{lexer.Identifier, "bool"},
+					Token: lexer.Token{Type: lexer.Identifier, Literal: "bool"},
 					Value: "bool",
 				},
 			},
@@ -29,13 +29,13 @@ func Test_Node2String(t *testing.T) {
 		{
 			ast: &Program{Statements: []Statement{
 				&LetStatement{
-					Token: lexer.Token{lexer.Let, "let"},
+					Token: lexer.Token{Type: lexer.Let, Literal: "let"},
 					Name: &Identifier{
-						Token: lexer.Token{lexer.Identifier, "var"},
+						Token: lexer.Token{Type: lexer.Identifier, Literal: "var"},
 						Value: "var",
 					},
 					Value: &Identifier{
-						Token: lexer.Token{lexer.Identifier, "var2"},
+						Token: lexer.Token{Type: lexer.Identifier, Literal: "var2"},
 						Value: "var2",
 					},
 				},
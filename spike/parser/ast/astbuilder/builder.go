@@ -0,0 +1,270 @@
+// Package astbuilder provides fluent constructors for AST nodes, producing
+// the same trees the parser does without spelling out every token by hand:
+//
+//	B.Program(B.Let("x", B.Int(5)), B.Expr(B.Infix(B.Ident("x"), "+", B.Int(1))))
+package astbuilder
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser/ast"
+	"strconv"
+)
+
+var B = Builder{}
+
+type Builder struct{}
+
+type Pair struct {
+	Key   ast.Expression
+	Value ast.Expression
+}
+
+var operators = map[string]lexer.Token{
+	"+":  lexer.PlusToken,
+	"-":  lexer.MinusToken,
+	"*":  lexer.AsteriskToken,
+	"/":  lexer.SlashToken,
+	"!":  lexer.BangToken,
+	"<":  lexer.LessThanToken,
+	">":  lexer.GreaterThanToken,
+	"<=": lexer.LessOrEqualToken,
+	">=": lexer.GreaterOrEqualToken,
+	"==": lexer.EqualToken,
+	"!=": lexer.NotEqualToken,
+	"&&": lexer.AndToken,
+	"||": lexer.OrToken,
+}
+
+func (Builder) Program(statements ...ast.Statement) *ast.Program {
+	program := &ast.Program{}
+	for _, statement := range statements {
+		program.AddStatement(statement)
+	}
+
+	return program
+}
+
+func (b Builder) Let(name string, value ast.Expression) *ast.LetStatement {
+	if function, ok := value.(*ast.FunctionExpression); ok {
+		function.Name = name
+	}
+
+	return &ast.LetStatement{
+		Token: lexer.LetToken,
+		Name:  b.Ident(name),
+		Value: value,
+	}
+}
+
+func (b Builder) DestructuringLet(value ast.Expression, names ...string) *ast.DestructuringLetStatement {
+	identifiers := make([]*ast.Identifier, len(names))
+	for i, name := range names {
+		identifiers[i] = b.Ident(name)
+	}
+
+	return &ast.DestructuringLetStatement{
+		Token: lexer.LetToken,
+		Names: identifiers,
+		Value: value,
+	}
+}
+
+func (b Builder) Enum(name string, members ...string) *ast.EnumStatement {
+	identifiers := make([]*ast.Identifier, len(members))
+	for i, member := range members {
+		identifiers[i] = b.Ident(member)
+	}
+
+	return &ast.EnumStatement{
+		Token:   lexer.EnumToken,
+		Name:    b.Ident(name),
+		Members: identifiers,
+		Closing: lexer.RightBraceToken,
+	}
+}
+
+func (b Builder) Import(module string) *ast.ImportStatement {
+	return &ast.ImportStatement{Token: lexer.ImportToken, Module: b.Str(module)}
+}
+
+func (b Builder) Member(object ast.Expression, member string) *ast.MemberExpression {
+	return &ast.MemberExpression{Token: lexer.DotToken, Object: object, Member: b.Ident(member)}
+}
+
+func (Builder) Tuple(elements ...ast.Expression) *ast.Tuple {
+	return &ast.Tuple{Token: lexer.CommaToken, Elements: elements}
+}
+
+func (Builder) Return(result ast.Expression) *ast.ReturnStatement {
+	return &ast.ReturnStatement{Token: lexer.ReturnToken, Result: result}
+}
+
+func (Builder) Expr(expression ast.Expression) *ast.ExpressionStatement {
+	return &ast.ExpressionStatement{Expression: expression}
+}
+
+func (Builder) Block(statements ...ast.Statement) *ast.BlockStatement {
+	block := &ast.BlockStatement{
+		Token:      lexer.LeftBraceToken,
+		Statements: make([]ast.Statement, 0, len(statements)),
+		Closing:    lexer.RightBraceToken,
+	}
+	block.Statements = append(block.Statements, statements...)
+
+	return block
+}
+
+func (Builder) Ident(name string) *ast.Identifier {
+	return &ast.Identifier{
+		Token: lexer.Token{Type: lexer.Identifier, Literal: name},
+		Value: name,
+	}
+}
+
+func (Builder) Int(value int64) *ast.Integer {
+	return &ast.Integer{
+		Token: lexer.Token{Type: lexer.Integer, Literal: strconv.FormatInt(value, 10)},
+		Value: value,
+	}
+}
+
+func (Builder) Float(literal string) *ast.Float {
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		panic(err)
+	}
+
+	return &ast.Float{
+		Token: lexer.Token{Type: lexer.Float, Literal: literal},
+		Value: value,
+	}
+}
+
+func (Builder) Str(value string) *ast.String {
+	return &ast.String{
+		Token: lexer.Token{Type: lexer.String, Literal: value},
+		Value: value,
+	}
+}
+
+func (Builder) Bool(value bool) *ast.Boolean {
+	if value {
+		return &ast.Boolean{Token: lexer.TrueToken, Value: true}
+	}
+
+	return &ast.Boolean{Token: lexer.FalseToken, Value: false}
+}
+
+func (Builder) Prefix(operator string, right ast.Expression) *ast.PrefixExpression {
+	return &ast.PrefixExpression{
+		Token:    operatorToken(operator),
+		Operator: operator,
+		Right:    right,
+	}
+}
+
+func (Builder) Infix(left ast.Expression, operator string, right ast.Expression) *ast.InfixExpression {
+	return &ast.InfixExpression{
+		Token:    operatorToken(operator),
+		Left:     left,
+		Operator: operator,
+		Right:    right,
+	}
+}
+
+func (Builder) Assign(target ast.Expression, value ast.Expression) *ast.AssignExpression {
+	return &ast.AssignExpression{Token: lexer.AssignToken, Target: target, Value: value}
+}
+
+func (Builder) If(condition ast.Expression, then *ast.BlockStatement) *ast.IfExpression {
+	return &ast.IfExpression{
+		Token:     lexer.IfToken,
+		Condition: condition,
+		Then:      then,
+	}
+}
+
+func (b Builder) IfElse(condition ast.Expression, then, otherwise *ast.BlockStatement) *ast.IfExpression {
+	ifExpression := b.If(condition, then)
+	ifExpression.Else = otherwise
+
+	return ifExpression
+}
+
+func (b Builder) Fn(parameters []string, body *ast.BlockStatement) *ast.FunctionExpression {
+	function := &ast.FunctionExpression{Token: lexer.FnToken, Body: body}
+	for _, parameter := range parameters {
+		function.Parameters = append(function.Parameters, b.Ident(parameter))
+	}
+
+	return function
+}
+
+func (b Builder) Generator(parameters []string, body *ast.BlockStatement) *ast.FunctionExpression {
+	function := b.Fn(parameters, body)
+	function.Generator = true
+
+	return function
+}
+
+func (Builder) Yield(value ast.Expression) *ast.YieldExpression {
+	return &ast.YieldExpression{Token: lexer.YieldToken, Value: value}
+}
+
+func (Builder) Call(function ast.Expression, arguments ...ast.Expression) *ast.CallExpression {
+	call := &ast.CallExpression{
+		Token:     lexer.LeftParenthesisToken,
+		Function:  function,
+		Arguments: make([]ast.Expression, 0, len(arguments)),
+		Closing:   lexer.RightParenthesisToken,
+	}
+	call.Arguments = append(call.Arguments, arguments...)
+
+	return call
+}
+
+func (Builder) Index(array, index ast.Expression) *ast.IndexExpression {
+	return &ast.IndexExpression{
+		Token:   lexer.LeftBracketToken,
+		Array:   array,
+		Index:   index,
+		Closing: lexer.RightBracketToken,
+	}
+}
+
+func (Builder) Array(elements ...ast.Expression) *ast.Array {
+	array := &ast.Array{
+		Token:    lexer.LeftBracketToken,
+		Elements: make([]ast.Expression, 0, len(elements)),
+		Closing:  lexer.RightBracketToken,
+	}
+	array.Elements = append(array.Elements, elements...)
+
+	return array
+}
+
+func (Builder) Pair(key, value ast.Expression) Pair {
+	return Pair{Key: key, Value: value}
+}
+
+func (Builder) Hash(pairs ...Pair) *ast.Hash {
+	hash := &ast.Hash{
+		Token:   lexer.LeftBraceToken,
+		Pairs:   make(map[ast.Expression]ast.Expression, len(pairs)),
+		Closing: lexer.RightBraceToken,
+	}
+	for _, pair := range pairs {
+		hash.Pairs[pair.Key] = pair.Value
+		hash.Keys = append(hash.Keys, pair.Key)
+	}
+
+	return hash
+}
+
+func operatorToken(operator string) lexer.Token {
+	if token, ok := operators[operator]; ok {
+		return token
+	}
+
+	return lexer.Token{Type: lexer.Invalid, Literal: operator}
+}
@@ -0,0 +1,79 @@
+package astbuilder
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/parser/ast/asttest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Builder_matchesParser(t *testing.T) {
+	testCases := []struct {
+		code     string
+		expected *ast.Program
+	}{
+		{
+			code:     "let x = 5;",
+			expected: B.Program(B.Let("x", B.Int(5))),
+		},
+		{
+			code:     "return !true;",
+			expected: B.Program(B.Return(B.Prefix("!", B.Bool(true)))),
+		},
+		{
+			code: "a + b * -c",
+			expected: B.Program(B.Expr(
+				B.Infix(B.Ident("a"), "+", B.Infix(B.Ident("b"), "*", B.Prefix("-", B.Ident("c")))),
+			)),
+		},
+		{
+			code: `if (x < 10) { "small" } else { "big" }`,
+			expected: B.Program(B.Expr(B.IfElse(
+				B.Infix(B.Ident("x"), "<", B.Int(10)),
+				B.Block(B.Expr(B.Str("small"))),
+				B.Block(B.Expr(B.Str("big"))),
+			))),
+		},
+		{
+			code: "let add = fn(a, b) { return a + b; }; add(1, 2)",
+			expected: B.Program(
+				B.Let("add", B.Fn([]string{"a", "b"}, B.Block(
+					B.Return(B.Infix(B.Ident("a"), "+", B.Ident("b"))),
+				))),
+				B.Expr(B.Call(B.Ident("add"), B.Int(1), B.Int(2))),
+			),
+		},
+		{
+			code: "fn() { }()",
+			expected: B.Program(B.Expr(
+				B.Call(B.Fn(nil, B.Block())),
+			)),
+		},
+		{
+			code:     "[1, false][0]",
+			expected: B.Program(B.Expr(B.Index(B.Array(B.Int(1), B.Bool(false)), B.Int(0)))),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.code))).ParseProgram()
+
+			assert.NoError(t, err)
+			asttest.AssertEqual(t, testCase.expected, program)
+		})
+	}
+}
+
+func Test_Builder_Hash(t *testing.T) {
+	hash := B.Hash(
+		B.Pair(B.Str("name"), B.Str("kenny")),
+		B.Pair(B.Int(1), B.Bool(true)),
+	)
+
+	assert.Equal(t, `{"name": "kenny", 1: true}`, hash.String())
+}
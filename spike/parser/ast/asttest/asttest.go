@@ -0,0 +1,171 @@
+// Package asttest compares AST nodes structurally, so tests can check the
+// exact tree the parser produced rather than its String form, which hides
+// differences like a missing node or a wrong token type. Trees are usually
+// spelled out with astbuilder:
+//
+//	asttest.AssertEqual(t, B.Program(B.Let("x", B.Int(5))), program)
+//
+// Token positions are not compared, nor are comments attached to statements.
+package asttest
+
+import (
+	"fmt"
+	"reflect"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser/ast"
+	"testing"
+)
+
+var (
+	positionType = reflect.TypeOf(lexer.Position{})
+	hashType     = reflect.TypeOf(ast.Hash{})
+	programType  = reflect.TypeOf(ast.Program{})
+)
+
+// AssertEqual fails the test if actual does not match expected, reporting the
+// path to the first difference.
+func AssertEqual(t testing.TB, expected, actual ast.Node) bool {
+	t.Helper()
+
+	difference := Diff(expected, actual)
+	if difference == "" {
+		return true
+	}
+
+	t.Errorf("AST mismatch at %s\nexpected: %s\nactual:   %s", difference, nodeString(expected), nodeString(actual))
+	return false
+}
+
+// Diff returns a description of the first difference between the trees, or
+// an empty string if they are equal.
+func Diff(expected, actual ast.Node) string {
+	return diff(rootName(expected, actual), reflect.ValueOf(&expected).Elem(), reflect.ValueOf(&actual).Elem())
+}
+
+func diff(path string, expected, actual reflect.Value) string {
+	if expected.Kind() == reflect.Interface {
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				return fmt.Sprintf("%s: expected %s, got %s", path, typeName(expected), typeName(actual))
+			}
+			return ""
+		}
+
+		expected, actual = expected.Elem(), actual.Elem()
+		if expected.Type() != actual.Type() {
+			return fmt.Sprintf("%s: expected %s, got %s", path, expected.Type(), actual.Type())
+		}
+	}
+
+	switch expected.Kind() {
+	case reflect.Ptr:
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				return fmt.Sprintf("%s: expected %s, got %s", path, nilOrType(expected), nilOrType(actual))
+			}
+			return ""
+		}
+
+		return diff(path, expected.Elem(), actual.Elem())
+
+	case reflect.Slice:
+		if expected.Len() != actual.Len() {
+			return fmt.Sprintf("%s: expected %d elements, got %d", path, expected.Len(), actual.Len())
+		}
+
+		for i := 0; i < expected.Len(); i++ {
+			if difference := diff(fmt.Sprintf("%s[%d]", path, i), expected.Index(i), actual.Index(i)); difference != "" {
+				return difference
+			}
+		}
+
+		return ""
+
+	case reflect.Struct:
+		return diffStruct(path, expected, actual)
+
+	default:
+		if expected.Interface() != actual.Interface() {
+			return fmt.Sprintf("%s: expected %#v, got %#v", path, expected.Interface(), actual.Interface())
+		}
+
+		return ""
+	}
+}
+
+func diffStruct(path string, expected, actual reflect.Value) string {
+	if expected.Type() == positionType {
+		return ""
+	}
+
+	for i := 0; i < expected.NumField(); i++ {
+		field := expected.Type().Field(i)
+		if expected.Type() == programType && field.Name == "Attached" {
+			continue
+		}
+		if expected.Type() == hashType && field.Name == "Pairs" {
+			continue
+		}
+
+		fieldPath := path + "." + field.Name
+		if difference := diff(fieldPath, expected.Field(i), actual.Field(i)); difference != "" {
+			return difference
+		}
+	}
+
+	if expected.Type() == hashType {
+		return diffPairs(path, expected.Addr().Interface().(*ast.Hash), actual.Addr().Interface().(*ast.Hash))
+	}
+
+	return ""
+}
+
+// diffPairs compares hash values in key order, Pairs is keyed by the key nodes
+// themselves so the two maps never share keys.
+func diffPairs(path string, expected, actual *ast.Hash) string {
+	if len(expected.Pairs) != len(actual.Pairs) {
+		return fmt.Sprintf("%s.Pairs: expected %d pairs, got %d", path, len(expected.Pairs), len(actual.Pairs))
+	}
+
+	for i, key := range expected.Keys {
+		expectedValue := expected.Pairs[key]
+		actualValue := actual.Pairs[actual.Keys[i]]
+		if difference := diff(fmt.Sprintf("%s.Pairs[%d]", path, i), reflect.ValueOf(&expectedValue).Elem(), reflect.ValueOf(&actualValue).Elem()); difference != "" {
+			return difference
+		}
+	}
+
+	return ""
+}
+
+func rootName(expected, actual ast.Node) string {
+	if expected == nil {
+		return "node"
+	}
+
+	return reflect.TypeOf(expected).Elem().Name()
+}
+
+func typeName(value reflect.Value) string {
+	if value.IsNil() {
+		return "nil"
+	}
+
+	return value.Elem().Type().String()
+}
+
+func nilOrType(value reflect.Value) string {
+	if value.IsNil() {
+		return "nil"
+	}
+
+	return value.Type().String()
+}
+
+func nodeString(node ast.Node) string {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return "<nil>"
+	}
+
+	return node.String()
+}
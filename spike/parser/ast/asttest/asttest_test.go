@@ -0,0 +1,74 @@
+package asttest
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/parser/ast/astbuilder"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	b := astbuilder.B
+	positioned := b.Int(5)
+	positioned.Token.Position = lexer.Position{Line: 2, Column: 3, Offset: 7}
+
+	testCases := map[string]struct {
+		expected ast.Node
+		actual   ast.Node
+		diff     string
+	}{
+		"equal ignoring positions": {
+			expected: b.Program(b.Let("x", b.Int(5))),
+			actual:   b.Program(b.Let("x", positioned)),
+			diff:     "",
+		},
+		"different literal": {
+			expected: b.Program(b.Expr(b.Infix(b.Int(1), "+", b.Int(2)))),
+			actual:   b.Program(b.Expr(b.Infix(b.Int(1), "+", b.Int(3)))),
+			diff:     "Program.Statements[0].Expression.Right.Token.Literal: expected \"2\", got \"3\"",
+		},
+		"different node type": {
+			expected: b.Program(b.Expr(b.Prefix("-", b.Int(1)))),
+			actual:   b.Program(b.Expr(b.Prefix("-", b.Ident("a")))),
+			diff:     "Program.Statements[0].Expression.Right: expected *ast.Integer, got *ast.Identifier",
+		},
+		"missing element": {
+			expected: b.Array(b.Int(1), b.Int(2)),
+			actual:   b.Array(b.Int(1)),
+			diff:     "Array.Elements: expected 2 elements, got 1",
+		},
+		"missing else": {
+			expected: b.IfElse(b.Bool(true), b.Block(), b.Block()),
+			actual:   b.If(b.Bool(true), b.Block()),
+			diff:     "IfExpression.Else: expected *ast.BlockStatement, got nil",
+		},
+		"equal hashes": {
+			expected: b.Hash(b.Pair(b.Str("a"), b.Int(1))),
+			actual:   b.Hash(b.Pair(b.Str("a"), b.Int(1))),
+			diff:     "",
+		},
+		"different hash value": {
+			expected: b.Hash(b.Pair(b.Str("a"), b.Int(1)), b.Pair(b.Str("b"), b.Int(2))),
+			actual:   b.Hash(b.Pair(b.Str("a"), b.Int(1)), b.Pair(b.Str("b"), b.Str("2"))),
+			diff:     "Hash.Pairs[1]: expected *ast.Integer, got *ast.String",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.diff, Diff(testCase.expected, testCase.actual))
+		})
+	}
+}
+
+func Test_AssertEqual_reportsMismatch(t *testing.T) {
+	b := astbuilder.B
+	recorder := &testing.T{}
+
+	equal := AssertEqual(recorder, b.Int(1), b.Int(2))
+
+	assert.False(t, equal)
+	assert.True(t, recorder.Failed())
+}
@@ -9,6 +9,7 @@ import (
 type BlockStatement struct {
 	Token      lexer.Token
 	Statements []Statement
+	Closing    lexer.Token
 }
 
 func (block *BlockStatement) statement() {}
@@ -17,6 +18,14 @@ func (block *BlockStatement) TokenLiteral() string {
 	return block.Token.Literal
 }
 
+func (block *BlockStatement) Pos() lexer.Position {
+	return block.Token.Position
+}
+
+func (block *BlockStatement) End() lexer.Position {
+	return block.Closing.End()
+}
+
 func (block *BlockStatement) String() string {
 	out := strings.Builder{}
 	out.WriteString("{\n")
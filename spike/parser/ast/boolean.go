@@ -13,6 +13,14 @@ func (boolean *Boolean) TokenLiteral() string {
 	return boolean.Token.Literal
 }
 
+func (boolean *Boolean) Pos() lexer.Position {
+	return boolean.Token.Position
+}
+
+func (boolean *Boolean) End() lexer.Position {
+	return boolean.Token.End()
+}
+
 func (boolean *Boolean) String() string {
 	if boolean.Value {
 		return "true"
@@ -9,12 +9,21 @@ type CallExpression struct {
 	Token     lexer.Token
 	Function  Expression
 	Arguments []Expression
+	Closing   lexer.Token
 }
 
 func (call *CallExpression) TokenLiteral() string {
 	return call.Token.Literal
 }
 
+func (call *CallExpression) Pos() lexer.Position {
+	return call.Function.Pos()
+}
+
+func (call *CallExpression) End() lexer.Position {
+	return call.Closing.End()
+}
+
 func (call *CallExpression) String() string {
 	out := strings.Builder{}
 
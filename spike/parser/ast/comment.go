@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
+
+// Comment is a `//` comment, kept when parsing with parser.WithComments.
+type Comment struct {
+	Token lexer.Token
+}
+
+func (comment *Comment) TokenLiteral() string {
+	return comment.Token.Literal
+}
+
+func (comment *Comment) Pos() lexer.Position {
+	return comment.Token.Position
+}
+
+func (comment *Comment) End() lexer.Position {
+	return comment.Token.End()
+}
+
+func (comment *Comment) String() string {
+	return comment.Token.Literal
+}
+
+// Text returns the comment without the leading `//` and surrounding spaces.
+func (comment *Comment) Text() string {
+	return strings.TrimSpace(strings.TrimPrefix(comment.Token.Literal, "//"))
+}
+
+// Comments are attached to a statement: Leading are the comments between the
+// previous statement and this one, Trailing is a comment following the
+// statement on the line it ends on.
+type Comments struct {
+	Leading  []*Comment
+	Trailing *Comment
+}
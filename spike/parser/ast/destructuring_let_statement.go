@@ -0,0 +1,45 @@
+package ast
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
+
+// DestructuringLetStatement binds the elements of a tuple, as in
+// `let x, y = f()`.
+type DestructuringLetStatement struct {
+	Token lexer.Token
+	Names []*Identifier
+	Value Expression
+}
+
+func (let *DestructuringLetStatement) TokenLiteral() string {
+	return let.Token.Literal
+}
+
+func (let *DestructuringLetStatement) Pos() lexer.Position {
+	return let.Token.Position
+}
+
+func (let *DestructuringLetStatement) End() lexer.Position {
+	return let.Value.End()
+}
+
+func (let *DestructuringLetStatement) statement() {
+}
+
+func (let *DestructuringLetStatement) String() string {
+	names := make([]string, len(let.Names))
+	for i, name := range let.Names {
+		names[i] = name.String()
+	}
+
+	out := strings.Builder{}
+	out.WriteString(let.Token.Literal)
+	out.WriteString(" ")
+	out.WriteString(strings.Join(names, ", "))
+	out.WriteString(" = ")
+	out.WriteString(let.Value.String())
+
+	return out.String()
+}
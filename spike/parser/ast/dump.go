@@ -0,0 +1,244 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
+
+// attribute is a value of a node that is not a node itself, like an
+// operator.
+type attribute struct {
+	name  string
+	value interface{}
+}
+
+// child is a field holding nodes. A list may have any number of them,
+// otherwise there is one node or none.
+type child struct {
+	name  string
+	nodes []Node
+	list  bool
+}
+
+// Dump prints the tree rooted at node with one node per line, indented by
+// depth, along with where each node starts:
+//
+//	Program 1:1
+//	  statements[0]: LetStatement 1:1
+//	    name: Identifier 1:5 value="x"
+//	    value: Integer 1:9 value=1
+func Dump(node Node) string {
+	out := &strings.Builder{}
+	dump(out, "", node, 0)
+
+	return out.String()
+}
+
+func dump(out *strings.Builder, label string, node Node, depth int) {
+	out.WriteString(strings.Repeat("  ", depth))
+	if label != "" {
+		out.WriteString(label + ": ")
+	}
+	if node == nil {
+		out.WriteString("nil\n")
+		return
+	}
+
+	attributes, children := describe(node)
+	fmt.Fprintf(out, "%s %d:%d", typeName(node), node.Pos().Line, node.Pos().Column)
+	for _, attribute := range attributes {
+		fmt.Fprintf(out, " %s=%#v", attribute.name, attribute.value)
+	}
+	out.WriteString("\n")
+
+	for _, child := range children {
+		if !child.list {
+			dump(out, child.name, child.nodes[0], depth+1)
+			continue
+		}
+
+		for i, node := range child.nodes {
+			dump(out, fmt.Sprintf("%s[%d]", child.name, i), node, depth+1)
+		}
+	}
+}
+
+// MarshalJSON serializes the tree rooted at node. Every node is an object
+// with its "type", "start" and "end" positions and its fields by name.
+func MarshalJSON(node Node) ([]byte, error) {
+	return json.MarshalIndent(jsonNode(node), "", "  ")
+}
+
+func jsonNode(node Node) interface{} {
+	if node == nil {
+		return nil
+	}
+
+	result := map[string]interface{}{
+		"type":  typeName(node),
+		"start": jsonPosition(node.Pos()),
+		"end":   jsonPosition(node.End()),
+	}
+
+	attributes, children := describe(node)
+	for _, attribute := range attributes {
+		result[attribute.name] = attribute.value
+	}
+	for _, child := range children {
+		if !child.list {
+			result[child.name] = jsonNode(child.nodes[0])
+			continue
+		}
+
+		nodes := make([]interface{}, len(child.nodes))
+		for i, node := range child.nodes {
+			nodes[i] = jsonNode(node)
+		}
+		result[child.name] = nodes
+	}
+
+	return result
+}
+
+func jsonPosition(position lexer.Position) map[string]int {
+	return map[string]int{
+		"line":   position.Line,
+		"column": position.Column,
+		"offset": position.Offset,
+	}
+}
+
+func typeName(node Node) string {
+	return reflect.TypeOf(node).Elem().Name()
+}
+
+// describe lists the attributes and children of node, children in source
+// order like Walk visits them.
+func describe(node Node) ([]attribute, []child) {
+	switch node := node.(type) {
+	case *Program:
+		return nil, []child{statements("statements", node.Statements)}
+
+	case *ExpressionStatement:
+		return nil, []child{single("expression", node.Expression)}
+
+	case *BlockStatement:
+		return nil, []child{statements("statements", node.Statements)}
+
+	case *LetStatement:
+		return nil, []child{single("name", node.Name), single("value", node.Value)}
+
+	case *DestructuringLetStatement:
+		return nil, []child{identifiers("names", node.Names), single("value", node.Value)}
+
+	case *ReturnStatement:
+		return nil, []child{single("result", node.Result)}
+
+	case *EnumStatement:
+		return nil, []child{single("name", node.Name), identifiers("members", node.Members)}
+
+	case *ImportStatement:
+		return nil, []child{single("module", node.Module)}
+
+	case *Identifier:
+		return []attribute{{"value", node.Value}}, nil
+
+	case *Integer:
+		return []attribute{{"value", node.Value}}, nil
+
+	case *Float:
+		return []attribute{{"value", node.Value}}, nil
+
+	case *String:
+		return []attribute{{"value", node.Value}}, nil
+
+	case *Boolean:
+		return []attribute{{"value", node.Value}}, nil
+
+	case *PrefixExpression:
+		return []attribute{{"operator", node.Operator}}, []child{single("right", node.Right)}
+
+	case *InfixExpression:
+		return []attribute{{"operator", node.Operator}}, []child{single("left", node.Left), single("right", node.Right)}
+
+	case *AssignExpression:
+		return nil, []child{single("target", node.Target), single("value", node.Value)}
+
+	case *IfExpression:
+		return nil, []child{single("condition", node.Condition), single("then", node.Then), single("else", node.Else)}
+
+	case *FunctionExpression:
+		attributes := []attribute{{"generator", node.Generator}}
+		if node.Name != "" {
+			attributes = append(attributes, attribute{"name", node.Name})
+		}
+		return attributes, []child{identifiers("parameters", node.Parameters), single("body", node.Body)}
+
+	case *CallExpression:
+		return nil, []child{single("function", node.Function), expressions("arguments", node.Arguments)}
+
+	case *IndexExpression:
+		return nil, []child{single("array", node.Array), single("index", node.Index)}
+
+	case *MemberExpression:
+		return nil, []child{single("object", node.Object), single("member", node.Member)}
+
+	case *Array:
+		return nil, []child{expressions("elements", node.Elements)}
+
+	case *Hash:
+		values := make([]Expression, len(node.Keys))
+		for i, key := range node.Keys {
+			values[i] = node.Pairs[key]
+		}
+		return nil, []child{expressions("keys", node.Keys), expressions("values", values)}
+
+	case *Tuple:
+		return nil, []child{expressions("elements", node.Elements)}
+
+	case *YieldExpression:
+		return nil, []child{single("value", node.Value)}
+
+	default:
+		panic(fmt.Sprintf("ast.Dump: unexpected node type %T", node))
+	}
+}
+
+// single holds node, which may be a nil pointer or interface.
+func single(name string, node Node) child {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		node = nil
+	}
+
+	return child{name: name, nodes: []Node{node}}
+}
+
+func statements(name string, statements []Statement) child {
+	nodes := make([]Node, len(statements))
+	for i, statement := range statements {
+		nodes[i] = statement
+	}
+
+	return child{name: name, nodes: nodes, list: true}
+}
+
+func expressions(name string, expressions []Expression) child {
+	nodes := make([]Node, len(expressions))
+	for i, expression := range expressions {
+		nodes[i] = expression
+	}
+
+	return child{name: name, nodes: nodes, list: true}
+}
+
+func identifiers(name string, identifiers []*Identifier) child {
+	nodes := make([]Node, len(identifiers))
+	for i, identifier := range identifiers {
+		nodes[i] = identifier
+	}
+
+	return child{name: name, nodes: nodes, list: true}
+}
@@ -0,0 +1,63 @@
+package ast_test
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Dump(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader("let f = fn*(a) { yield -a };\nf(1)[0];\nreturn;"))).ParseProgram()
+	assert.NoError(t, err)
+
+	expected := `Program 1:1
+  statements[0]: LetStatement 1:1
+    name: Identifier 1:5 value="f"
+    value: FunctionExpression 1:9 generator=true name="f"
+      parameters[0]: Identifier 1:13 value="a"
+      body: BlockStatement 1:16
+        statements[0]: ExpressionStatement 1:18
+          expression: YieldExpression 1:18
+            value: PrefixExpression 1:24 operator="-"
+              right: Identifier 1:25 value="a"
+  statements[1]: ExpressionStatement 2:1
+    expression: IndexExpression 2:1
+      array: CallExpression 2:1
+        function: Identifier 2:1 value="f"
+        arguments[0]: Integer 2:3 value=1
+      index: Integer 2:6 value=0
+  statements[2]: ReturnStatement 3:1
+    result: nil
+`
+	assert.Equal(t, expected, ast.Dump(program))
+}
+
+func Test_MarshalJSON(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`{"a": true}`))).ParseProgram()
+	assert.NoError(t, err)
+
+	serialized, err := ast.MarshalJSON(program.Statements[0].(*ast.ExpressionStatement).Expression)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "Hash",
+		"start": {"line": 1, "column": 1, "offset": 0},
+		"end": {"line": 1, "column": 12, "offset": 11},
+		"keys": [{
+			"type": "String",
+			"start": {"line": 1, "column": 2, "offset": 1},
+			"end": {"line": 1, "column": 5, "offset": 4},
+			"value": "a"
+		}],
+		"values": [{
+			"type": "Boolean",
+			"start": {"line": 1, "column": 7, "offset": 6},
+			"end": {"line": 1, "column": 11, "offset": 10},
+			"value": true
+		}]
+	}`, string(serialized))
+}
@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
+
+type EnumStatement struct {
+	Token   lexer.Token
+	Name    *Identifier
+	Members []*Identifier
+	Closing lexer.Token
+}
+
+func (enum *EnumStatement) TokenLiteral() string {
+	return enum.Token.Literal
+}
+
+func (enum *EnumStatement) Pos() lexer.Position {
+	return enum.Token.Position
+}
+
+func (enum *EnumStatement) End() lexer.Position {
+	return enum.Closing.End()
+}
+
+func (enum *EnumStatement) statement() {
+}
+
+func (enum *EnumStatement) String() string {
+	members := make([]string, len(enum.Members))
+	for i, member := range enum.Members {
+		members[i] = member.String()
+	}
+
+	out := strings.Builder{}
+	out.WriteString("enum ")
+	out.WriteString(enum.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(members, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
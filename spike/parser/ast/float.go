@@ -0,0 +1,27 @@
+package ast
+
+import "spike-interpreter-go/spike/lexer"
+
+// Float keeps the literal as written in Token, String prints it unchanged.
+type Float struct {
+	Token lexer.Token
+	Value float64
+}
+
+func (float *Float) TokenLiteral() string {
+	return float.Token.Literal
+}
+
+func (float *Float) Pos() lexer.Position {
+	return float.Token.Position
+}
+
+func (float *Float) End() lexer.Position {
+	return float.Token.End()
+}
+
+func (float *Float) expression() {}
+
+func (float *Float) String() string {
+	return float.Token.Literal
+}
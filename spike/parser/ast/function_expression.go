@@ -9,6 +9,8 @@ type FunctionExpression struct {
 	Token      lexer.Token
 	Parameters []*Identifier
 	Body       Statement
+	Name       string
+	Generator  bool
 }
 
 func (function *FunctionExpression) expression() {}
@@ -17,10 +19,21 @@ func (function *FunctionExpression) TokenLiteral() string {
 	return function.Token.Literal
 }
 
+func (function *FunctionExpression) Pos() lexer.Position {
+	return function.Token.Position
+}
+
+func (function *FunctionExpression) End() lexer.Position {
+	return function.Body.End()
+}
+
 func (function *FunctionExpression) String() string {
 	out := strings.Builder{}
 
 	out.WriteString(function.Token.Literal)
+	if function.Generator {
+		out.WriteString("*")
+	}
 	out.WriteString(" (")
 	for i, parameter := range function.Parameters {
 		out.WriteString(parameter.String())
@@ -35,3 +48,24 @@ func (function *FunctionExpression) String() string {
 
 	return out.String()
 }
+
+// Doc returns the string literal the body starts with, if any, which documents
+// the function.
+func (function *FunctionExpression) Doc() string {
+	block, ok := function.Body.(*BlockStatement)
+	if !ok || len(block.Statements) == 0 {
+		return ""
+	}
+
+	statement, ok := block.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return ""
+	}
+
+	doc, ok := statement.Expression.(*String)
+	if !ok {
+		return ""
+	}
+
+	return doc.Value
+}
@@ -2,34 +2,42 @@ package ast
 
 import (
 	"fmt"
-	"sort"
 	"spike-interpreter-go/spike/lexer"
 	"strings"
 )
 
+// Hash keeps the keys of Pairs in source order in Keys.
 type Hash struct {
-	Token lexer.Token
-	Pairs map[Expression]Expression
+	Token   lexer.Token
+	Pairs   map[Expression]Expression
+	Keys    []Expression
+	Closing lexer.Token
 }
 
 func (hash *Hash) TokenLiteral() string {
 	return hash.Token.Literal
 }
 
+func (hash *Hash) Pos() lexer.Position {
+	return hash.Token.Position
+}
+
+func (hash *Hash) End() lexer.Position {
+	return hash.Closing.End()
+}
+
 func (hash *Hash) String() string {
 	out := strings.Builder{}
 
 	pairs := make([]string, 0, len(hash.Pairs))
-	for key, val := range hash.Pairs {
+	for _, key := range hash.Keys {
 		pairs = append(pairs, fmt.Sprintf(
 			"%s: %s",
 			key.String(),
-			val.String(),
+			hash.Pairs[key].String(),
 		))
 	}
 
-	sort.Strings(pairs)
-
 	out.WriteString(fmt.Sprintf(
 		"{%s}",
 		strings.Join(pairs, ", "),
@@ -11,6 +11,14 @@ func (identifier *Identifier) TokenLiteral() string {
 	return identifier.Token.Literal
 }
 
+func (identifier *Identifier) Pos() lexer.Position {
+	return identifier.Token.Position
+}
+
+func (identifier *Identifier) End() lexer.Position {
+	return identifier.Token.End()
+}
+
 func (identifier *Identifier) expression() {}
 
 func (identifier *Identifier) String() string {
@@ -18,6 +18,18 @@ func (expression *IfExpression) TokenLiteral() string {
 	return expression.Token.Literal
 }
 
+func (expression *IfExpression) Pos() lexer.Position {
+	return expression.Token.Position
+}
+
+func (expression *IfExpression) End() lexer.Position {
+	if expression.Else != nil {
+		return expression.Else.End()
+	}
+
+	return expression.Then.End()
+}
+
 func (expression *IfExpression) String() string {
 	out := strings.Builder{}
 	out.WriteString("if ")
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"spike-interpreter-go/spike/lexer"
+)
+
+// ImportStatement binds the module registered by the embedder under the name
+// Module, as in `import "db"`.
+type ImportStatement struct {
+	Token  lexer.Token
+	Module *String
+}
+
+func (statement *ImportStatement) TokenLiteral() string {
+	return statement.Token.Literal
+}
+
+func (statement *ImportStatement) Pos() lexer.Position {
+	return statement.Token.Position
+}
+
+func (statement *ImportStatement) End() lexer.Position {
+	return statement.Module.End()
+}
+
+func (statement *ImportStatement) statement() {
+}
+
+func (statement *ImportStatement) String() string {
+	return "import " + statement.Module.String()
+}
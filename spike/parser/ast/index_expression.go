@@ -7,15 +7,24 @@ import (
 )
 
 type IndexExpression struct {
-	Token lexer.Token
-	Array Expression
-	Index Expression
+	Token   lexer.Token
+	Array   Expression
+	Index   Expression
+	Closing lexer.Token
 }
 
 func (index *IndexExpression) TokenLiteral() string {
 	return index.Token.Literal
 }
 
+func (index *IndexExpression) Pos() lexer.Position {
+	return index.Array.Pos()
+}
+
+func (index *IndexExpression) End() lexer.Position {
+	return index.Closing.End()
+}
+
 func (index *IndexExpression) String() string {
 	out := strings.Builder{}
 
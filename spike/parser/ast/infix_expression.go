@@ -18,6 +18,14 @@ func (expression *InfixExpression) TokenLiteral() string {
 	return expression.Token.Literal
 }
 
+func (expression *InfixExpression) Pos() lexer.Position {
+	return expression.Left.Pos()
+}
+
+func (expression *InfixExpression) End() lexer.Position {
+	return expression.Right.End()
+}
+
 func (expression *InfixExpression) String() string {
 	out := strings.Builder{}
 	out.WriteString("(")
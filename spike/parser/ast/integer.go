@@ -14,6 +14,14 @@ func (integer *Integer) TokenLiteral() string {
 	return integer.Token.Literal
 }
 
+func (integer *Integer) Pos() lexer.Position {
+	return integer.Token.Position
+}
+
+func (integer *Integer) End() lexer.Position {
+	return integer.Token.End()
+}
+
 func (integer *Integer) expression() {}
 
 func (integer *Integer) String() string {
@@ -15,6 +15,14 @@ func (let *LetStatement) TokenLiteral() string {
 	return let.Token.Literal
 }
 
+func (let *LetStatement) Pos() lexer.Position {
+	return let.Token.Position
+}
+
+func (let *LetStatement) End() lexer.Position {
+	return let.Value.End()
+}
+
 func (let *LetStatement) statement() {
 }
 
@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/lexer"
+)
+
+// MemberExpression is a dotted access such as `Color.Red`.
+type MemberExpression struct {
+	Token  lexer.Token
+	Object Expression
+	Member *Identifier
+}
+
+func (member *MemberExpression) TokenLiteral() string {
+	return member.Token.Literal
+}
+
+func (member *MemberExpression) Pos() lexer.Position {
+	return member.Object.Pos()
+}
+
+func (member *MemberExpression) End() lexer.Position {
+	return member.Member.End()
+}
+
+func (member *MemberExpression) String() string {
+	return fmt.Sprintf("(%s.%s)", member.Object.String(), member.Member.String())
+}
+
+func (member *MemberExpression) expression() {}
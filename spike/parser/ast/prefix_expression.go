@@ -17,6 +17,14 @@ func (expression *PrefixExpression) TokenLiteral() string {
 	return expression.Token.Literal
 }
 
+func (expression *PrefixExpression) Pos() lexer.Position {
+	return expression.Token.Position
+}
+
+func (expression *PrefixExpression) End() lexer.Position {
+	return expression.Right.End()
+}
+
 func (expression *PrefixExpression) String() string {
 	out := strings.Builder{}
 	out.WriteString("(")
@@ -1,19 +1,54 @@
 package ast
 
-import "strings"
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
 
+// Program lists the comments of the source in Comments and the ones attached
+// to statements, including nested ones, in Attached. Both are only set when
+// parsing with comments.
 type Program struct {
 	Statements []Statement
+	Comments   []*Comment
+	Attached   map[Statement]*Comments
 }
 
 func (program *Program) TokenLiteral() string {
 	return "program"
 }
 
+func (program *Program) Pos() lexer.Position {
+	if len(program.Statements) == 0 {
+		return lexer.Position{}
+	}
+
+	return program.Statements[0].Pos()
+}
+
+func (program *Program) End() lexer.Position {
+	if len(program.Statements) == 0 {
+		return lexer.Position{}
+	}
+
+	return program.Statements[len(program.Statements)-1].End()
+}
+
 func (program *Program) AddStatement(statement Statement) {
 	program.Statements = append(program.Statements, statement)
 }
 
+// EndsWithExpression reports whether the last statement is an expression,
+// whose value is then the value of the program.
+func (program *Program) EndsWithExpression() bool {
+	if len(program.Statements) == 0 {
+		return false
+	}
+
+	_, ok := program.Statements[len(program.Statements)-1].(*ExpressionStatement)
+	return ok
+}
+
 func (program *Program) String() string {
 	out := strings.Builder{}
 
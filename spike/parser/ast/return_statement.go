@@ -5,6 +5,8 @@ import (
 	"strings"
 )
 
+// ReturnStatement leaves its function with Result, which is nil for a bare
+// `return`.
 type ReturnStatement struct {
 	Token  lexer.Token
 	Result Expression
@@ -14,10 +16,26 @@ func (returnStatement *ReturnStatement) TokenLiteral() string {
 	return returnStatement.Token.Literal
 }
 
+func (returnStatement *ReturnStatement) Pos() lexer.Position {
+	return returnStatement.Token.Position
+}
+
+func (returnStatement *ReturnStatement) End() lexer.Position {
+	if returnStatement.Result == nil {
+		return returnStatement.Token.End()
+	}
+
+	return returnStatement.Result.End()
+}
+
 func (returnStatement *ReturnStatement) statement() {
 }
 
 func (returnStatement *ReturnStatement) String() string {
+	if returnStatement.Result == nil {
+		return "return"
+	}
+
 	out := strings.Builder{}
 	out.WriteString("return ")
 	out.WriteString(returnStatement.Result.String())
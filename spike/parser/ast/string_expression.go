@@ -14,6 +14,14 @@ func (str *String) TokenLiteral() string {
 	return str.Token.Literal
 }
 
+func (str *String) Pos() lexer.Position {
+	return str.Token.Position
+}
+
+func (str *String) End() lexer.Position {
+	return str.Token.End()
+}
+
 func (str *String) String() string {
 	return fmt.Sprintf("\"%s\"", str.Value)
 }
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"strings"
+)
+
+// Tuple is the comma separated list of values in `return a, b`.
+type Tuple struct {
+	Token    lexer.Token
+	Elements []Expression
+}
+
+func (tuple *Tuple) TokenLiteral() string {
+	return tuple.Token.Literal
+}
+
+func (tuple *Tuple) Pos() lexer.Position {
+	return tuple.Elements[0].Pos()
+}
+
+func (tuple *Tuple) End() lexer.Position {
+	return tuple.Elements[len(tuple.Elements)-1].End()
+}
+
+func (tuple *Tuple) String() string {
+	elements := make([]string, len(tuple.Elements))
+	for i, element := range tuple.Elements {
+		elements[i] = element.String()
+	}
+
+	return strings.Join(elements, ", ")
+}
+
+func (tuple *Tuple) expression() {
+}
@@ -0,0 +1,141 @@
+package ast
+
+import "fmt"
+
+// Visitor is called by Walk for every node. If Visit returns a non-nil
+// visitor w, Walk visits the children of node with w and then calls
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node depth-first, visiting children in
+// source order. Comments are not part of the tree and are not visited.
+func Walk(visitor Visitor, node Node) {
+	if visitor = visitor.Visit(node); visitor == nil {
+		return
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		walkStatements(visitor, node.Statements)
+
+	case *ExpressionStatement:
+		Walk(visitor, node.Expression)
+
+	case *BlockStatement:
+		walkStatements(visitor, node.Statements)
+
+	case *LetStatement:
+		Walk(visitor, node.Name)
+		Walk(visitor, node.Value)
+
+	case *DestructuringLetStatement:
+		for _, name := range node.Names {
+			Walk(visitor, name)
+		}
+		Walk(visitor, node.Value)
+
+	case *ReturnStatement:
+		if node.Result != nil {
+			Walk(visitor, node.Result)
+		}
+
+	case *EnumStatement:
+		Walk(visitor, node.Name)
+		for _, member := range node.Members {
+			Walk(visitor, member)
+		}
+
+	case *ImportStatement:
+		Walk(visitor, node.Module)
+
+	case *Identifier, *Integer, *Float, *String, *Boolean:
+		// leaves
+
+	case *PrefixExpression:
+		Walk(visitor, node.Right)
+
+	case *InfixExpression:
+		Walk(visitor, node.Left)
+		Walk(visitor, node.Right)
+
+	case *AssignExpression:
+		Walk(visitor, node.Target)
+		Walk(visitor, node.Value)
+
+	case *IfExpression:
+		Walk(visitor, node.Condition)
+		Walk(visitor, node.Then)
+		if node.Else != nil {
+			Walk(visitor, node.Else)
+		}
+
+	case *FunctionExpression:
+		for _, parameter := range node.Parameters {
+			Walk(visitor, parameter)
+		}
+		Walk(visitor, node.Body)
+
+	case *CallExpression:
+		Walk(visitor, node.Function)
+		walkExpressions(visitor, node.Arguments)
+
+	case *IndexExpression:
+		Walk(visitor, node.Array)
+		Walk(visitor, node.Index)
+
+	case *MemberExpression:
+		Walk(visitor, node.Object)
+		Walk(visitor, node.Member)
+
+	case *Array:
+		walkExpressions(visitor, node.Elements)
+
+	case *Hash:
+		for _, key := range node.Keys {
+			Walk(visitor, key)
+			Walk(visitor, node.Pairs[key])
+		}
+
+	case *Tuple:
+		walkExpressions(visitor, node.Elements)
+
+	case *YieldExpression:
+		Walk(visitor, node.Value)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", node))
+	}
+
+	visitor.Visit(nil)
+}
+
+func walkStatements(visitor Visitor, statements []Statement) {
+	for _, statement := range statements {
+		Walk(visitor, statement)
+	}
+}
+
+func walkExpressions(visitor Visitor, expressions []Expression) {
+	for _, expression := range expressions {
+		Walk(visitor, expression)
+	}
+}
+
+type inspector func(Node) bool
+
+func (inspect inspector) Visit(node Node) Visitor {
+	if inspect(node) {
+		return inspect
+	}
+
+	return nil
+}
+
+// Inspect traverses the tree like Walk, calling f for every node and, after
+// its children, with nil. Children of a node are skipped when f returns false
+// for it.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,84 @@
+package ast_test
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/parser/ast/astbuilder"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Inspect(t *testing.T) {
+	b := astbuilder.B
+	program := b.Program(
+		b.Enum("Color", "Red"),
+		b.DestructuringLet(b.Call(b.Ident("f")), "a", "b"),
+		b.Let("f", b.Fn([]string{"x"}, b.Block(
+			b.Return(b.Tuple(b.Member(b.Ident("Color"), "Red"), b.Prefix("-", b.Ident("x")))),
+		))),
+		b.Expr(b.IfElse(
+			b.Infix(b.Index(b.Array(b.Int(1)), b.Int(0)), "<", b.Int(2)),
+			b.Block(b.Expr(b.Hash(b.Pair(b.Str("k"), b.Bool(true))))),
+			b.Block(),
+		)),
+	)
+	expected := []string{
+		"*ast.Program",
+		"*ast.EnumStatement", "Color", "Red",
+		"*ast.DestructuringLetStatement", "a", "b", "*ast.CallExpression", "f",
+		"*ast.LetStatement", "f", "*ast.FunctionExpression", "x", "*ast.BlockStatement",
+		"*ast.ReturnStatement", "*ast.Tuple", "*ast.MemberExpression", "Color", "Red", "*ast.PrefixExpression", "x",
+		"*ast.ExpressionStatement", "*ast.IfExpression", "*ast.InfixExpression", "*ast.IndexExpression",
+		"*ast.Array", "1", "0", "2",
+		"*ast.BlockStatement", "*ast.ExpressionStatement", "*ast.Hash", `"k"`, "true",
+		"*ast.BlockStatement",
+	}
+
+	visited := []string{}
+	depth, maxDepth := 0, 0
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node == nil {
+			depth--
+			return false
+		}
+
+		depth++
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+
+		switch node := node.(type) {
+		case *ast.Identifier, *ast.Integer, *ast.String, *ast.Boolean:
+			visited = append(visited, node.String())
+		default:
+			visited = append(visited, fmt.Sprintf("%T", node))
+		}
+
+		return true
+	})
+
+	assert.Equal(t, expected, visited)
+	assert.Equal(t, 0, depth)
+	assert.Equal(t, 8, maxDepth)
+}
+
+func Test_Inspect_skipsChildren(t *testing.T) {
+	b := astbuilder.B
+	program := b.Program(
+		b.Let("f", b.Fn(nil, b.Block(b.Expr(b.Ident("inner"))))),
+		b.Expr(b.Ident("outer")),
+	)
+
+	identifiers := []string{}
+	ast.Inspect(program, func(node ast.Node) bool {
+		if identifier, ok := node.(*ast.Identifier); ok {
+			identifiers = append(identifiers, identifier.Value)
+		}
+
+		_, isFunction := node.(*ast.FunctionExpression)
+		return !isFunction
+	})
+
+	assert.Equal(t, []string{"f", "outer"}, identifiers)
+}
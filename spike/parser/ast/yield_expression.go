@@ -0,0 +1,28 @@
+package ast
+
+import "spike-interpreter-go/spike/lexer"
+
+// YieldExpression suspends the enclosing generator function, handing Value
+// to whoever asked the generator for its next value.
+type YieldExpression struct {
+	Token lexer.Token
+	Value Expression
+}
+
+func (yield *YieldExpression) TokenLiteral() string {
+	return yield.Token.Literal
+}
+
+func (yield *YieldExpression) Pos() lexer.Position {
+	return yield.Token.Position
+}
+
+func (yield *YieldExpression) End() lexer.Position {
+	return yield.Value.End()
+}
+
+func (yield *YieldExpression) String() string {
+	return "yield " + yield.Value.String()
+}
+
+func (yield *YieldExpression) expression() {}
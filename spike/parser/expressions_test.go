@@ -21,6 +21,14 @@ func Test_prefix_expressions(t *testing.T) {
 			input:           "10;",
 			expectedProgram: "10\n",
 		},
+		"float": {
+			input:           "2.5e-3;",
+			expectedProgram: "2.5e-3\n",
+		},
+		"negate float": {
+			input:           "-3.14;",
+			expectedProgram: "(-3.14)\n",
+		},
 		"true keyword": {
 			input:           "true;",
 			expectedProgram: "true\n",
@@ -141,11 +149,11 @@ func Test_invalid_expressions(t *testing.T) {
 	}{
 		"let after minus operator": {
 			code:          `-let;`,
-			expectedError: `"let" is not a valid prefix expression`,
+			expectedError: `"let" is not a valid prefix expression at 1:2`,
 		},
 		"return after minus operator": {
 			code:          `-return;`,
-			expectedError: `"return" is not a valid prefix expression`,
+			expectedError: `"return" is not a valid prefix expression at 1:2`,
 		},
 	}
 
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser/ast"
+	"strings"
+	"testing"
+)
+
+func FuzzParser(f *testing.F) {
+	for _, seed := range []string{
+		"let add = fn(a, b) { a + b }; add(1, 2)",
+		"let x, y = f(); x.y[0] = {\"a\": [1, 2]}",
+		"enum Color { Red, Green }; import \"db\"; if (a) { b } else { c }",
+		"let g = fn*() { yield 1; return; };",
+		"// comment\nlet = ;",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		program, err := New(lexer.New(strings.NewReader(input), lexer.WithComments()), WithComments()).ParseProgram()
+		if err != nil {
+			return
+		}
+
+		_ = program.String()
+		ast.Inspect(program, func(node ast.Node) bool {
+			if node != nil {
+				node.Pos()
+				node.End()
+			}
+			return true
+		})
+	})
+}
@@ -1,11 +1,11 @@
 package parser
 
 import (
+	"spike-interpreter-go/spike/diagnostic"
 	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/parser/ast"
 	"strconv"
-
-	"github.com/pkg/errors"
+	"strings"
 )
 
 type prefixParseFunc func() (ast.Expression, error)
@@ -13,6 +13,7 @@ type infixParseFunc func(expression ast.Expression) (ast.Expression, error)
 
 const (
 	lowest = iota
+	assign
 	alternative
 	conjunction
 	inequality
@@ -25,6 +26,7 @@ const (
 )
 
 var precedences = map[lexer.TokenType]int{
+	lexer.Assign:          assign,
 	lexer.Plus:            sum,
 	lexer.Minus:           sum,
 	lexer.Asterisk:        product,
@@ -39,6 +41,7 @@ var precedences = map[lexer.TokenType]int{
 	lexer.Or:              alternative,
 	lexer.LeftParenthesis: call,
 	lexer.LeftBracket:     index,
+	lexer.Dot:             index,
 }
 
 type Parser struct {
@@ -47,15 +50,39 @@ type Parser struct {
 	peekToken     lexer.Token
 	prefixParsers map[lexer.TokenType]prefixParseFunc
 	infixParsers  map[lexer.TokenType]infixParseFunc
+	inGenerator   bool
+	lexerError    error
+
+	keepComments    bool
+	comments        []*ast.Comment
+	attached        map[ast.Statement]*ast.Comments
+	currentComments []*ast.Comment
+	peekComments    []*ast.Comment
+}
+
+type Option func(parser *Parser)
+
+// WithComments keeps the comments in the parsed program and attaches them to
+// statements, see ast.Program. The lexer has to be created with
+// lexer.WithComments for there to be any.
+func WithComments() Option {
+	return func(parser *Parser) {
+		parser.keepComments = true
+		parser.attached = make(map[ast.Statement]*ast.Comments)
+	}
 }
 
-func New(lexerInstance *lexer.Lexer) *Parser {
+func New(lexerInstance *lexer.Lexer, options ...Option) *Parser {
 	parser := &Parser{lexerInstance: lexerInstance}
+	for _, option := range options {
+		option(parser)
+	}
 	parser.prefixParsers = make(map[lexer.TokenType]prefixParseFunc)
 	parser.infixParsers = make(map[lexer.TokenType]infixParseFunc)
 
 	parser.addPrefixParser(lexer.Identifier, parser.parseIdentifier)
 	parser.addPrefixParser(lexer.Integer, parser.parseInteger)
+	parser.addPrefixParser(lexer.Float, parser.parseFloat)
 	parser.addPrefixParser(lexer.True, parser.parseBoolean)
 	parser.addPrefixParser(lexer.False, parser.parseBoolean)
 	parser.addPrefixParser(lexer.Bang, parser.parsePrefixExpression)
@@ -66,6 +93,7 @@ func New(lexerInstance *lexer.Lexer) *Parser {
 	parser.addPrefixParser(lexer.String, parser.parseString)
 	parser.addPrefixParser(lexer.LeftBracket, parser.parseArray)
 	parser.addPrefixParser(lexer.LeftBrace, parser.parseHash)
+	parser.addPrefixParser(lexer.Yield, parser.parseYieldExpression)
 
 	parser.addInfixParser(lexer.Plus, parser.parseInfixExpression)
 	parser.addInfixParser(lexer.Asterisk, parser.parseInfixExpression)
@@ -81,6 +109,8 @@ func New(lexerInstance *lexer.Lexer) *Parser {
 	parser.addInfixParser(lexer.And, parser.parseInfixExpression)
 	parser.addInfixParser(lexer.LeftParenthesis, parser.parseCallExpression)
 	parser.addInfixParser(lexer.LeftBracket, parser.parseIndexExpression)
+	parser.addInfixParser(lexer.Dot, parser.parseMemberExpression)
+	parser.addInfixParser(lexer.Assign, parser.parseAssignExpression)
 
 	return parser
 }
@@ -90,8 +120,19 @@ func (parser *Parser) ParseProgram() (*ast.Program, error) {
 
 	parser.advanceToken()
 
+	if parser.keepComments {
+		defer func() {
+			program.Comments = parser.comments
+			program.Attached = parser.attached
+		}()
+	}
+
 	for parser.advanceToken(); parser.currentToken.Type != lexer.Eof; parser.advanceToken() {
+		leading := parser.currentComments
 		statement, err := parser.parseStatement()
+		if parser.lexerError != nil {
+			return program, parser.lexerError
+		}
 		if err != nil {
 			return program, err
 		}
@@ -101,9 +142,45 @@ func (parser *Parser) ParseProgram() (*ast.Program, error) {
 		if parser.peekToken.Type == lexer.Semicolon {
 			parser.advanceToken()
 		}
+		parser.attachComments(statement, leading)
+	}
+
+	return program, parser.lexerError
+}
+
+// ParseExpression parses source consisting of a single expression, optionally
+// followed by a semicolon.
+func ParseExpression(source string) (ast.Expression, error) {
+	return New(lexer.New(strings.NewReader(source))).ParseExpression()
+}
+
+// ParseExpression parses the whole input as a single expression, optionally
+// followed by a semicolon.
+func (parser *Parser) ParseExpression() (ast.Expression, error) {
+	parser.advanceToken()
+	parser.advanceToken()
+
+	expression, err := parser.parseExpression(lowest)
+	if parser.lexerError != nil {
+		return nil, parser.lexerError
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parser.advanceToken()
+	if parser.currentToken.Type == lexer.Semicolon {
+		parser.advanceToken()
 	}
 
-	return program, nil
+	if parser.lexerError != nil {
+		return nil, parser.lexerError
+	}
+	if parser.currentToken.Type != lexer.Eof {
+		return nil, parser.errorf("expected end of expression, got %s", parser.currentToken.Type)
+	}
+
+	return expression, nil
 }
 
 func (parser *Parser) addPrefixParser(tokenType lexer.TokenType, prefixParser prefixParseFunc) {
@@ -114,9 +191,55 @@ func (parser *Parser) addInfixParser(tokenType lexer.TokenType, infixParser infi
 	parser.infixParsers[tokenType] = infixParser
 }
 
+// errorf reports a syntax error at the current token.
+func (parser *Parser) errorf(format string, args ...interface{}) error {
+	return diagnostic.NewParseError(parser.currentToken, diagnostic.InvalidSyntax, format, args...)
+}
+
+// advanceToken keeps the first lexer error, ParseProgram reports it in place
+// of any syntax error the invalid token causes. Comment tokens are skipped,
+// the ones preceding peekToken are collected in peekComments.
 func (parser *Parser) advanceToken() {
 	parser.currentToken = parser.peekToken
-	parser.peekToken, _ = parser.lexerInstance.NextToken()
+	parser.currentComments = parser.peekComments
+	parser.peekComments = nil
+
+	for {
+		token, err := parser.lexerInstance.NextToken()
+		if err != nil && parser.lexerError == nil {
+			parser.lexerError = diagnostic.FromLexer(err)
+		}
+
+		if token.Type != lexer.Comment {
+			parser.peekToken = token
+			return
+		}
+
+		if parser.keepComments {
+			comment := &ast.Comment{Token: token}
+			parser.comments = append(parser.comments, comment)
+			parser.peekComments = append(parser.peekComments, comment)
+		}
+	}
+}
+
+// attachComments attaches the comments preceding statement and a comment
+// following it on the same line. It is called once the statement, including
+// its semicolon, has been parsed.
+func (parser *Parser) attachComments(statement ast.Statement, leading []*ast.Comment) {
+	if !parser.keepComments {
+		return
+	}
+
+	comments := &ast.Comments{Leading: leading}
+	if len(parser.peekComments) > 0 && parser.peekComments[0].Pos().Line == parser.currentToken.Line {
+		comments.Trailing = parser.peekComments[0]
+		parser.peekComments = parser.peekComments[1:]
+	}
+
+	if comments.Leading != nil || comments.Trailing != nil {
+		parser.attached[statement] = comments
+	}
 }
 
 func (parser *Parser) parseStatement() (ast.Statement, error) {
@@ -125,6 +248,10 @@ func (parser *Parser) parseStatement() (ast.Statement, error) {
 		return parser.parseLetStatement()
 	case lexer.Return:
 		return parser.parseReturnStatement()
+	case lexer.Enum:
+		return parser.parseEnumStatement()
+	case lexer.Import:
+		return parser.parseImportStatement()
 	default:
 		return parser.parseExpressionStatement()
 	}
@@ -136,15 +263,19 @@ func (parser *Parser) parseLetStatement() (ast.Statement, error) {
 	parser.advanceToken()
 
 	if parser.currentToken.Type != lexer.Identifier {
-		return letStatement, errors.Errorf("expected identifier, got %s", parser.currentToken.Type)
+		return letStatement, parser.errorf("expected identifier, got %s", parser.currentToken.Type)
 	}
 
 	letStatement.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
 
 	parser.advanceToken()
 
+	if parser.currentToken.Type == lexer.Comma {
+		return parser.parseDestructuringLetStatement(letStatement)
+	}
+
 	if parser.currentToken.Type != lexer.Assign {
-		return letStatement, errors.Errorf("expected assign operator, got %s", parser.currentToken.Type)
+		return letStatement, parser.errorf("expected assign operator, got %s", parser.currentToken.Type)
 	}
 
 	parser.advanceToken()
@@ -152,15 +283,50 @@ func (parser *Parser) parseLetStatement() (ast.Statement, error) {
 	expression, err := parser.parseExpression(lowest)
 	letStatement.Value = expression
 
+	if function, ok := expression.(*ast.FunctionExpression); ok {
+		function.Name = letStatement.Name.Value
+	}
+
 	return letStatement, err
 }
 
+func (parser *Parser) parseDestructuringLetStatement(letStatement *ast.LetStatement) (ast.Statement, error) {
+	destructuring := &ast.DestructuringLetStatement{
+		Token: letStatement.Token,
+		Names: []*ast.Identifier{letStatement.Name},
+	}
+
+	for parser.currentToken.Type == lexer.Comma {
+		parser.advanceToken()
+		if parser.currentToken.Type != lexer.Identifier {
+			return destructuring, parser.errorf("expected identifier, got %s", parser.currentToken.Type)
+		}
+
+		destructuring.Names = append(
+			destructuring.Names,
+			&ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal},
+		)
+		parser.advanceToken()
+	}
+
+	if parser.currentToken.Type != lexer.Assign {
+		return destructuring, parser.errorf("expected assign operator, got %s", parser.currentToken.Type)
+	}
+
+	parser.advanceToken()
+
+	expression, err := parser.parseExpression(lowest)
+	destructuring.Value = expression
+
+	return destructuring, err
+}
+
 func (parser *Parser) parseIfExpression() (ast.Expression, error) {
 	ifExpression := &ast.IfExpression{Token: parser.currentToken}
 
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.LeftParenthesis {
-		return ifExpression, errors.Errorf("expected left parenthesis, got %s", parser.currentToken.Type)
+		return ifExpression, parser.errorf("expected left parenthesis, got %s", parser.currentToken.Type)
 	}
 
 	parser.advanceToken()
@@ -172,12 +338,12 @@ func (parser *Parser) parseIfExpression() (ast.Expression, error) {
 
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.RightParenthesis {
-		return ifExpression, errors.Errorf("expected right parenthesis, got %s", parser.currentToken.Type)
+		return ifExpression, parser.errorf("expected right parenthesis, got %s", parser.currentToken.Type)
 	}
 
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.LeftBrace {
-		return ifExpression, errors.Errorf("expected left brace, got: %s", parser.currentToken.Type)
+		return ifExpression, parser.errorf("expected left brace, got: %s", parser.currentToken.Type)
 	}
 
 	block, err := parser.parseBlockStatement()
@@ -193,7 +359,7 @@ func (parser *Parser) parseIfExpression() (ast.Expression, error) {
 	parser.advanceToken()
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.LeftBrace {
-		return ifExpression, errors.Errorf("expected left brace, got: %s", parser.currentToken.Type)
+		return ifExpression, parser.errorf("expected left brace, got: %s", parser.currentToken.Type)
 	}
 
 	block, err = parser.parseBlockStatement()
@@ -209,8 +375,13 @@ func (parser *Parser) parseFunctionExpression() (ast.Expression, error) {
 	functionExpression := &ast.FunctionExpression{Token: parser.currentToken}
 
 	parser.advanceToken()
+	if parser.currentToken.Type == lexer.Asterisk {
+		functionExpression.Generator = true
+		parser.advanceToken()
+	}
+
 	if parser.currentToken.Type != lexer.LeftParenthesis {
-		return functionExpression, errors.Errorf("expected left parenthesis, got %s", parser.currentToken.Type)
+		return functionExpression, parser.errorf("expected left parenthesis, got %s", parser.currentToken.Type)
 	}
 
 	for {
@@ -220,7 +391,7 @@ func (parser *Parser) parseFunctionExpression() (ast.Expression, error) {
 		}
 
 		if parser.currentToken.Type != lexer.Identifier {
-			return functionExpression, errors.Errorf("expected identifier, got %s", parser.currentToken.Type)
+			return functionExpression, parser.errorf("expected identifier, got %s", parser.currentToken.Type)
 		}
 
 		identifier, err := parser.parseIdentifier()
@@ -235,16 +406,19 @@ func (parser *Parser) parseFunctionExpression() (ast.Expression, error) {
 		}
 
 		if parser.currentToken.Type != lexer.Comma {
-			return functionExpression, errors.Errorf("expected comma, got %s", parser.currentToken.Type)
+			return functionExpression, parser.errorf("expected comma, got %s", parser.currentToken.Type)
 		}
 	}
 
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.LeftBrace {
-		return functionExpression, errors.Errorf("expected left brace, got: %s", parser.currentToken.Type)
+		return functionExpression, parser.errorf("expected left brace, got: %s", parser.currentToken.Type)
 	}
 
+	enclosingGenerator := parser.inGenerator
+	parser.inGenerator = functionExpression.Generator
 	block, err := parser.parseBlockStatement()
+	parser.inGenerator = enclosingGenerator
 	if err != nil {
 		return functionExpression, err
 	}
@@ -254,14 +428,57 @@ func (parser *Parser) parseFunctionExpression() (ast.Expression, error) {
 	return functionExpression, nil
 }
 
+func (parser *Parser) parseYieldExpression() (ast.Expression, error) {
+	if !parser.inGenerator {
+		return nil, parser.errorf("yield outside generator function")
+	}
+
+	yield := &ast.YieldExpression{Token: parser.currentToken}
+
+	parser.advanceToken()
+	value, err := parser.parseExpression(lowest)
+	if err != nil {
+		return nil, err
+	}
+	yield.Value = value
+
+	return yield, nil
+}
+
 func (parser *Parser) parseReturnStatement() (ast.Statement, error) {
 	returnStatement := &ast.ReturnStatement{Token: parser.currentToken}
 
+	// A bare `return` leaves Result nil.
+	switch parser.peekToken.Type {
+	case lexer.Semicolon, lexer.RightBrace, lexer.Eof:
+		return returnStatement, nil
+	}
+
 	parser.advanceToken()
 
-	expression, _ := parser.parseExpression(lowest)
+	expression, err := parser.parseExpression(lowest)
+	if err != nil {
+		return returnStatement, err
+	}
 	returnStatement.Result = expression
 
+	if parser.peekToken.Type != lexer.Comma {
+		return returnStatement, nil
+	}
+
+	tuple := &ast.Tuple{Token: parser.peekToken, Elements: []ast.Expression{expression}}
+	for parser.peekToken.Type == lexer.Comma {
+		parser.advanceToken()
+		parser.advanceToken()
+
+		element, err := parser.parseExpression(lowest)
+		if err != nil {
+			return returnStatement, err
+		}
+		tuple.Elements = append(tuple.Elements, element)
+	}
+	returnStatement.Result = tuple
+
 	return returnStatement, nil
 }
 
@@ -286,7 +503,7 @@ func (parser *Parser) parseExpression(precedence int) (ast.Expression, error) {
 	var err error
 	parsePrefixExpression, ok := parser.prefixParsers[parser.currentToken.Type]
 	if !ok {
-		return expression, errors.Errorf("%q is not a valid prefix expression", parser.currentToken.Literal)
+		return expression, parser.errorf("%q is not a valid prefix expression", parser.currentToken.Literal)
 	}
 
 	expression, err = parsePrefixExpression()
@@ -303,9 +520,12 @@ func (parser *Parser) parseExpression(precedence int) (ast.Expression, error) {
 		parser.advanceToken()
 
 		expression, err = parseInfixExpression(expression)
+		if err != nil {
+			return expression, err
+		}
 	}
 
-	return expression, err
+	return expression, nil
 }
 
 func (parser *Parser) parseIdentifier() (ast.Expression, error) {
@@ -334,8 +554,17 @@ func (parser *Parser) parseInteger() (ast.Expression, error) {
 	return expression, nil
 }
 
+func (parser *Parser) parseFloat() (ast.Expression, error) {
+	value, err := strconv.ParseFloat(parser.currentToken.Literal, 64)
+	if err != nil {
+		return nil, parser.errorf("invalid float literal %s", parser.currentToken.Literal)
+	}
+
+	return &ast.Float{Token: parser.currentToken, Value: value}, nil
+}
+
 func (parser *Parser) parseBoolean() (ast.Expression, error) {
-	if parser.currentToken == lexer.TrueToken {
+	if parser.currentToken.Type == lexer.True {
 		return &ast.Boolean{Token: parser.currentToken, Value: true}, nil
 	}
 
@@ -365,7 +594,29 @@ func (parser *Parser) parseInfixExpression(left ast.Expression) (ast.Expression,
 	precedence, _ := precedences[parser.currentToken.Type]
 
 	parser.advanceToken()
-	expression.Right, _ = parser.parseExpression(precedence)
+	right, err := parser.parseExpression(precedence)
+	expression.Right = right
+
+	return expression, err
+}
+
+// parseAssignExpression parses the value with the lowest precedence, so
+// `a = b = 1` assigns 1 to b first.
+func (parser *Parser) parseAssignExpression(target ast.Expression) (ast.Expression, error) {
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression:
+	default:
+		return nil, parser.errorf("cannot assign to %s", strings.TrimSuffix(target.String(), ";"))
+	}
+
+	expression := &ast.AssignExpression{Token: parser.currentToken, Target: target}
+
+	parser.advanceToken()
+	value, err := parser.parseExpression(lowest)
+	if err != nil {
+		return nil, err
+	}
+	expression.Value = value
 
 	return expression, nil
 }
@@ -373,9 +624,15 @@ func (parser *Parser) parseInfixExpression(left ast.Expression) (ast.Expression,
 func (parser *Parser) parseGroupedExpression() (ast.Expression, error) {
 	parser.advanceToken()
 
-	expression, _ := parser.parseExpression(lowest)
+	expression, err := parser.parseExpression(lowest)
+	if err != nil {
+		return expression, err
+	}
 
 	parser.advanceToken()
+	if parser.currentToken.Type != lexer.RightParenthesis {
+		return expression, parser.errorf("expected right parenthesis, got %s", parser.currentToken.Type)
+	}
 
 	return expression, nil
 }
@@ -387,6 +644,7 @@ func (parser *Parser) parseBlockStatement() (ast.Statement, error) {
 	}
 
 	for parser.advanceToken(); parser.currentToken.Type != lexer.RightBrace; parser.advanceToken() {
+		leading := parser.currentComments
 		statement, err := parser.parseStatement()
 		if err != nil {
 			return blockStatement, err
@@ -396,7 +654,9 @@ func (parser *Parser) parseBlockStatement() (ast.Statement, error) {
 		if parser.peekToken.Type == lexer.Semicolon {
 			parser.advanceToken()
 		}
+		parser.attachComments(statement, leading)
 	}
+	blockStatement.Closing = parser.currentToken
 
 	return blockStatement, nil
 }
@@ -413,6 +673,7 @@ func (parser *Parser) parseCallExpression(function ast.Expression) (ast.Expressi
 	}
 
 	callExpression.Arguments = callArguments
+	callExpression.Closing = parser.currentToken
 
 	return callExpression, nil
 }
@@ -439,7 +700,7 @@ func (parser *Parser) parseCallArguments() ([]ast.Expression, error) {
 		}
 
 		if parser.currentToken.Type != lexer.Comma {
-			return arguments, errors.Errorf("expected comma, got %s", parser.currentToken.Type)
+			return arguments, parser.errorf("expected comma, got %s", parser.currentToken.Type)
 		}
 	}
 
@@ -465,7 +726,7 @@ func (parser *Parser) parseHash() (ast.Expression, error) {
 
 		parser.advanceToken()
 		if parser.currentToken.Type != lexer.Colon {
-			return nil, errors.Errorf("expected colon, got: %s", parser.currentToken.Literal)
+			return nil, parser.errorf("expected colon, got: %s", parser.currentToken.Literal)
 		}
 
 		parser.advanceToken()
@@ -475,6 +736,7 @@ func (parser *Parser) parseHash() (ast.Expression, error) {
 		}
 
 		hash.Pairs[key] = val
+		hash.Keys = append(hash.Keys, key)
 
 		parser.advanceToken()
 		if parser.currentToken.Type == lexer.RightBrace {
@@ -482,9 +744,10 @@ func (parser *Parser) parseHash() (ast.Expression, error) {
 		}
 
 		if parser.currentToken.Type != lexer.Comma {
-			return nil, errors.Errorf("expected comma, got %s", parser.currentToken.Type)
+			return nil, parser.errorf("expected comma, got %s", parser.currentToken.Type)
 		}
 	}
+	hash.Closing = parser.currentToken
 
 	return hash, nil
 }
@@ -513,9 +776,10 @@ func (parser *Parser) parseArray() (ast.Expression, error) {
 		}
 
 		if parser.currentToken.Type != lexer.Comma {
-			return nil, errors.Errorf("expected comma, got %s", parser.currentToken.Type)
+			return nil, parser.errorf("expected comma, got %s", parser.currentToken.Type)
 		}
 	}
+	array.Closing = parser.currentToken
 
 	return array, nil
 }
@@ -535,8 +799,75 @@ func (parser *Parser) parseIndexExpression(array ast.Expression) (ast.Expression
 
 	parser.advanceToken()
 	if parser.currentToken.Type != lexer.RightBracket {
-		return nil, errors.Errorf("expected closing bracket, got: %s", parser.currentToken.Type)
+		return nil, parser.errorf("expected closing bracket, got: %s", parser.currentToken.Type)
 	}
+	i.Closing = parser.currentToken
 
 	return i, nil
 }
+
+func (parser *Parser) parseMemberExpression(object ast.Expression) (ast.Expression, error) {
+	member := &ast.MemberExpression{
+		Token:  parser.currentToken,
+		Object: object,
+	}
+
+	parser.advanceToken()
+	if parser.currentToken.Type != lexer.Identifier {
+		return nil, parser.errorf("expected member name, got %s", parser.currentToken.Type)
+	}
+	member.Member = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	return member, nil
+}
+
+func (parser *Parser) parseEnumStatement() (ast.Statement, error) {
+	enum := &ast.EnumStatement{Token: parser.currentToken}
+
+	parser.advanceToken()
+	if parser.currentToken.Type != lexer.Identifier {
+		return nil, parser.errorf("expected identifier, got %s", parser.currentToken.Type)
+	}
+	enum.Name = &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	parser.advanceToken()
+	if parser.currentToken.Type != lexer.LeftBrace {
+		return nil, parser.errorf("expected left brace, got %s", parser.currentToken.Type)
+	}
+
+	for {
+		parser.advanceToken()
+		if parser.currentToken.Type == lexer.RightBrace {
+			break
+		}
+
+		if parser.currentToken.Type != lexer.Identifier {
+			return nil, parser.errorf("expected identifier, got %s", parser.currentToken.Type)
+		}
+		enum.Members = append(enum.Members, &ast.Identifier{Token: parser.currentToken, Value: parser.currentToken.Literal})
+
+		parser.advanceToken()
+		if parser.currentToken.Type == lexer.RightBrace {
+			break
+		}
+
+		if parser.currentToken.Type != lexer.Comma {
+			return nil, parser.errorf("expected comma, got %s", parser.currentToken.Type)
+		}
+	}
+	enum.Closing = parser.currentToken
+
+	return enum, nil
+}
+
+func (parser *Parser) parseImportStatement() (ast.Statement, error) {
+	statement := &ast.ImportStatement{Token: parser.currentToken}
+
+	parser.advanceToken()
+	if parser.currentToken.Type != lexer.String {
+		return nil, parser.errorf("expected module name, got %s", parser.currentToken.Type)
+	}
+	statement.Module = &ast.String{Token: parser.currentToken, Value: parser.currentToken.Literal}
+
+	return statement, nil
+}
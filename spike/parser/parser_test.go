@@ -3,6 +3,8 @@ package parser
 import (
 	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/parser/ast/astbuilder"
+	"spike-interpreter-go/spike/parser/ast/asttest"
 	"strings"
 	"testing"
 
@@ -18,13 +20,21 @@ func Test_Parser_parseValidCode(t *testing.T) {
 			code: `let variable = 10;`,
 			expectedProgram: &ast.Program{Statements: []ast.Statement{
 				&ast.LetStatement{
-					Token: lexer.Token{Type: lexer.Let, Literal: "let"},
+					Token: lexer.Token{Type: lexer.Let, Literal: "let", Position: lexer.Position{Line: 1, Column: 1}},
 					Name: &ast.Identifier{
-						Token: lexer.Token{Type: lexer.Identifier, Literal: "variable"},
+						Token: lexer.Token{
+							Type:     lexer.Identifier,
+							Literal:  "variable",
+							Position: lexer.Position{Line: 1, Column: 5, Offset: 4},
+						},
 						Value: "variable",
 					},
 					Value: &ast.Integer{
-						Token: lexer.Token{Type: lexer.Integer, Literal: "10"},
+						Token: lexer.Token{
+							Type:     lexer.Integer,
+							Literal:  "10",
+							Position: lexer.Position{Line: 1, Column: 16, Offset: 15},
+						},
 						Value: 10,
 					},
 				},
@@ -34,24 +44,27 @@ func Test_Parser_parseValidCode(t *testing.T) {
 			code: `return 2 + 2;`,
 			expectedProgram: &ast.Program{Statements: []ast.Statement{
 				&ast.ReturnStatement{
-					Token: lexer.Token{Type: lexer.Return, Literal: "return"},
+					Token: lexer.Token{Type: lexer.Return, Literal: "return", Position: lexer.Position{Line: 1, Column: 1}},
 					Result: &ast.InfixExpression{
 						Token: lexer.Token{
-							Type:    lexer.Plus,
-							Literal: "+",
+							Type:     lexer.Plus,
+							Literal:  "+",
+							Position: lexer.Position{Line: 1, Column: 10, Offset: 9},
 						},
 						Left: &ast.Integer{
 							Token: lexer.Token{
-								Type:    lexer.Integer,
-								Literal: "2",
+								Type:     lexer.Integer,
+								Literal:  "2",
+								Position: lexer.Position{Line: 1, Column: 8, Offset: 7},
 							},
 							Value: 2,
 						},
 						Operator: "+",
 						Right: &ast.Integer{
 							Token: lexer.Token{
-								Type:    lexer.Integer,
-								Literal: "2",
+								Type:     lexer.Integer,
+								Literal:  "2",
+								Position: lexer.Position{Line: 1, Column: 12, Offset: 11},
 							},
 							Value: 2,
 						},
@@ -72,81 +85,190 @@ func Test_Parser_parseValidCode(t *testing.T) {
 }
 
 func Test_Parser_ParseProgram(t *testing.T) {
+	b := astbuilder.B
 	testCases := []struct {
-		code        string
-		expectedAst string
+		code     string
+		expected *ast.Program
 	}{
 		{
-			code:        "let variable = 2 + 2 * 2;",
-			expectedAst: "let variable = (2 + (2 * 2))\n",
+			code:     "let variable = 2 + 2 * 2;",
+			expected: b.Program(b.Let("variable", b.Infix(b.Int(2), "+", b.Infix(b.Int(2), "*", b.Int(2))))),
+		},
+		{
+			code:     "return 2 + variable * 2;",
+			expected: b.Program(b.Return(b.Infix(b.Int(2), "+", b.Infix(b.Ident("variable"), "*", b.Int(2))))),
+		},
+		{
+			code: "if (true == false) { let a = 10; };",
+			expected: b.Program(b.Expr(b.If(
+				b.Infix(b.Bool(true), "==", b.Bool(false)),
+				b.Block(b.Let("a", b.Int(10))),
+			))),
+		},
+		{
+			code: "if (true == false) { let a = 10; } else { let a = 20; };",
+			expected: b.Program(b.Expr(b.IfElse(
+				b.Infix(b.Bool(true), "==", b.Bool(false)),
+				b.Block(b.Let("a", b.Int(10))),
+				b.Block(b.Let("a", b.Int(20))),
+			))),
+		},
+		{
+			code: "fn (x, y) { return x + y; }",
+			expected: b.Program(b.Expr(b.Fn([]string{"x", "y"}, b.Block(
+				b.Return(b.Infix(b.Ident("x"), "+", b.Ident("y"))),
+			)))),
+		},
+		{
+			code: "fn (x, y) { let x = 2; return x; }",
+			expected: b.Program(b.Expr(b.Fn([]string{"x", "y"}, b.Block(
+				b.Let("x", b.Int(2)),
+				b.Return(b.Ident("x")),
+			)))),
+		},
+		{
+			code:     "fn (x) { x; }",
+			expected: b.Program(b.Expr(b.Fn([]string{"x"}, b.Block(b.Expr(b.Ident("x")))))),
+		},
+		{
+			code:     "add(5);",
+			expected: b.Program(b.Expr(b.Call(b.Ident("add"), b.Int(5)))),
+		},
+		{
+			code:     "add(x, 2 + 5);",
+			expected: b.Program(b.Expr(b.Call(b.Ident("add"), b.Ident("x"), b.Infix(b.Int(2), "+", b.Int(5))))),
 		},
 		{
-			code:        "return 2 + variable * 2;",
-			expectedAst: "return (2 + (variable * 2))\n",
+			code: "fn (x) { x; }(5)",
+			expected: b.Program(b.Expr(b.Call(
+				b.Fn([]string{"x"}, b.Block(b.Expr(b.Ident("x")))),
+				b.Int(5),
+			))),
 		},
 		{
-			code:        "if (true == false) { let a = 10; };",
-			expectedAst: "if (true == false) {\n  let a = 10;\n}\n",
+			code:     "\"hello world\"",
+			expected: b.Program(b.Expr(b.Str("hello world"))),
 		},
 		{
-			code:        "if (true == false) { let a = 10; } else { let a = 20; };",
-			expectedAst: "if (true == false) {\n  let a = 10;\n} else {\n  let a = 20;\n}\n",
+			code: "[1, 2 * 2, 3 + 3]",
+			expected: b.Program(b.Expr(b.Array(
+				b.Int(1),
+				b.Infix(b.Int(2), "*", b.Int(2)),
+				b.Infix(b.Int(3), "+", b.Int(3)),
+			))),
 		},
 		{
-			code:        "fn (x, y) { return x + y; }",
-			expectedAst: "fn (x, y) {\n  return (x + y);\n}\n",
+			code:     "array[1 + 1]",
+			expected: b.Program(b.Expr(b.Index(b.Ident("array"), b.Infix(b.Int(1), "+", b.Int(1))))),
 		},
 		{
-			code:        "fn (x, y) { let x = 2; return x; }",
-			expectedAst: "fn (x, y) {\n  let x = 2;\n  return x;\n}\n",
+			code: "a * [1, 2, 3, 4][b * c] * d",
+			expected: b.Program(b.Expr(b.Infix(
+				b.Infix(
+					b.Ident("a"),
+					"*",
+					b.Index(
+						b.Array(b.Int(1), b.Int(2), b.Int(3), b.Int(4)),
+						b.Infix(b.Ident("b"), "*", b.Ident("c")),
+					),
+				),
+				"*",
+				b.Ident("d"),
+			))),
 		},
 		{
-			code:        "fn (x) { x; }",
-			expectedAst: "fn (x) {\n  x;\n}\n",
+			code: "add(a * b[2], b[1], 2 * [1, 2][1])",
+			expected: b.Program(b.Expr(b.Call(
+				b.Ident("add"),
+				b.Infix(b.Ident("a"), "*", b.Index(b.Ident("b"), b.Int(2))),
+				b.Index(b.Ident("b"), b.Int(1)),
+				b.Infix(b.Int(2), "*", b.Index(b.Array(b.Int(1), b.Int(2)), b.Int(1))),
+			))),
 		},
 		{
-			code:        "add(5);",
-			expectedAst: "add(5);\n",
+			code: `{"key": "val", 2: true}`,
+			expected: b.Program(b.Expr(b.Hash(
+				b.Pair(b.Str("key"), b.Str("val")),
+				b.Pair(b.Int(2), b.Bool(true)),
+			))),
 		},
 		{
-			code:        "add(x, 2 + 5);",
-			expectedAst: "add(x, (2 + 5));\n",
+			code: `{"key" + "key2": "val", 2 + 3: !true}`,
+			expected: b.Program(b.Expr(b.Hash(
+				b.Pair(b.Infix(b.Str("key"), "+", b.Str("key2")), b.Str("val")),
+				b.Pair(b.Infix(b.Int(2), "+", b.Int(3)), b.Prefix("!", b.Bool(true))),
+			))),
 		},
 		{
-			code:        "fn (x) { x; }(5)",
-			expectedAst: "fn (x) {\n  x;\n}(5);\n",
+			code:     "{}",
+			expected: b.Program(b.Expr(b.Hash())),
 		},
 		{
-			code:        "\"hello world\"",
-			expectedAst: "\"hello world\"\n",
+			code: "enum Color { Red, Green }; Color.Red == c",
+			expected: b.Program(
+				b.Enum("Color", "Red", "Green"),
+				b.Expr(b.Infix(b.Member(b.Ident("Color"), "Red"), "==", b.Ident("c"))),
+			),
 		},
 		{
-			code:        "[1, 2 * 2, 3 + 3]",
-			expectedAst: "[1, (2 * 2), (3 + 3)]\n",
+			code: "fn* (n) { yield n + 1; }",
+			expected: b.Program(b.Expr(b.Generator([]string{"n"}, b.Block(
+				b.Expr(b.Yield(b.Infix(b.Ident("n"), "+", b.Int(1)))),
+			)))),
 		},
 		{
-			code:        "array[1 + 1]",
-			expectedAst: "(array[(1 + 1)])\n",
+			code:     "let x, y = f(1);",
+			expected: b.Program(b.DestructuringLet(b.Call(b.Ident("f"), b.Int(1)), "x", "y")),
 		},
 		{
-			code:        "a * [1, 2, 3, 4][b * c] * d",
-			expectedAst: "((a * ([1, 2, 3, 4][(b * c)])) * d)\n",
+			code: "fn (a, b) { return b, a + 1; }",
+			expected: b.Program(b.Expr(b.Fn([]string{"a", "b"}, b.Block(
+				b.Return(b.Tuple(b.Ident("b"), b.Infix(b.Ident("a"), "+", b.Int(1)))),
+			)))),
 		},
 		{
-			code:        "add(a * b[2], b[1], 2 * [1, 2][1])",
-			expectedAst: "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));\n",
+			code:     "[\n  1,\n  2,\n]",
+			expected: b.Program(b.Expr(b.Array(b.Int(1), b.Int(2)))),
 		},
 		{
-			code:        `{"key": "val", 2: true}`,
-			expectedAst: `{"key": "val", 2: true}` + "\n",
+			code: "{\n  \"a\": 1,\n  \"b\": 2,\n}",
+			expected: b.Program(b.Expr(b.Hash(
+				b.Pair(b.Str("a"), b.Int(1)),
+				b.Pair(b.Str("b"), b.Int(2)),
+			))),
 		},
 		{
-			code:        `{"key" + "key2": "val", 2 + 3: !true}`,
-			expectedAst: `{("key" + "key2"): "val", (2 + 3): (!true)}` + "\n",
+			code: "fn (\n  a,\n  b,\n) { a }(\n  1,\n  2,\n)",
+			expected: b.Program(b.Expr(b.Call(
+				b.Fn([]string{"a", "b"}, b.Block(b.Expr(b.Ident("a")))),
+				b.Int(1),
+				b.Int(2),
+			))),
 		},
 		{
-			code:        "{}",
-			expectedAst: "{}\n",
+			code: "x = y = 5 + 1",
+			expected: b.Program(b.Expr(
+				b.Assign(b.Ident("x"), b.Assign(b.Ident("y"), b.Infix(b.Int(5), "+", b.Int(1)))),
+			)),
+		},
+		{
+			code: "a[i + 1] = b == c",
+			expected: b.Program(b.Expr(b.Assign(
+				b.Index(b.Ident("a"), b.Infix(b.Ident("i"), "+", b.Int(1))),
+				b.Infix(b.Ident("b"), "==", b.Ident("c")),
+			))),
+		},
+		{
+			code:     "enum Color { Red, Green, }",
+			expected: b.Program(b.Enum("Color", "Red", "Green")),
+		},
+		{
+			code:     `import "db"; db.query(1)`,
+			expected: b.Program(b.Import("db"), b.Expr(b.Call(b.Member(b.Ident("db"), "query"), b.Int(1)))),
+		},
+		{
+			code:     "2.5e-3 * x",
+			expected: b.Program(b.Expr(b.Infix(b.Float("2.5e-3"), "*", b.Ident("x")))),
 		},
 	}
 
@@ -155,7 +277,7 @@ func Test_Parser_ParseProgram(t *testing.T) {
 			program, err := New(lexer.New(strings.NewReader(testCase.code))).ParseProgram()
 
 			assert.NoError(t, err)
-			assert.Equal(t, testCase.expectedAst, program.String())
+			asttest.AssertEqual(t, testCase.expected, program)
 		})
 	}
 }
@@ -167,11 +289,55 @@ func Test_Parser_parsingError(t *testing.T) {
 	}{
 		"missing assignment in let statement": {
 			code:          "let variable 10;",
-			expectedError: "expected assign operator, got integer",
+			expectedError: "expected assign operator, got integer at 1:14",
 		},
 		"missing identifier in let statement": {
 			code:          "let = 10;",
-			expectedError: "expected identifier, got assign",
+			expectedError: "expected identifier, got assign at 1:5",
+		},
+		"missing right operand": {
+			code:          "x +",
+			expectedError: `"" is not a valid prefix expression at 1:4`,
+		},
+		"yield outside generator": {
+			code:          "fn* () { fn () { yield 1; } }",
+			expectedError: "yield outside generator function at 1:18",
+		},
+		"missing member name": {
+			code:          "Color.1",
+			expectedError: "expected member name, got integer at 1:7",
+		},
+		"assignment to literal": {
+			code:          "1 = 2",
+			expectedError: "cannot assign to 1 at 1:3",
+		},
+		"assignment to call": {
+			code:          "a = f() = 2",
+			expectedError: "cannot assign to f() at 1:9",
+		},
+		"comma without element": {
+			code:          "[1, , 2]",
+			expectedError: `"," is not a valid prefix expression at 1:5`,
+		},
+		"lone comma in arguments": {
+			code:          "f(,)",
+			expectedError: `"," is not a valid prefix expression at 1:3`,
+		},
+		"missing identifier after comma in let statement": {
+			code:          "let x, = f();",
+			expectedError: "expected identifier, got assign at 1:8",
+		},
+		"illegal character": {
+			code:          "let a = 1 ^ 2;",
+			expectedError: `illegal character "^" at 1:11`,
+		},
+		"unterminated string": {
+			code:          `let a = 1; puts("abc);`,
+			expectedError: "unterminated string at 1:17",
+		},
+		"unclosed parenthesis": {
+			code:          "(1 + 2",
+			expectedError: "expected right parenthesis, got eof at 1:7",
 		},
 	}
 
@@ -185,3 +351,150 @@ func Test_Parser_parsingError(t *testing.T) {
 		})
 	}
 }
+
+func Test_Parser_nodePositions(t *testing.T) {
+	testCases := map[string]struct {
+		code        string
+		expectedPos lexer.Position
+		expectedEnd lexer.Position
+	}{
+		"infix expression": {
+			code:        "  1 + foo",
+			expectedPos: lexer.Position{Line: 1, Column: 3, Offset: 2},
+			expectedEnd: lexer.Position{Line: 1, Column: 10, Offset: 9},
+		},
+		"call expression": {
+			code:        `puts("żółw", [1, 2])`,
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 1, Column: 21, Offset: 23},
+		},
+		"let statement with function": {
+			code:        "let f = fn(x) {\n  x[0]\n};",
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 3, Column: 2, Offset: 24},
+		},
+		"if expression with else": {
+			code:        "if (a) { 1 } else { {\"a\": 2} }",
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 1, Column: 31, Offset: 30},
+		},
+		"multi-line string": {
+			code:        "\"a\nb\"",
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 2, Column: 3, Offset: 5},
+		},
+		"return tuple": {
+			code:        "return a, b.c",
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 1, Column: 14, Offset: 13},
+		},
+		"bare return": {
+			code:        "return;",
+			expectedPos: lexer.Position{Line: 1, Column: 1, Offset: 0},
+			expectedEnd: lexer.Position{Line: 1, Column: 7, Offset: 6},
+		},
+	}
+
+	for testCaseName, testCase := range testCases {
+		t.Run(testCaseName, func(t *testing.T) {
+			parser := New(lexer.New(strings.NewReader(testCase.code)))
+
+			program, err := parser.ParseProgram()
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedPos, program.Pos())
+			assert.Equal(t, testCase.expectedEnd, program.End())
+		})
+	}
+}
+
+func Test_Parser_comments(t *testing.T) {
+	code := `// Adds one.
+// Returns an integer.
+let inc = fn(x) {
+	// the result
+	x + 1 // trailing
+};
+puts(inc(1)); // print it
+// dangling`
+
+	parser := New(lexer.New(strings.NewReader(code), lexer.WithComments()), WithComments())
+
+	program, err := parser.ParseProgram()
+	assert.NoError(t, err)
+
+	texts := make([]string, len(program.Comments))
+	for i, comment := range program.Comments {
+		texts[i] = comment.Text()
+	}
+	assert.Equal(t, []string{"Adds one.", "Returns an integer.", "the result", "trailing", "print it", "dangling"}, texts)
+
+	let := program.Statements[0].(*ast.LetStatement)
+	body := let.Value.(*ast.FunctionExpression).Body.(*ast.BlockStatement)
+	assert.Equal(t, map[ast.Statement]*ast.Comments{
+		let:                   {Leading: program.Comments[0:2]},
+		body.Statements[0]:    {Leading: program.Comments[2:3], Trailing: program.Comments[3]},
+		program.Statements[1]: {Trailing: program.Comments[4]},
+	}, program.Attached)
+}
+
+func Test_Parser_skipsCommentTokens(t *testing.T) {
+	parser := New(lexer.New(strings.NewReader("1 + // one\n2"), lexer.WithComments()))
+
+	program, err := parser.ParseProgram()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "(1 + 2)\n", program.String())
+	assert.Nil(t, program.Comments)
+}
+
+func Test_ParseExpression(t *testing.T) {
+	testCases := map[string]struct {
+		source        string
+		expected      string
+		expectedError string
+	}{
+		"infix expression": {
+			source:   "1 + x * 2",
+			expected: "(1 + (x * 2))",
+		},
+		"trailing semicolon": {
+			source:   `{"a": [1, 2]};`,
+			expected: `{"a": [1, 2]}`,
+		},
+		"call spanning lines": {
+			source:   "f(\n  1,\n  2\n)",
+			expected: "f(1, 2);",
+		},
+		"empty source": {
+			source:        "",
+			expectedError: `"" is not a valid prefix expression at 1:1`,
+		},
+		"statement": {
+			source:        "let a = 1",
+			expectedError: `"let" is not a valid prefix expression at 1:1`,
+		},
+		"two expressions": {
+			source:        "1; 2",
+			expectedError: "expected end of expression, got integer at 1:4",
+		},
+		"lexer error": {
+			source:        "1 + ^",
+			expectedError: `illegal character "^" at 1:5`,
+		},
+	}
+
+	for testCaseName, testCase := range testCases {
+		t.Run(testCaseName, func(t *testing.T) {
+			expression, err := ParseExpression(testCase.source)
+
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, expression.String())
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Package spike embeds the interpreter in Go programs. An Engine parses,
+// compiles and runs source on the VM, or with WithEngine(EvalEngine) on the
+// tree-walking evaluator, keeping definitions between calls:
+//
+//	engine := spike.NewEngine()
+//	_, err := engine.Eval(`let double = fn(x) { x * 2 };`)
+//	result, err := engine.Eval(`double(21)`)
+//
+// An Engine must not be used by several goroutines at once. Engines share no
+// mutable state though, nor do compilers and VMs, so each goroutine can run
+// scripts on its own engine, for example one per request taken from a
+// sync.Pool filled by a Factory. Scripts reading input without WithStdin all
+// read the process's standard input, which is not safe to do concurrently.
+package spike
+
+import (
+	"context"
+	"io"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/eval"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"spike-interpreter-go/spike/parser/ast"
+	"spike-interpreter-go/spike/vm"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxBuiltins is how many builtins OpGetBuiltin can address.
+const maxBuiltins = 256
+
+// EngineKind is what runs the scripts of an Engine.
+type EngineKind string
+
+const (
+	// VMEngine compiles scripts to bytecode and runs them on the VM. It is
+	// the default.
+	VMEngine EngineKind = "vm"
+	// EvalEngine runs scripts on the tree-walking evaluator.
+	EvalEngine EngineKind = "eval"
+)
+
+type Engine struct {
+	kind        EngineKind
+	builtins    []*object.BuiltinFunction
+	modules     map[string]*object.Module
+	session     *vm.Session
+	vmOptions   []vm.Option
+	environment *object.Environment
+	evalOptions []eval.Option
+}
+
+type Option func(engine *Engine)
+
+// WithEngine picks what runs scripts, VMEngine or EvalEngine.
+func WithEngine(kind EngineKind) Option {
+	return func(engine *Engine) {
+		engine.kind = kind
+	}
+}
+
+// Builtin is a Go function scripts can call.
+type Builtin func(args ...object.Object) (object.Object, error)
+
+// WithStdout makes scripts print to stdout instead of the process's standard
+// output.
+func WithStdout(stdout io.Writer) Option {
+	return func(engine *Engine) {
+		engine.vmOptions = append(engine.vmOptions, vm.WithStdout(stdout))
+		engine.evalOptions = append(engine.evalOptions, eval.WithStdout(stdout))
+	}
+}
+
+// WithStderr makes scripts print errors to stderr instead of the process's
+// standard error.
+func WithStderr(stderr io.Writer) Option {
+	return func(engine *Engine) {
+		engine.vmOptions = append(engine.vmOptions, vm.WithStderr(stderr))
+		engine.evalOptions = append(engine.evalOptions, eval.WithStderr(stderr))
+	}
+}
+
+// WithStdin makes scripts read input from stdin instead of the process's
+// standard input.
+func WithStdin(stdin io.Reader) Option {
+	return func(engine *Engine) {
+		engine.vmOptions = append(engine.vmOptions, vm.WithStdin(stdin))
+		engine.evalOptions = append(engine.evalOptions, eval.WithStdin(stdin))
+	}
+}
+
+// WithPolicy restricts which builtins scripts may call and what they may do,
+// object.PurePolicy leaves only pure functions.
+func WithPolicy(policy *object.Policy) Option {
+	return func(engine *Engine) {
+		engine.vmOptions = append(engine.vmOptions, vm.WithPolicy(policy))
+		engine.evalOptions = append(engine.evalOptions, eval.WithPolicy(policy))
+	}
+}
+
+// WithBuiltin registers fn as name, see Engine.RegisterBuiltin.
+func WithBuiltin(name string, fn Builtin) Option {
+	return func(engine *Engine) {
+		engine.RegisterBuiltin(name, fn)
+	}
+}
+
+// WithModule registers functions as the module name, see
+// Engine.RegisterModule.
+func WithModule(name string, functions map[string]Builtin) Option {
+	return func(engine *Engine) {
+		engine.RegisterModule(name, functions)
+	}
+}
+
+func NewEngine(options ...Option) *Engine {
+	engine := &Engine{
+		kind:     VMEngine,
+		builtins: append([]*object.BuiltinFunction{}, object.Builtins...),
+		modules:  map[string]*object.Module{},
+		session:  &vm.Session{Globals: make([]object.Object, vm.GlobalsSize)},
+	}
+	engine.Reset()
+	for _, option := range options {
+		option(engine)
+	}
+
+	return engine
+}
+
+// Reset forgets the definitions of earlier calls, keeping the registered
+// builtins and modules, so the engine can be reused for unrelated scripts.
+func (engine *Engine) Reset() {
+	engine.session.SymbolTable = compiler.NewSymbolTable()
+	for i, builtin := range engine.builtins {
+		engine.session.SymbolTable.DefineBuiltin(i, builtin.Name)
+	}
+	engine.session.Constants = []object.Object{}
+	for i := range engine.session.Globals {
+		engine.session.Globals[i] = nil
+	}
+
+	engine.environment = object.NewEnvironment()
+	for _, builtin := range engine.builtins[len(object.Builtins):] {
+		engine.environment.Set(builtin.Name, builtin)
+	}
+}
+
+// RegisterBuiltin makes fn callable from scripts run afterwards as name,
+// shadowing a builtin or global of the same name. It panics once there are
+// more builtins than the bytecode can refer to.
+func (engine *Engine) RegisterBuiltin(name string, fn Builtin) {
+	if len(engine.builtins) >= maxBuiltins {
+		panic(errors.Errorf("unable to register %s: there can be at most %d builtins", name, maxBuiltins))
+	}
+
+	builtin := newBuiltin(name, fn)
+	engine.builtins = append(engine.builtins, builtin)
+	engine.session.SymbolTable.DefineBuiltin(len(engine.builtins)-1, name)
+	engine.environment.Set(name, builtin)
+}
+
+// RegisterModule makes functions available to scripts run afterwards as
+// members of the module name, bound by `import "name"`. Registering a name
+// again replaces the module for later imports. Sandbox policies see the
+// functions as "name.function".
+func (engine *Engine) RegisterModule(name string, functions map[string]Builtin) {
+	module := &object.Module{Name: name, Members: map[string]object.Object{}}
+	for functionName, fn := range functions {
+		module.Members[functionName] = newBuiltin(name+"."+functionName, fn)
+	}
+
+	engine.modules[name] = module
+}
+
+func newBuiltin(name string, fn Builtin) *object.BuiltinFunction {
+	return &object.BuiltinFunction{
+		Name: name,
+		Function: func(_ object.Runtime, args ...object.Object) (object.Object, error) {
+			return fn(args...)
+		},
+	}
+}
+
+// Eval runs source with a new engine.
+func Eval(source string) (object.Object, error) {
+	return NewEngine().Eval(source)
+}
+
+// Eval runs source with the definitions of earlier calls in scope and
+// returns the value of its last statement when that is an expression, null
+// otherwise. Errors are a *diagnostic.ParseError, *diagnostic.CompileError or
+// *diagnostic.RuntimeError, while a script calling exit fails with an
+// *object.ExitError. On the VM, the names a failing call defined are
+// forgotten again.
+func (engine *Engine) Eval(source string) (object.Object, error) {
+	return engine.EvalContext(context.Background(), source)
+}
+
+// EvalWithTimeout runs source like Eval, stopping it once it runs longer than
+// timeout. The error is then a *diagnostic.RuntimeError caused by
+// context.DeadlineExceeded.
+func (engine *Engine) EvalWithTimeout(source string, timeout time.Duration) (object.Object, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return engine.EvalContext(ctx, source)
+}
+
+// EvalContext runs source like Eval, stopping it once ctx is done.
+func (engine *Engine) EvalContext(ctx context.Context, source string) (object.Object, error) {
+	program, err := parser.New(lexer.New(strings.NewReader(source))).ParseProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	if engine.kind == EvalEngine {
+		return engine.evaluate(ctx, program)
+	}
+
+	compiled, err := engine.session.Compile(program)
+	if err != nil {
+		return nil, err
+	}
+
+	options := append([]vm.Option{vm.WithBuiltins(engine.builtins), vm.WithModules(engine.modules)}, engine.vmOptions...)
+	machine, err := engine.session.Run(ctx, compiled, options...)
+	if err != nil {
+		return nil, machine.RuntimeError(err)
+	}
+
+	if !program.EndsWithExpression() {
+		return &object.NullObject, nil
+	}
+
+	return machine.LastPoppedStackElement(), nil
+}
+
+// evaluate runs program on the evaluator like EvalContext does on the VM.
+func (engine *Engine) evaluate(ctx context.Context, program *ast.Program) (object.Object, error) {
+	options := append([]eval.Option{eval.WithModules(engine.modules)}, engine.evalOptions...)
+	evaluator := eval.New(options...)
+	result, err := evaluator.EvalContext(ctx, program, engine.environment)
+	if err != nil {
+		return nil, evaluator.RuntimeError(err)
+	}
+
+	if !program.EndsWithExpression() || result == nil {
+		return &object.NullObject, nil
+	}
+
+	return result, nil
+}
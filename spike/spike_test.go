@@ -0,0 +1,291 @@
+package spike
+
+import (
+	"context"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/object"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Eval(t *testing.T) {
+	testCases := []struct {
+		source        string
+		expected      object.Object
+		expectedError string
+	}{
+		{
+			source:   `let x = 2; [x * 3, len("spike")]`,
+			expected: &object.Array{Elements: []object.Object{&object.Integer{Value: 6}, &object.Integer{Value: 5}}},
+		},
+		{
+			source:   `let x = 2;`,
+			expected: &object.NullObject,
+		},
+		{
+			source:   ``,
+			expected: &object.NullObject,
+		},
+		{
+			source:        `let = 2;`,
+			expectedError: "expected identifier, got assign at 1:5",
+		},
+		{
+			source:        `y + 1`,
+			expectedError: "unable to resolve identifier: y at 1:1",
+		},
+		{
+			source:        `len(1)`,
+			expectedError: "len: argument of type integer is not supported at 1:1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.source, func(t *testing.T) {
+			result, err := Eval(testCase.source)
+
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
+
+func Test_Engine_WithStderr(t *testing.T) {
+	stderr := &strings.Builder{}
+	engine := NewEngine(WithStderr(stderr))
+
+	_, err := engine.Eval(`eprintln("warning")`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "warning\n", stderr.String())
+}
+
+func Test_Eval_errorPhases(t *testing.T) {
+	testCases := []struct {
+		source        string
+		expectedPhase diagnostic.Phase
+		expectedCode  diagnostic.Code
+	}{
+		{source: `let x = ~;`, expectedPhase: diagnostic.ParsePhase, expectedCode: diagnostic.InvalidCharacter},
+		{source: `let = 1;`, expectedPhase: diagnostic.ParsePhase, expectedCode: diagnostic.InvalidSyntax},
+		{source: `x + 1`, expectedPhase: diagnostic.CompilePhase, expectedCode: diagnostic.UnresolvedIdentifier},
+		{source: `len(1)`, expectedPhase: diagnostic.RuntimePhase, expectedCode: diagnostic.RuntimeFailure},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.source, func(t *testing.T) {
+			_, err := Eval(testCase.source)
+
+			typed, ok := err.(diagnostic.Diagnostic)
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expectedPhase, typed.Phase())
+			assert.Equal(t, testCase.expectedCode, typed.ErrorCode())
+		})
+	}
+
+	_, err := Eval(`exit(2)`)
+	assert.Equal(t, &object.ExitError{Code: 2}, err)
+}
+
+func Test_Engine_EvalWithTimeout(t *testing.T) {
+	engine := NewEngine()
+
+	for _, source := range []string{
+		`sleep(60000)`,
+		`let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) + f(n - 1) } }; f(60)`,
+	} {
+		start := time.Now()
+		_, err := engine.EvalWithTimeout(source, 20*time.Millisecond)
+
+		assert.True(t, time.Since(start) < 5*time.Second)
+		assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+		assert.Equal(t, diagnostic.Interrupted, err.(*diagnostic.RuntimeError).ErrorCode())
+	}
+
+	result, err := engine.EvalWithTimeout(`len("spike")`, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Integer{Value: 5}, result)
+}
+
+func Test_Engine_keepsDefinitions(t *testing.T) {
+	stdout := &strings.Builder{}
+	engine := NewEngine(WithStdout(stdout), WithStdin(strings.NewReader("spike\n")))
+
+	_, err := engine.Eval(`let greet = fn(name) { "hello " + name };`)
+	assert.NoError(t, err)
+
+	_, err = engine.Eval(`undefined`)
+	assert.Error(t, err)
+
+	result, err := engine.Eval(`let name = input("name? "); println(greet(name)); greet("you")`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.String{Value: "hello you"}, result)
+	assert.Equal(t, "name? hello spike\n", stdout.String())
+}
+
+func Test_Engine_failingEvalForgetsItsNames(t *testing.T) {
+	engine := NewEngine()
+
+	_, err := engine.Eval(`let a = 1;`)
+	assert.NoError(t, err)
+
+	_, err = engine.Eval(`let a = 2; let b = 3; len(1)`)
+	assert.Error(t, err)
+	_, err = engine.Eval(`let c = 4; undefined`)
+	assert.Error(t, err)
+
+	result, err := engine.Eval(`a`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Integer{Value: 1}, result)
+
+	for _, name := range []string{"b", "c"} {
+		_, err = engine.Eval(name)
+		assert.EqualError(t, err, "unable to resolve identifier: "+name+" at 1:1")
+	}
+}
+
+func Test_Engine_RegisterBuiltin(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterBuiltin("sum", func(args ...object.Object) (object.Object, error) {
+		total := int64(0)
+		for _, arg := range args {
+			integer, ok := arg.(*object.Integer)
+			if !ok {
+				return nil, errors.Errorf("sum: expected integers, got %s", arg.Type())
+			}
+			total += integer.Value
+		}
+
+		return &object.Integer{Value: total}, nil
+	})
+	engine.RegisterBuiltin("len", func(args ...object.Object) (object.Object, error) {
+		return &object.Integer{Value: -1}, nil
+	})
+
+	result, err := engine.Eval(`[sum(1, 2, 3), map([[1], [2, 3]], fn(xs) { sum(xs[0], len(xs)) })]`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.Integer{Value: 6},
+		&object.Array{Elements: []object.Object{&object.Integer{Value: 0}, &object.Integer{Value: 1}}},
+	}}, result)
+
+	_, err = engine.Eval(`sum(1, "a")`)
+	assert.EqualError(t, err, "sum: expected integers, got string at 1:1")
+
+	_, err = NewEngine().Eval(`sum(1)`)
+	assert.EqualError(t, err, "unable to resolve identifier: sum at 1:1")
+}
+
+func Test_Engine_RegisterBuiltin_freeze(t *testing.T) {
+	engine := NewEngine(WithBuiltin("freeze", func(args ...object.Object) (object.Object, error) {
+		return args[0], nil
+	}))
+
+	result, err := engine.Eval(`let a = freeze([1, 2]); a[0] = 3; a`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[3, 2]", result.Inspect())
+}
+
+func Test_Engine_RegisterModule(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterModule("db", map[string]Builtin{
+		"get": func(args ...object.Object) (object.Object, error) {
+			return &object.String{Value: "row " + args[0].Inspect()}, nil
+		},
+		"drop": func(args ...object.Object) (object.Object, error) {
+			return &object.NullObject, nil
+		},
+	})
+
+	_, err := engine.Eval(`import "db";`)
+	assert.NoError(t, err)
+	result, err := engine.Eval(`db.get(1)`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.String{Value: "row 1"}, result)
+
+	_, err = NewEngine().Eval(`import "db"`)
+	assert.EqualError(t, err, "unknown module db at 1:1")
+
+	sandboxed := NewEngine(WithPolicy(object.DefaultPolicy().DenyBuiltins("db.drop")))
+	sandboxed.RegisterModule("db", map[string]Builtin{"drop": func(args ...object.Object) (object.Object, error) {
+		return &object.NullObject, nil
+	}})
+	_, err = sandboxed.Eval(`import "db"; db.drop()`)
+	assert.EqualError(t, err, "db.drop is not permitted by the sandbox policy at 1:14")
+}
+
+func Test_Engine_RegisterBuiltin_limit(t *testing.T) {
+	engine := NewEngine()
+	for i := len(object.Builtins); i < maxBuiltins; i++ {
+		engine.RegisterBuiltin("f", func(args ...object.Object) (object.Object, error) { return &object.NullObject, nil })
+	}
+
+	assert.Panics(t, func() {
+		engine.RegisterBuiltin("g", func(args ...object.Object) (object.Object, error) { return &object.NullObject, nil })
+	})
+}
+
+func Test_Engine_WithPolicy(t *testing.T) {
+	engine := NewEngine(WithPolicy(object.PurePolicy()))
+	engine.RegisterBuiltin("answer", func(args ...object.Object) (object.Object, error) {
+		return &object.Integer{Value: 42}, nil
+	})
+
+	result, err := engine.Eval(`answer() + len("ab")`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Integer{Value: 44}, result)
+
+	_, err = engine.Eval(`readFile("/etc/passwd")`)
+	assert.EqualError(t, err, "readFile is not permitted by the sandbox policy at 1:1")
+}
+
+func Test_Engine_WithEngine(t *testing.T) {
+	for _, kind := range []EngineKind{VMEngine, EvalEngine} {
+		t.Run(string(kind), func(t *testing.T) {
+			stdout := &strings.Builder{}
+			engine := NewEngine(WithEngine(kind), WithStdout(stdout), WithBuiltin("answer", func(args ...object.Object) (object.Object, error) {
+				return &object.Integer{Value: 42}, nil
+			}))
+			engine.RegisterModule("math", map[string]Builtin{"double": func(args ...object.Object) (object.Object, error) {
+				return &object.Integer{Value: 2 * args[0].(*object.Integer).Value}, nil
+			}})
+
+			_, err := engine.Eval(`let inc = fn(x) { x + 1 }; import "math";`)
+			assert.NoError(t, err)
+
+			result, err := engine.Eval(`println("hi"); math.double(inc(answer()))`)
+			assert.NoError(t, err)
+			assert.Equal(t, &object.Integer{Value: 86}, result)
+			assert.Equal(t, "hi\n", stdout.String())
+
+			result, err = engine.Eval(`let x = 1;`)
+			assert.NoError(t, err)
+			assert.Equal(t, &object.NullObject, result)
+
+			_, err = engine.Eval("inc(1);\n[1][5]")
+			assert.EqualError(t, err, "index 5 out of range [0..0] at 2:1")
+			assert.Equal(t, diagnostic.RuntimePhase, err.(*diagnostic.RuntimeError).Phase())
+
+			_, err = engine.EvalWithTimeout(`let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) + f(n - 1) } }; f(60)`, 20*time.Millisecond)
+			assert.Equal(t, diagnostic.Interrupted, err.(*diagnostic.RuntimeError).ErrorCode())
+
+			_, err = engine.Eval(`exit(3)`)
+			assert.Equal(t, &object.ExitError{Code: 3}, err)
+
+			engine.Reset()
+			result, err = engine.Eval(`answer()`)
+			assert.NoError(t, err)
+			assert.Equal(t, &object.Integer{Value: 42}, result)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package vm
+
+import (
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+)
+
+// Debugger is told whenever the VM starts running another line of the source.
+// The VM waits for Line to return, and stops the run with its error if there
+// is one.
+type Debugger interface {
+	Line(pause *Pause) error
+}
+
+// WithDebugger reports every source line the VM runs to debugger.
+func WithDebugger(debugger Debugger) Option {
+	return func(vm *VM) {
+		vm.debugger = debugger
+	}
+}
+
+// Pause is the VM stopped before running the line at Position. It is only
+// valid until the debugger's Line returns.
+type Pause struct {
+	Position lexer.Position
+	// Depth counts the calls the VM is in, 0 at the top level.
+	Depth int
+	vm    *VM
+}
+
+// Variable is a named value in scope where the VM paused.
+type Variable struct {
+	Name  string
+	Value object.Object
+}
+
+// Locals returns the parameters and local variables of the function the VM
+// paused in, which were already assigned, in the order they were defined.
+func (pause *Pause) Locals() []Variable {
+	frame := pause.vm.currentFrame()
+	names := frame.closure.Function.LocalNames
+
+	locals := []Variable{}
+	for i, name := range names {
+		value := pause.vm.stack[frame.basePointer+i]
+		if name == "" || value == nil {
+			continue
+		}
+		locals = append(locals, Variable{Name: name, Value: value})
+	}
+
+	return locals
+}
+
+// Global returns the global at index, or nil when it was not assigned yet.
+func (pause *Pause) Global(index int) object.Object {
+	if index < 0 || index >= len(pause.vm.globals) {
+		return nil
+	}
+
+	return pause.vm.globals[index]
+}
+
+// StackTrace returns the names of the functions the VM is in, innermost
+// first.
+func (pause *Pause) StackTrace() []string {
+	return pause.vm.StackTrace()
+}
+
+// debug reports the line of the current instruction to the debugger, once per
+// line and frame.
+func (vm *VM) debug() error {
+	frame := vm.currentFrame()
+	position, ok := frame.closure.Function.SourceMap.Lookup(frame.ip)
+	if !ok || position.Line == frame.line {
+		return nil
+	}
+	frame.line = position.Line
+
+	return vm.debugger.Line(&Pause{Position: position, Depth: vm.framesIndex - 1, vm: vm})
+}
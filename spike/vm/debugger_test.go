@@ -0,0 +1,47 @@
+package vm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingDebugger struct {
+	lines []string
+	stop  int
+}
+
+func (debugger *recordingDebugger) Line(pause *Pause) error {
+	locals := ""
+	for _, local := range pause.Locals() {
+		locals += fmt.Sprintf(" %s=%s", local.Name, local.Value.Inspect())
+	}
+	debugger.lines = append(debugger.lines, fmt.Sprintf("%d@%d%s", pause.Position.Line, pause.Depth, locals))
+
+	if pause.Position.Line == debugger.stop {
+		return errors.New("stopped")
+	}
+	return nil
+}
+
+func Test_Run_debugger(t *testing.T) {
+	input := "let add = fn(a, b) {\n  let sum = a + b;\n  sum\n};\nlet x = add(1, 2);\nx * 2"
+	debugger := &recordingDebugger{}
+
+	result, err := runInVM(input, WithDebugger(debugger))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "6", result.Inspect())
+	assert.Equal(t, []string{"1@0", "5@0", "2@1 a=1 b=2", "3@1 a=1 b=2 sum=3", "6@0"}, debugger.lines)
+}
+
+func Test_Run_debuggerStops(t *testing.T) {
+	debugger := &recordingDebugger{stop: 2}
+
+	_, err := runInVM("let x = 1;\nlet y = 2;\nlet z = 3;", WithDebugger(debugger))
+
+	assert.EqualError(t, err, "stopped")
+	assert.Equal(t, []string{"1@0", "2@0"}, debugger.lines)
+}
@@ -0,0 +1,41 @@
+package vm
+
+import (
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+// CallMain invokes the top-level main(args) function of a program the VM has
+// run, looking main up in the symbol table it was compiled with. It reports
+// false when the program defines no main function.
+func (vm *VM) CallMain(symbolTable *compiler.SymbolTable, args []string) (object.Object, bool, error) {
+	symbol, ok := symbolTable.Resolve(object.MainFunctionName)
+	if !ok || symbol.SymbolScope != compiler.GlobalScope {
+		return nil, false, nil
+	}
+
+	closure, ok := vm.globals[symbol.Index].(*object.Closure)
+	if !ok {
+		return nil, false, nil
+	}
+
+	parametersCount := closure.Function.ParametersCount
+	if parametersCount > 1 {
+		return nil, true, errors.Errorf("%s must take at most one parameter, got %d", object.MainFunctionName, parametersCount)
+	}
+
+	arguments := &object.Array{Elements: make([]object.Object, len(args))}
+	for i, arg := range args {
+		arguments.Elements[i] = &object.String{Value: arg}
+	}
+
+	if parametersCount == 0 {
+		result, err := vm.Call(closure)
+		return result, true, err
+	}
+
+	result, err := vm.Call(closure, arguments)
+	return result, true, err
+}
@@ -0,0 +1,83 @@
+package vm
+
+import (
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/eval"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CallMain(t *testing.T) {
+	testCases := []struct {
+		input            string
+		args             []string
+		expectedHasMain  bool
+		expectedExitCode int
+	}{
+		{
+			input:            `let x = 5;`,
+			expectedHasMain:  false,
+			expectedExitCode: 0,
+		},
+		{
+			input:            `let main = fn(args) { len(args) };`,
+			args:             []string{"a", "b", "c"},
+			expectedHasMain:  true,
+			expectedExitCode: 3,
+		},
+		{
+			input:            `let base = 40; let main = fn() { base + 2 };`,
+			expectedHasMain:  true,
+			expectedExitCode: 42,
+		},
+		{
+			input:            `let main = fn(args) { args[0] };`,
+			args:             []string{"not a number"},
+			expectedHasMain:  true,
+			expectedExitCode: 0,
+		},
+		{
+			input:            `let main = 10;`,
+			expectedHasMain:  false,
+			expectedExitCode: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.input))).ParseProgram()
+			assert.NoError(t, err)
+
+			c := compiler.New()
+			assert.NoError(t, c.Compile(program))
+
+			machine := New(c.Bytecode())
+			assert.NoError(t, machine.Run())
+
+			result, hasMain, err := machine.CallMain(c.SymbolTable(), testCase.args)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedHasMain, hasMain)
+			code, err := eval.ExitCode(result)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedExitCode, code)
+		})
+	}
+}
+
+func Test_CallMain_tooManyParameters(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`let main = fn(a, b) { 0 };`))).ParseProgram()
+	assert.NoError(t, err)
+
+	c := compiler.New()
+	assert.NoError(t, c.Compile(program))
+
+	machine := New(c.Bytecode())
+	assert.NoError(t, machine.Run())
+
+	_, _, err = machine.CallMain(c.SymbolTable(), nil)
+	assert.EqualError(t, err, "main must take at most one parameter, got 2")
+}
@@ -9,6 +9,8 @@ type Frame struct {
 	closure     *object.Closure
 	ip          int
 	basePointer int
+	// line is the source line last reported to the debugger.
+	line int
 }
 
 func NewFrame(closure *object.Closure, basePointer int) *Frame {
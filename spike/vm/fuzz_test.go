@@ -0,0 +1,56 @@
+package vm
+
+import (
+	"io/ioutil"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+)
+
+// fuzzSteps bounds the instructions a fuzzed program may run, so loops
+// through recursion end quickly.
+const fuzzSteps = 10000
+
+func FuzzCompileAndRun(f *testing.F) {
+	for _, seed := range []string{
+		"let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(10)",
+		"let xs = map([1, 2, 3], fn(x) { x * 2 }); xs[1] + len(xs)",
+		"let h = {\"a\": 1}; h[\"a\"] / 0",
+		"let g = fn*(n) { yield n; yield n + 1 }; let it = g(1); [next(it), next(it)]",
+		"enum Color { Red }; Color.Red == Color.Red",
+		"let f = fn() { f() }; f()",
+		"\"a\" * 3",
+		"let h=[]*h",
+		"let x = x; len(x)",
+		"if (false) { let y = 1 }; y",
+		"return 1",
+		"return;",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+		if err != nil {
+			return
+		}
+
+		c := compiler.New()
+		err = c.Compile(program)
+		if err != nil {
+			return
+		}
+
+		machine := New(c.Bytecode(),
+			WithMaxSteps(fuzzSteps),
+			WithPolicy(object.PurePolicy()),
+			WithStdin(strings.NewReader("")),
+			WithStdout(ioutil.Discard),
+			WithStderr(ioutil.Discard),
+		)
+		_ = machine.Run()
+	})
+}
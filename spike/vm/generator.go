@@ -0,0 +1,116 @@
+package vm
+
+import (
+	"fmt"
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+// Generator is the iterator returned by calling a generator function. It runs
+// the function's frame on a machine of its own, sharing globals and constants
+// with the VM that created it, and suspends that machine on every yield.
+type Generator struct {
+	machine *VM
+	parent  *VM
+	running bool
+	done    bool
+}
+
+func (vm *VM) newGenerator(closure *object.Closure, args []object.Object) (*Generator, error) {
+	if vm.resuming == nil {
+		vm.resuming = new(int)
+	}
+
+	machine := &VM{
+		constants: vm.constants,
+		globals:   vm.globals,
+		builtins:  vm.builtins,
+		modules:   vm.modules,
+		stack:     make([]object.Object, StackSize),
+		frames:    make([]*Frame, MaxFrames),
+		stdout:    vm.stdout,
+		stderr:    vm.stderr,
+		stdin:     vm.stdin,
+		random:    vm.Rand(),
+		policy:    vm.policy,
+		args:      vm.args,
+		maxSteps:  vm.maxSteps,
+		overflow:  vm.overflow,
+		debugger:  vm.debugger,
+		profile:   vm.profile,
+		trace:     vm.trace,
+		resuming:  vm.resuming,
+	}
+	if vm.trace != nil {
+		machine.traceThread = vm.trace.newThread(object.FrameName(closure.Function.Name))
+	}
+
+	err := machine.push(closure)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		err = machine.push(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	frame := NewFrame(closure, machine.sp-len(args))
+	err = machine.pushFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+	machine.sp = frame.basePointer + closure.Function.LocalsCount
+
+	return &Generator{machine: machine, parent: vm}, nil
+}
+
+func (generator *Generator) Type() object.ObjectType {
+	return object.IteratorType
+}
+
+func (generator *Generator) Inspect() string {
+	return fmt.Sprintf("generator[%p]", generator)
+}
+
+func (generator *Generator) Equal(other object.Object) bool {
+	return other == generator
+}
+
+func (generator *Generator) Iterate() object.Iterator {
+	return generator
+}
+
+// Next resumes the generator until it yields or returns. A generator that
+// failed or returned stays exhausted.
+func (generator *Generator) Next() (object.Object, bool, error) {
+	if generator.done {
+		return nil, false, nil
+	}
+	if generator.running {
+		return nil, false, errors.New("generator is already running")
+	}
+
+	// Generators resumed from generators nest on the Go stack, with a
+	// machine each, so they are limited like calls are.
+	resuming := generator.machine.resuming
+	if *resuming >= MaxFrames {
+		return nil, false, errors.New("stack overflow")
+	}
+
+	*resuming++
+	generator.running = true
+	generator.machine.ctx = generator.parent.ctx
+	err := generator.machine.execute(0)
+	generator.running = false
+	*resuming--
+
+	if err != nil || generator.machine.framesIndex == 0 {
+		generator.done = true
+		return nil, false, err
+	}
+
+	return generator.machine.yielded, true, nil
+}
@@ -0,0 +1,187 @@
+package vm
+
+import (
+	"spike-interpreter-go/spike/code"
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+var operators = map[code.Opcode]string{
+	code.OpAdd:         "+",
+	code.OpSub:         "-",
+	code.OpMul:         "*",
+	code.OpDiv:         "/",
+	code.OpEqual:       "==",
+	code.OpNotEqual:    "!=",
+	code.OpGreaterThan: ">",
+	code.OpLessThan:    "<",
+}
+
+// operandTypes are the types of the left and right operand of a binary
+// operator.
+type operandTypes struct {
+	left  object.ObjectType
+	right object.ObjectType
+}
+
+// binaryOperation applies opcode to operands of the types it is registered
+// for, integer arithmetic handling overflow as overflow says.
+type binaryOperation func(overflow Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error)
+
+// binaryOperations holds the binary operators of every pair of operand types
+// that has any. Supporting another type takes an entry here, operators on
+// pairs without one fail in unsupportedOperation.
+var binaryOperations = map[operandTypes]binaryOperation{
+	{object.IntegerType, object.IntegerType}: integerOperation,
+	{object.BigIntType, object.BigIntType}:   bigIntOperation,
+	{object.BigIntType, object.IntegerType}:  bigIntOperation,
+	{object.IntegerType, object.BigIntType}:  bigIntOperation,
+	{object.StringType, object.StringType}:   stringOperation,
+	{object.ArrayType, object.ArrayType}:     arrayOperation,
+	{object.BooleanType, object.BooleanType}: booleanOperation,
+}
+
+func (vm *VM) executeBinaryOperation(opcode code.Opcode) error {
+	left, right, err := vm.popTwo()
+	if err != nil {
+		return err
+	}
+
+	result, err := applyBinaryOperation(vm.overflow, opcode, left, right)
+	if err != nil {
+		return err
+	}
+
+	return vm.push(result)
+}
+
+// applyBinaryOperation dispatches opcode on the types of left and right.
+func applyBinaryOperation(overflow Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	// Integers are the common case, they skip looking up the table.
+	if _, ok := left.(*object.Integer); ok {
+		if _, ok := right.(*object.Integer); ok {
+			return integerOperation(overflow, opcode, left, right)
+		}
+	}
+
+	operation, ok := binaryOperations[operandTypes{left: left.Type(), right: right.Type()}]
+	if !ok {
+		return unsupportedOperation(opcode, left, right)
+	}
+
+	return operation(overflow, opcode, left, right)
+}
+
+func integerOperation(overflow Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	leftValue := left.(*object.Integer).Value
+	rightValue := right.(*object.Integer).Value
+
+	switch opcode {
+	case code.OpAdd, code.OpSub, code.OpMul:
+		result, err := integerArithmetic(overflow, opcode, leftValue, rightValue)
+		if err != nil {
+			return nil, err
+		}
+
+		return &object.Integer{Value: result}, nil
+	case code.OpDiv:
+		if rightValue == 0 {
+			return nil, errors.New("division by zero")
+		}
+
+		return &object.Integer{Value: leftValue / rightValue}, nil
+	case code.OpEqual:
+		return nativeBoolToBoolean(leftValue == rightValue), nil
+	case code.OpNotEqual:
+		return nativeBoolToBoolean(leftValue != rightValue), nil
+	case code.OpGreaterThan:
+		return nativeBoolToBoolean(leftValue > rightValue), nil
+	case code.OpLessThan:
+		return nativeBoolToBoolean(leftValue < rightValue), nil
+	}
+
+	return unsupportedOperation(opcode, left, right)
+}
+
+// bigIntOperation promotes an integer on either side to a BigInt, results do
+// not overflow.
+func bigIntOperation(_ Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	result, _, err := object.BigIntOperation(operators[opcode], left, right)
+
+	return result, err
+}
+
+func stringOperation(_ Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	leftValue := left.(*object.String).Value
+	rightValue := right.(*object.String).Value
+
+	switch opcode {
+	case code.OpAdd:
+		return &object.String{Value: leftValue + rightValue}, nil
+	case code.OpEqual:
+		return nativeBoolToBoolean(leftValue == rightValue), nil
+	case code.OpNotEqual:
+		return nativeBoolToBoolean(leftValue != rightValue), nil
+	}
+
+	return unsupportedOperation(opcode, left, right)
+}
+
+// arrayOperation concatenates arrays into a new one and compares them element
+// by element.
+func arrayOperation(_ Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	leftArray := left.(*object.Array)
+	rightArray := right.(*object.Array)
+
+	switch opcode {
+	case code.OpAdd:
+		elements := make([]object.Object, 0, len(leftArray.Elements)+len(rightArray.Elements))
+		elements = append(elements, leftArray.Elements...)
+
+		return &object.Array{Elements: append(elements, rightArray.Elements...)}, nil
+	case code.OpEqual:
+		return nativeBoolToBoolean(leftArray.Equal(rightArray)), nil
+	case code.OpNotEqual:
+		return nativeBoolToBoolean(!leftArray.Equal(rightArray)), nil
+	}
+
+	return unsupportedOperation(opcode, left, right)
+}
+
+func booleanOperation(_ Overflow, opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	leftValue := left.(*object.Boolean).Value
+	rightValue := right.(*object.Boolean).Value
+
+	switch opcode {
+	case code.OpEqual:
+		return nativeBoolToBoolean(leftValue == rightValue), nil
+	case code.OpNotEqual:
+		return nativeBoolToBoolean(leftValue != rightValue), nil
+	}
+
+	return unsupportedOperation(opcode, left, right)
+}
+
+// unsupportedOperation fails opcode on operands it is not defined for, except
+// for comparing null and enum members, which are only equal to themselves,
+// for equality with any value.
+func unsupportedOperation(opcode code.Opcode, left, right object.Object) (object.Object, error) {
+	switch opcode {
+	case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv:
+		return nil, errors.Errorf("type mismatch: %s %s %s", left.Type(), operators[opcode], right.Type())
+	}
+
+	if left == Null || right == Null || left.Type() == object.EnumMemberType {
+		switch opcode {
+		case code.OpEqual:
+			return nativeBoolToBoolean(left == right), nil
+		case code.OpNotEqual:
+			return nativeBoolToBoolean(left != right), nil
+		}
+	} else if left.Type() != right.Type() {
+		return nil, errors.Errorf("both operands must have same type, had: %s and %s", left.Type(), right.Type())
+	}
+
+	return nil, errors.Errorf("unable to compare variables of type %s and %s", left.Type(), right.Type())
+}
@@ -0,0 +1,63 @@
+package vm
+
+import (
+	"math"
+	"spike-interpreter-go/spike/code"
+
+	"github.com/pkg/errors"
+)
+
+// Overflow is what integer +, - and * do with results that do not fit in 64
+// bits.
+type Overflow int
+
+const (
+	// OverflowWrap wraps results around, as Go does. It is the default.
+	OverflowWrap Overflow = iota
+	// OverflowSaturate clamps results to the largest or smallest integer.
+	OverflowSaturate
+	// OverflowError fails the operation.
+	OverflowError
+)
+
+// WithOverflow makes integer +, - and * handle overflow as overflow says.
+func WithOverflow(overflow Overflow) Option {
+	return func(vm *VM) {
+		vm.overflow = overflow
+	}
+}
+
+// integerArithmetic applies +, - or * to left and right, handling overflow as
+// overflow says.
+func integerArithmetic(overflow Overflow, opcode code.Opcode, left, right int64) (int64, error) {
+	var result int64
+	var overflowed, negative bool
+	switch opcode {
+	case code.OpAdd:
+		result = left + right
+		overflowed = (left >= 0) == (right >= 0) && (result >= 0) != (left >= 0)
+		negative = left < 0
+	case code.OpSub:
+		result = left - right
+		overflowed = (left >= 0) != (right >= 0) && (result >= 0) != (left >= 0)
+		negative = left < 0
+	case code.OpMul:
+		result = left * right
+		overflowed = left != 0 && (result/left != right || left == -1 && right == math.MinInt64)
+		negative = (left < 0) != (right < 0)
+	}
+
+	if !overflowed || overflow == OverflowWrap {
+		return result, nil
+	}
+
+	if overflow == OverflowError {
+		return 0, errors.Errorf("integer overflow: %d %s %d", left, operators[opcode], right)
+	}
+
+	if negative {
+		return math.MinInt64, nil
+	}
+
+	return math.MaxInt64, nil
+}
@@ -0,0 +1,50 @@
+package vm
+
+import (
+	"math"
+	"spike-interpreter-go/spike/object"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run_overflow(t *testing.T) {
+	const max = "9223372036854775807"
+	const min = "(-9223372036854775807 - 1)"
+
+	testCases := []struct {
+		code          string
+		overflow      Overflow
+		expected      int64
+		expectedError string
+	}{
+		{code: max + " + 1", overflow: OverflowWrap, expected: math.MinInt64},
+		{code: max + " + 1", overflow: OverflowSaturate, expected: math.MaxInt64},
+		{code: max + " + 1", overflow: OverflowError, expectedError: "integer overflow: 9223372036854775807 + 1"},
+		{code: min + " - 1", overflow: OverflowWrap, expected: math.MaxInt64},
+		{code: min + " - 1", overflow: OverflowSaturate, expected: math.MinInt64},
+		{code: "0 - " + min, overflow: OverflowSaturate, expected: math.MaxInt64},
+		{code: "0 - " + min, overflow: OverflowError, expectedError: "integer overflow: 0 - -9223372036854775808"},
+		{code: max + " * -2", overflow: OverflowSaturate, expected: math.MinInt64},
+		{code: min + " * -1", overflow: OverflowSaturate, expected: math.MaxInt64},
+		{code: "-1 * " + min, overflow: OverflowError, expectedError: "integer overflow: -1 * -9223372036854775808"},
+		{code: "4611686018427387904 * 2", overflow: OverflowError, expectedError: "integer overflow: 4611686018427387904 * 2"},
+		{code: "-4611686018427387904 * 2", overflow: OverflowError, expected: math.MinInt64},
+		{code: max + " - 1 + 1", overflow: OverflowError, expected: math.MaxInt64},
+		{code: "let g = fn*() { yield " + max + " + 1 }; next(g())", overflow: OverflowSaturate, expected: math.MaxInt64},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			result, err := runInVM(testCase.code, WithOverflow(testCase.overflow))
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, &object.Integer{Value: testCase.expected}, result)
+		})
+	}
+}
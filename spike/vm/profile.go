@@ -0,0 +1,96 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"spike-interpreter-go/spike/object"
+	"strings"
+)
+
+// DefaultProfileInterval is how many instructions pass between samples unless
+// a profile asks for another interval.
+const DefaultProfileInterval = 1000
+
+// Profile samples the calls the VM is in every Interval instructions. Each
+// call is recorded as its function and the source line it is at, so the
+// samples map back to the script.
+type Profile struct {
+	Interval  int
+	samples   map[string]int
+	countdown int
+}
+
+func NewProfile(interval int) *Profile {
+	if interval <= 0 {
+		interval = DefaultProfileInterval
+	}
+
+	return &Profile{Interval: interval, samples: map[string]int{}, countdown: interval}
+}
+
+// WithProfile records samples of the run in profile.
+func WithProfile(profile *Profile) Option {
+	return func(vm *VM) {
+		vm.profile = profile
+	}
+}
+
+// Samples returns how many samples were taken.
+func (profile *Profile) Samples() int {
+	total := 0
+	for _, count := range profile.samples {
+		total += count
+	}
+
+	return total
+}
+
+// WriteFolded writes the samples as folded stacks, one line per distinct
+// stack with the outermost call first, as read by flamegraph.pl and
+// speedscope:
+//
+//	<main>:5;add:2 12
+func (profile *Profile) WriteFolded(w io.Writer) error {
+	stacks := make([]string, 0, len(profile.samples))
+	for stack := range profile.samples {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	for _, stack := range stacks {
+		_, err := fmt.Fprintf(w, "%s %d\n", stack, profile.samples[stack])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sample counts down to the next sample and takes it once due. Calls are
+// named like in StackTrace.
+func (vm *VM) sample() {
+	profile := vm.profile
+	profile.countdown--
+	if profile.countdown > 0 {
+		return
+	}
+	profile.countdown = profile.Interval
+
+	calls := make([]string, vm.framesIndex)
+	for i := 0; i < vm.framesIndex; i++ {
+		frame := vm.frames[i]
+		name := object.TopLevelFrameName
+		if i > 0 {
+			name = object.FrameName(frame.closure.Function.Name)
+		}
+
+		if position, ok := frame.closure.Function.SourceMap.Lookup(frame.ip); ok {
+			name = fmt.Sprintf("%s:%d", name, position.Line)
+		}
+		calls[i] = name
+	}
+
+	profile.samples[strings.Join(calls, ";")]++
+}
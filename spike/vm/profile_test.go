@@ -0,0 +1,26 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run_profile(t *testing.T) {
+	input := "let double = fn(x) {\n  x * 2\n};\ndouble(1) + 1"
+	profile := NewProfile(1)
+
+	_, err := runInVM(input, WithProfile(profile))
+	require.NoError(t, err)
+
+	output := &strings.Builder{}
+	require.NoError(t, profile.WriteFolded(output))
+	assert.Equal(t, "<main>:1 2\n<main>:4 6\n<main>:4;double:2 4\n", output.String())
+	assert.Equal(t, 12, profile.Samples())
+}
+
+func Test_NewProfile_defaultInterval(t *testing.T) {
+	assert.Equal(t, DefaultProfileInterval, NewProfile(0).Interval)
+}
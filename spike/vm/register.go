@@ -0,0 +1,213 @@
+package vm
+
+import (
+	"math/big"
+	"spike-interpreter-go/spike/code"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/object"
+
+	"github.com/pkg/errors"
+)
+
+// RegisterVM runs programs compiled with compiler.WithRegisters. It is an
+// experiment in how fast arithmetic can get without the stack, so it has none
+// of the VM's options, builtins or debugging support.
+type RegisterVM struct {
+	constants []object.Object
+	globals   []object.Object
+	main      *object.CompiledFunction
+
+	// registers holds the registers of all calls in progress, each call uses
+	// the ones after its caller's.
+	registers []object.Object
+	depth     int
+
+	result object.Object
+}
+
+var registerOperations = map[code.RegisterOpcode]code.Opcode{
+	code.RegAdd:         code.OpAdd,
+	code.RegSub:         code.OpSub,
+	code.RegMul:         code.OpMul,
+	code.RegDiv:         code.OpDiv,
+	code.RegEqual:       code.OpEqual,
+	code.RegNotEqual:    code.OpNotEqual,
+	code.RegGreaterThan: code.OpGreaterThan,
+	code.RegLessThan:    code.OpLessThan,
+}
+
+func NewRegisterVM(bytecode *compiler.Bytecode) *RegisterVM {
+	return &RegisterVM{
+		constants: bytecode.Constants,
+		globals:   make([]object.Object, GlobalsSize),
+		main: &object.CompiledFunction{
+			Registers:      bytecode.Registers,
+			RegistersCount: bytecode.RegistersCount,
+		},
+		registers: make([]object.Object, StackSize),
+	}
+}
+
+func (vm *RegisterVM) Run() error {
+	vm.depth = 0
+	result, err := vm.call(vm.main, 0)
+	vm.result = result
+
+	return err
+}
+
+// Result returns the value of the last expression statement of the program
+// the last run executed.
+func (vm *RegisterVM) Result() object.Object {
+	return vm.result
+}
+
+// window returns the count registers starting at base, growing the registers
+// when needed. Calls keep using the window they started with, so values
+// left in the old registers when they grow are never read again.
+func (vm *RegisterVM) window(base, count int) []object.Object {
+	if base+count > len(vm.registers) {
+		registers := make([]object.Object, 2*(base+count))
+		copy(registers, vm.registers)
+		vm.registers = registers
+	}
+
+	return vm.registers[base : base+count]
+}
+
+func (vm *RegisterVM) call(function *object.CompiledFunction, base int) (object.Object, error) {
+	registers := vm.window(base, function.RegistersCount)
+	top := base + function.RegistersCount
+
+	// Locals a call has not set yet read as undefined, not as what an
+	// earlier call left in their registers.
+	for i := function.ParametersCount; i < len(registers); i++ {
+		registers[i] = nil
+	}
+	instructions := function.Registers
+
+	for ip := 0; ip < len(instructions); ip++ {
+		instruction := instructions[ip]
+
+		switch opcode := instruction.Opcode(); opcode {
+		case code.RegLoadConstant:
+			registers[instruction.A()] = vm.constants[instruction.Bx()]
+
+		case code.RegLoadTrue:
+			registers[instruction.A()] = True
+
+		case code.RegLoadFalse:
+			registers[instruction.A()] = False
+
+		case code.RegLoadNull:
+			registers[instruction.A()] = Null
+
+		case code.RegMove:
+			registers[instruction.A()] = registers[instruction.B()]
+
+		case code.RegGetGlobal:
+			global := vm.globals[instruction.Bx()]
+			if global == nil {
+				return nil, errUndefined
+			}
+			registers[instruction.A()] = global
+
+		case code.RegSetGlobal:
+			vm.globals[instruction.Bx()] = registers[instruction.A()]
+
+		case code.RegAdd, code.RegSub, code.RegMul, code.RegDiv,
+			code.RegEqual, code.RegNotEqual, code.RegGreaterThan, code.RegLessThan:
+			left, right := registers[instruction.B()], registers[instruction.C()]
+			if left == nil || right == nil {
+				return nil, errUndefined
+			}
+
+			result, err := applyBinaryOperation(OverflowWrap, registerOperations[opcode], left, right)
+			if err != nil {
+				return nil, err
+			}
+			registers[instruction.A()] = result
+
+		case code.RegMinus:
+			if registers[instruction.B()] == nil {
+				return nil, errUndefined
+			}
+
+			result, err := registerMinus(registers[instruction.B()])
+			if err != nil {
+				return nil, err
+			}
+			registers[instruction.A()] = result
+
+		case code.RegBang:
+			if registers[instruction.B()] == nil {
+				return nil, errUndefined
+			}
+			registers[instruction.A()] = nativeBoolToBoolean(!object.Truthy(registers[instruction.B()]))
+
+		case code.RegJump:
+			ip = instruction.Bx() - 1
+
+		case code.RegJumpNotTrue:
+			if registers[instruction.A()] == nil {
+				return nil, errUndefined
+			}
+			if !object.Truthy(registers[instruction.A()]) {
+				ip = instruction.Bx() - 1
+			}
+
+		case code.RegCall:
+			if registers[instruction.B()] == nil {
+				return nil, errUndefined
+			}
+
+			callee, ok := registers[instruction.B()].(*object.CompiledFunction)
+			if !ok || callee.Registers == nil {
+				return nil, errors.Errorf("calling non-function: %s", registers[instruction.B()].Type())
+			}
+
+			argumentsCount := instruction.C()
+			if callee.ParametersCount != argumentsCount {
+				return nil, object.ArityError(callee.ParametersCount, argumentsCount, callee.Line)
+			}
+
+			if vm.depth >= MaxFrames {
+				return nil, errors.New("stack overflow")
+			}
+
+			arguments := registers[instruction.B()+1 : instruction.B()+1+argumentsCount]
+			copy(vm.window(top, callee.RegistersCount), arguments)
+
+			vm.depth++
+			result, err := vm.call(callee, top)
+			vm.depth--
+			if err != nil {
+				return nil, err
+			}
+			registers[instruction.A()] = result
+
+		case code.RegReturn:
+			if registers[instruction.A()] == nil {
+				return nil, errUndefined
+			}
+
+			return registers[instruction.A()], nil
+
+		default:
+			return nil, errors.Errorf("unknown register opcode: %s", opcode)
+		}
+	}
+
+	return Null, nil
+}
+
+func registerMinus(operand object.Object) (object.Object, error) {
+	switch operand := operand.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -operand.Value}, nil
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Neg(operand.Value)}, nil
+	}
+
+	return nil, errors.Errorf("type mismatch: -%s", operand.Type())
+}
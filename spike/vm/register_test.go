@@ -0,0 +1,158 @@
+package vm
+
+import (
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fibonacciProgram = "let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } };\nfib(20)"
+
+func Test_RegisterVM_Run(t *testing.T) {
+	testCases := []string{
+		"1 + 2 * 3 - 4 / 2",
+		"-(5 - 10)",
+		"\"spi\" + \"ke\"",
+		"\"spi\" + \"ke\" == \"spike\"",
+		"(1 < 2) == true",
+		"!(1 > 2) != false",
+		"if (1 > 2) { 10 }",
+		"if (1 < 2) { 10 } else { 20 }",
+		"let a = 2; a = a * 3; a + 1",
+		"let add = fn(a, b) { let c = a + b; c * 2 }; add(1, add(2, 3))",
+		"let f = fn(n) { if (n > 3) { return n } ; n = n + 10; n }; f(1) + f(5)",
+		"let f = fn() { }; f()",
+		"let f = fn() { let x = 1 }; f()",
+		"let a = 1; let a = a + 1; a",
+		"let f = fn(a) { let a = a * 2; a }; f(3)",
+		"let f = fn(a) { let b = a + (a = 10); b }; f(1)",
+		"let f = fn(a) { let b = (a = 10) + a; b }; f(1)",
+		fibonacciProgram,
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			expected, err := runInVM(testCase)
+			assert.NoError(t, err)
+
+			result, err := runInRegisterVM(testCase)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func Test_RegisterVM_RunWithError(t *testing.T) {
+	testCases := []struct {
+		code          string
+		expectedError string
+	}{
+		{
+			code:          "1 / 0",
+			expectedError: "division by zero",
+		},
+		{
+			code:          "1 + true",
+			expectedError: "type mismatch: integer + boolean",
+		},
+		{
+			code:          "1 == \"a\"",
+			expectedError: "both operands must have same type, had: integer and string",
+		},
+		{
+			code:          "let f = fn(a) { a };\nf(1, 2)",
+			expectedError: "wrong number of arguments: want 1, got 2 (function defined at line 1)",
+		},
+		{
+			code:          "let x = 1; x()",
+			expectedError: "calling non-function: integer",
+		},
+		{
+			code:          "let f = fn() { f() }; f()",
+			expectedError: "stack overflow",
+		},
+		{
+			code:          "let x = x + 1; x",
+			expectedError: "unable to resolve identifier: x at 1:9",
+		},
+		{
+			code:          "if (false) { let y = 1 }; y",
+			expectedError: "identifier used before it was defined",
+		},
+		{
+			code:          "let f = fn(n) { if (n > 0) { let y = n }; y + 1 }; f(1); f(0)",
+			expectedError: "identifier used before it was defined",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			_, err := runInRegisterVM(testCase.code)
+
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func Benchmark_Run_fibonacci(b *testing.B) {
+	b.Run("stack", func(b *testing.B) {
+		bytecode := compileBenchmark(b, fibonacciProgram)
+		for i := 0; i < b.N; i++ {
+			err := New(bytecode).Run()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("register", func(b *testing.B) {
+		bytecode := compileBenchmark(b, fibonacciProgram, compiler.WithRegisters())
+		for i := 0; i < b.N; i++ {
+			err := NewRegisterVM(bytecode).Run()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func compileBenchmark(b *testing.B, input string, options ...compiler.Option) *compiler.Bytecode {
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	c := compiler.New(options...)
+	err = c.Compile(program)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return c.Bytecode()
+}
+
+func runInRegisterVM(input string) (object.Object, error) {
+	program, err := parser.New(lexer.New(strings.NewReader(input))).ParseProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	c := compiler.New(compiler.WithRegisters())
+	err = c.Compile(program)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := NewRegisterVM(c.Bytecode())
+	err = machine.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return machine.Result(), nil
+}
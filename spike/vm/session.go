@@ -0,0 +1,71 @@
+package vm
+
+import (
+	"context"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser/ast"
+)
+
+// Session compiles and runs programs one after another, as the REPL, the
+// notebook kernel and embedding engines do, keeping the names, constants and
+// globals of the earlier ones. A program that fails is rolled back: the names
+// it defined are forgotten, so the names they shadowed are visible again.
+type Session struct {
+	SymbolTable *compiler.SymbolTable
+	Constants   []object.Object
+	Globals     []object.Object
+}
+
+// Compiled is a program a Session compiled that has not run yet.
+type Compiled struct {
+	bytecode    *compiler.Bytecode
+	symbolTable *compiler.SymbolTable
+}
+
+// NewSession starts a session with the names of symbolTable, typically the
+// builtins, in scope.
+func NewSession(symbolTable *compiler.SymbolTable) *Session {
+	return &Session{
+		SymbolTable: symbolTable,
+		Constants:   []object.Object{},
+		Globals:     make([]object.Object, GlobalsSize),
+	}
+}
+
+// Compile compiles program with the names of the earlier programs in scope.
+// The names it defines are only kept once Run ran it without failing.
+func (session *Session) Compile(program *ast.Program) (*Compiled, error) {
+	symbolTable := session.SymbolTable.Copy()
+	c := compiler.NewWithState(symbolTable, session.Constants)
+	err := c.Compile(program)
+	if err != nil {
+		return nil, err
+	}
+
+	bytecode := c.Bytecode()
+	session.Constants = bytecode.Constants
+
+	return &Compiled{bytecode: bytecode, symbolTable: symbolTable}, nil
+}
+
+// Run runs compiled, which must be the program compiled last, and returns the
+// machine it ran on, also when it failed, for its result or the position of
+// the error.
+func (session *Session) Run(ctx context.Context, compiled *Compiled, options ...Option) (*VM, error) {
+	machine := NewWithGlobalStore(compiled.bytecode, session.Globals, options...)
+	err := machine.RunContext(ctx)
+	if err != nil {
+		// Later programs reuse the slots of the names it defined and must not
+		// find the values it left there.
+		for i := session.SymbolTable.DefinitionsCount(); i < compiled.symbolTable.DefinitionsCount(); i++ {
+			session.Globals[i] = nil
+		}
+
+		return machine, err
+	}
+
+	session.SymbolTable = compiled.symbolTable
+
+	return machine, nil
+}
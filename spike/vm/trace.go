@@ -0,0 +1,125 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"spike-interpreter-go/spike/object"
+	"time"
+)
+
+// mainThread is the trace thread of the VM a script runs on. Generators run
+// on machines of their own and get the next threads.
+const mainThread = 1
+
+// Trace records when every call the VM makes starts and ends, to be written
+// in the trace event format of Chrome's about:tracing, Perfetto and
+// speedscope. It keeps two events per call, so it grows with the run.
+type Trace struct {
+	events []traceEvent
+	// open counts the calls of each thread that did not end yet.
+	open    map[int]int
+	threads int
+	start   time.Time
+	now     func() time.Time
+}
+
+type traceEvent struct {
+	Name      string            `json:"name,omitempty"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Process   int               `json:"pid"`
+	Thread    int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+func NewTrace() *Trace {
+	return &Trace{open: map[int]int{}, start: time.Now(), now: time.Now}
+}
+
+// WithTrace records the calls of the run in trace.
+func WithTrace(trace *Trace) Option {
+	return func(vm *VM) {
+		vm.trace = trace
+		vm.traceThread = trace.newThread(object.TopLevelFrameName)
+	}
+}
+
+// Events returns how many events were recorded.
+func (trace *Trace) Events() int {
+	return len(trace.events)
+}
+
+// WriteJSON writes the trace as a JSON object holding its traceEvents, one
+// per line, with timestamps in microseconds since the trace was created.
+// Calls still running end at the time of writing, as they do when a script
+// fails.
+func (trace *Trace) WriteJSON(w io.Writer) error {
+	events := append([]traceEvent{}, trace.events...)
+	timestamp := trace.timestamp()
+	for thread := 1; thread <= trace.threads; thread++ {
+		for i := 0; i < trace.open[thread]; i++ {
+			events = append(events, traceEvent{Phase: "E", Timestamp: timestamp, Process: 1, Thread: thread})
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("{\"traceEvents\": [\n")
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	for i, event := range events {
+		if i > 0 {
+			buffer.WriteString(",\n")
+		}
+		err := encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		// Encode ends every event with a line break.
+		buffer.Truncate(buffer.Len() - 1)
+	}
+	buffer.WriteString("\n], \"displayTimeUnit\": \"ms\"}\n")
+
+	_, err := buffer.WriteTo(w)
+	return err
+}
+
+// newThread names the next thread after the first function running on it.
+func (trace *Trace) newThread(name string) int {
+	trace.threads++
+	trace.events = append(trace.events, traceEvent{
+		Name:    "thread_name",
+		Phase:   "M",
+		Process: 1,
+		Thread:  trace.threads,
+		Args:    map[string]string{"name": name},
+	})
+
+	return trace.threads
+}
+
+func (trace *Trace) begin(thread int, name string) {
+	trace.open[thread]++
+	trace.events = append(trace.events, traceEvent{Name: name, Category: "call", Phase: "B", Timestamp: trace.timestamp(), Process: 1, Thread: thread})
+}
+
+func (trace *Trace) end(thread int) {
+	if trace.open[thread] == 0 {
+		return
+	}
+
+	trace.open[thread]--
+	trace.events = append(trace.events, traceEvent{Phase: "E", Timestamp: trace.timestamp(), Process: 1, Thread: thread})
+}
+
+// endAll ends the calls of thread left running by an error.
+func (trace *Trace) endAll(thread int) {
+	for trace.open[thread] > 0 {
+		trace.end(thread)
+	}
+}
+
+func (trace *Trace) timestamp() int64 {
+	return int64(trace.now().Sub(trace.start) / time.Microsecond)
+}
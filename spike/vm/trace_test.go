@@ -0,0 +1,59 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tickingClock returns a clock moving one microsecond every time it is read.
+func tickingClock() func() time.Time {
+	now := time.Time{}
+	return func() time.Time {
+		now = now.Add(time.Microsecond)
+		return now
+	}
+}
+
+func Test_Run_trace(t *testing.T) {
+	input := "let double = fn(x) { x * 2 }; let g = fn*() { yield double(1) }; next(g()); double(2)"
+	trace := NewTrace()
+	trace.start, trace.now = time.Time{}, tickingClock()
+
+	_, err := runInVM(input, WithTrace(trace))
+	require.NoError(t, err)
+
+	output := &strings.Builder{}
+	require.NoError(t, trace.WriteJSON(output))
+	// The suspended generator ends when the trace is written.
+	assert.Equal(t, `{"traceEvents": [
+{"name":"thread_name","ph":"M","ts":0,"pid":1,"tid":1,"args":{"name":"<main>"}},
+{"name":"<main>","cat":"call","ph":"B","ts":1,"pid":1,"tid":1},
+{"name":"thread_name","ph":"M","ts":0,"pid":1,"tid":2,"args":{"name":"g"}},
+{"name":"g","cat":"call","ph":"B","ts":2,"pid":1,"tid":2},
+{"name":"double","cat":"call","ph":"B","ts":3,"pid":1,"tid":2},
+{"ph":"E","ts":4,"pid":1,"tid":2},
+{"name":"double","cat":"call","ph":"B","ts":5,"pid":1,"tid":1},
+{"ph":"E","ts":6,"pid":1,"tid":1},
+{"ph":"E","ts":7,"pid":1,"tid":1},
+{"ph":"E","ts":8,"pid":1,"tid":2}
+], "displayTimeUnit": "ms"}
+`, output.String())
+	assert.Equal(t, 9, trace.Events())
+}
+
+func Test_Run_traceEndsFailedCalls(t *testing.T) {
+	trace := NewTrace()
+	trace.start, trace.now = time.Time{}, tickingClock()
+
+	_, err := runInVM("let f = fn() { len(1) }; f()", WithTrace(trace))
+	require.Error(t, err)
+
+	output := &strings.Builder{}
+	require.NoError(t, trace.WriteJSON(output))
+	assert.Equal(t, 2, strings.Count(output.String(), `"ph":"B"`))
+	assert.Equal(t, 2, strings.Count(output.String(), `"ph":"E"`))
+}
@@ -1,10 +1,20 @@
 package vm
 
 import (
+	"bufio"
+	"context"
 	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
 	"spike-interpreter-go/spike/code"
 	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/diagnostic"
+	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -13,27 +23,130 @@ const (
 	StackSize   = 2048
 	MaxFrames   = 1024
 	GlobalsSize = 65536
+
+	interruptCheckInterval = 1024
 )
 
 var (
-	True  = &object.Boolean{Value: true}
-	False = &object.Boolean{Value: false}
-	Null  = &object.Null{}
+	True  = &object.True
+	False = &object.False
+	Null  = &object.NullObject
 )
 
+// errUndefined is returned reading a global or a local whose let has not run,
+// because the branch holding it was not taken.
+var errUndefined = errors.New("identifier used before it was defined")
+
 type VM struct {
 	constants []object.Object
 	globals   []object.Object
+	builtins  []*object.BuiltinFunction
+	modules   map[string]*object.Module
 
 	stack []object.Object
 	sp    int
 
 	frames      []*Frame
 	framesIndex int
+
+	stdout io.Writer
+	stderr io.Writer
+	stdin  *bufio.Reader
+	random *rand.Rand
+	policy *object.Policy
+	args   []string
+
+	ctx      context.Context
+	executed int
+
+	maxSteps int
+	steps    int
+
+	overflow Overflow
+
+	yielded object.Object
+	// resuming counts the generators being resumed, shared by the machine
+	// that created the first generator and the machines of all generators.
+	resuming *int
+
+	// err is the last error a call from a builtin failed with and
+	// errorPosition where it happened, or where the last run stopped.
+	err           error
+	errorPosition lexer.Position
+
+	debugger    Debugger
+	profile     *Profile
+	trace       *Trace
+	traceThread int
+}
+
+type Option func(vm *VM)
+
+func WithStdout(stdout io.Writer) Option {
+	return func(vm *VM) {
+		vm.stdout = stdout
+	}
+}
+
+func WithStderr(stderr io.Writer) Option {
+	return func(vm *VM) {
+		vm.stderr = stderr
+	}
+}
+
+func WithStdin(stdin io.Reader) Option {
+	return func(vm *VM) {
+		vm.stdin = object.BufferedReader(stdin)
+	}
+}
+
+// WithRand makes the rand, randInt and seed builtins use random, so runs can
+// be reproduced by seeding it.
+func WithRand(random *rand.Rand) Option {
+	return func(vm *VM) {
+		vm.random = random
+	}
 }
 
-func New(bytecode *compiler.Bytecode) *VM {
-	mainFn := &object.CompiledFunction{Instructions: bytecode.Instructions}
+// WithPolicy restricts what builtins may do, see object.Policy.
+func WithPolicy(policy *object.Policy) Option {
+	return func(vm *VM) {
+		vm.policy = policy
+	}
+}
+
+// WithBuiltins replaces object.Builtins as the functions OpGetBuiltin loads,
+// for programs compiled with more builtins defined.
+func WithBuiltins(builtins []*object.BuiltinFunction) Option {
+	return func(vm *VM) {
+		vm.builtins = builtins
+	}
+}
+
+// WithModules sets the modules `import` statements bind, by name.
+func WithModules(modules map[string]*object.Module) Option {
+	return func(vm *VM) {
+		vm.modules = modules
+	}
+}
+
+// WithArgs sets the command line arguments returned by the args builtin.
+func WithArgs(args []string) Option {
+	return func(vm *VM) {
+		vm.args = args
+	}
+}
+
+// WithMaxSteps stops a run with an error once it has executed more than
+// steps instructions. Zero means no limit.
+func WithMaxSteps(steps int) Option {
+	return func(vm *VM) {
+		vm.maxSteps = steps
+	}
+}
+
+func New(bytecode *compiler.Bytecode, options ...Option) *VM {
+	mainFn := &object.CompiledFunction{Instructions: bytecode.Instructions, SourceMap: bytecode.SourceMap}
 	mainClosure := &object.Closure{
 		Function:      mainFn,
 		FreeVariables: nil,
@@ -43,30 +156,255 @@ func New(bytecode *compiler.Bytecode) *VM {
 	frames := make([]*Frame, MaxFrames)
 	frames[0] = mainFrame
 
-	return &VM{
+	vm := &VM{
 		constants:   bytecode.Constants,
+		builtins:    object.Builtins,
 		stack:       make([]object.Object, StackSize),
 		globals:     make([]object.Object, GlobalsSize),
 		sp:          0,
 		frames:      frames,
 		framesIndex: 1,
+		stdout:      os.Stdout,
+		stderr:      os.Stderr,
+		stdin:       object.Stdin,
+		policy:      object.DefaultPolicy(),
+	}
+	for _, option := range options {
+		option(vm)
 	}
+
+	return vm
 }
 
-func NewWithGlobalStore(bytecode *compiler.Bytecode, globals []object.Object) *VM {
-	vm := New(bytecode)
+func NewWithGlobalStore(bytecode *compiler.Bytecode, globals []object.Object, options ...Option) *VM {
+	vm := New(bytecode, options...)
 	vm.globals = globals
 	return vm
 }
 
+func (vm *VM) Stdout() io.Writer {
+	return vm.stdout
+}
+
+func (vm *VM) Stderr() io.Writer {
+	return vm.stderr
+}
+
+func (vm *VM) Rand() *rand.Rand {
+	if vm.random == nil {
+		vm.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return vm.random
+}
+
+func (vm *VM) Stdin() *bufio.Reader {
+	return vm.stdin
+}
+
+func (vm *VM) Context() context.Context {
+	if vm.ctx == nil {
+		return context.Background()
+	}
+
+	return vm.ctx
+}
+
+func (vm *VM) Policy() *object.Policy {
+	return vm.policy
+}
+
+func (vm *VM) Args() []string {
+	return vm.args
+}
+
 func (vm *VM) Run() error {
+	return vm.RunContext(context.Background())
+}
+
+// RunContext executes the bytecode like Run, stopping with the context's
+// error once the context is cancelled or its deadline passes.
+func (vm *VM) RunContext(ctx context.Context) error {
+	vm.ctx = ctx
+	defer func() { vm.ctx = nil }()
+
+	vm.err, vm.errorPosition = nil, lexer.Position{}
+	vm.steps = 0
+	if vm.trace != nil {
+		vm.trace.begin(vm.traceThread, object.TopLevelFrameName)
+		defer vm.trace.endAll(vm.traceThread)
+	}
+
+	err := vm.execute(0)
+	if err != nil && err != vm.err {
+		vm.recordErrorPosition()
+	}
+
+	return err
+}
+
+// recordErrorPosition remembers where the current frame stopped. Frames are
+// left as they were when an error happened, so it is where the error came
+// from.
+func (vm *VM) recordErrorPosition() {
+	frame := vm.currentFrame()
+	vm.errorPosition, _ = frame.closure.Function.SourceMap.Lookup(frame.ip)
+}
+
+// ErrorPosition returns where in the source the instruction that failed the
+// last run came from, if the bytecode maps it back to the source.
+func (vm *VM) ErrorPosition() (lexer.Position, bool) {
+	return vm.errorPosition, vm.errorPosition.Line > 0
+}
+
+// RuntimeError returns err, which the last run failed with, as a
+// *diagnostic.RuntimeError at ErrorPosition. Exiting is not an error, an
+// *object.ExitError is returned as it is.
+func (vm *VM) RuntimeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, exit := errors.Cause(err).(*object.ExitError); exit {
+		return err
+	}
+
+	return diagnostic.NewRuntimeError(err, vm.errorPosition)
+}
+
+// Call invokes function from within a builtin, running closures on this VM
+// until they return. A call that fails leaves frames and the stack as they
+// were before it, so builtins may go on after an error.
+func (vm *VM) Call(function object.Object, args ...object.Object) (object.Object, error) {
+	switch function := function.(type) {
+	case *object.Closure:
+		framesIndex, sp := vm.framesIndex, vm.sp
+
+		result, err := vm.callFromBuiltin(function, args)
+		if err != nil {
+			if err != vm.err {
+				vm.recordErrorPosition()
+				vm.err = err
+			}
+			for vm.framesIndex > framesIndex {
+				vm.popFrame()
+			}
+			vm.sp = sp
+
+			return nil, err
+		}
+
+		return result, nil
+
+	case *object.BuiltinFunction:
+		err := vm.policy.CheckBuiltin(function.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := function.Function(vm, args...)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			result = Null
+		}
+
+		return result, nil
+	}
+
+	return nil, errors.Errorf("calling non-function: %s", function.Type())
+}
+
+func (vm *VM) callFromBuiltin(closure *object.Closure, args []object.Object) (object.Object, error) {
+	err := vm.push(closure)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		err = vm.push(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = vm.callClosure(closure, len(args))
+	if err != nil {
+		return nil, err
+	}
+
+	if !closure.Function.Generator {
+		err = vm.execute(vm.framesIndex - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vm.pop()
+}
+
+func (vm *VM) CallDepth() int {
+	return vm.framesIndex - 1
+}
+
+func (vm *VM) StackTrace() []string {
+	trace := make([]string, 0, vm.framesIndex)
+	for i := vm.framesIndex - 1; i > 0; i-- {
+		trace = append(trace, object.FrameName(vm.frames[i].closure.Function.Name))
+	}
+
+	return append(trace, object.TopLevelFrameName)
+}
+
+// execute runs instructions until the frame count drops to returnFrames or
+// the outermost frame runs out of instructions.
+func (vm *VM) execute(returnFrames int) (err error) {
 	var ip int
 	var instructions code.Instructions
 	var op code.Opcode
 
-	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+	defer func() {
+		if underflow, ok := err.(*StackUnderflowError); ok && !underflow.located {
+			underflow.IP, underflow.Op, underflow.located = ip, op, true
+		}
+	}()
+
+	var done <-chan struct{}
+	if vm.ctx != nil {
+		done = vm.ctx.Done()
+	}
+
+	for vm.framesIndex > returnFrames && vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		if done != nil {
+			vm.executed++
+			if vm.executed%interruptCheckInterval == 0 {
+				select {
+				case <-done:
+					return vm.ctx.Err()
+				default:
+				}
+			}
+		}
+
+		if vm.maxSteps > 0 {
+			vm.steps++
+			if vm.steps > vm.maxSteps {
+				return &object.StepLimitError{Limit: vm.maxSteps}
+			}
+		}
+
 		vm.currentFrame().ip++
 
+		if vm.debugger != nil {
+			err := vm.debug()
+			if err != nil {
+				return err
+			}
+		}
+
+		if vm.profile != nil {
+			vm.sample()
+		}
+
 		ip = vm.currentFrame().ip
 		instructions = vm.currentFrame().Instructions()
 		op = code.Opcode(instructions[ip])
@@ -76,26 +414,26 @@ func (vm *VM) Run() error {
 			index := binary.BigEndian.Uint16(instructions[ip+1:])
 			vm.currentFrame().ip += 2
 
-			err := vm.push(vm.constants[index])
-			if err != nil {
-				return err
-
+			// Folded literals must evaluate to a fresh value each time,
+			// freezing one must not freeze the constant. Frozen constants
+			// can not change and are shared.
+			constant := vm.constants[index]
+			if object.CheckMutable(constant) == nil {
+				switch constant.(type) {
+				case *object.Array, *object.Hash:
+					constant = object.DeepCopy(constant)
+				}
 			}
 
-		case code.OpAdd:
-			err := vm.executePlusOperation()
+			err := vm.push(constant)
 			if err != nil {
 				return err
-			}
 
-		case code.OpSub, code.OpMul, code.OpDiv:
-			err := vm.executeBinaryIntegerOperation(op)
-			if err != nil {
-				return err
 			}
 
-		case code.OpEqual, code.OpNotEqual, code.OpGreaterThan:
-			err := vm.executeComparison(op)
+		case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv,
+			code.OpEqual, code.OpNotEqual, code.OpGreaterThan, code.OpLessThan:
+			err := vm.executeBinaryOperation(op)
 			if err != nil {
 				return err
 			}
@@ -113,7 +451,10 @@ func (vm *VM) Run() error {
 			}
 
 		case code.OpPop:
-			vm.pop()
+			_, err := vm.pop()
+			if err != nil {
+				return err
+			}
 
 		case code.OpBang:
 			err := vm.executeBangOperator()
@@ -135,8 +476,11 @@ func (vm *VM) Run() error {
 			jumpIndex := binary.BigEndian.Uint16(instructions[ip+1:])
 			vm.currentFrame().ip += 2
 
-			condition := vm.pop().(*object.Boolean).Value
-			if !condition {
+			condition, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			if !object.Truthy(condition) {
 				vm.currentFrame().ip = int(jumpIndex) - 1
 			}
 
@@ -150,13 +494,22 @@ func (vm *VM) Run() error {
 			globalIndex := binary.BigEndian.Uint16(instructions[ip+1:])
 			vm.currentFrame().ip += 2
 
-			vm.globals[globalIndex] = vm.pop()
+			value, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			vm.globals[globalIndex] = value
 
 		case code.OpGetGlobal:
 			globalIndex := binary.BigEndian.Uint16(instructions[ip+1:])
 			vm.currentFrame().ip += 2
 
-			err := vm.push(vm.globals[globalIndex])
+			global := vm.globals[globalIndex]
+			if global == nil {
+				return errUndefined
+			}
+
+			err := vm.push(global)
 			if err != nil {
 				return err
 			}
@@ -165,6 +518,11 @@ func (vm *VM) Run() error {
 			elementsCount := int(binary.BigEndian.Uint16(instructions[ip+1:]))
 			vm.currentFrame().ip += 2
 
+			err := vm.requireStack(elementsCount)
+			if err != nil {
+				return err
+			}
+
 			elements := make([]object.Object, elementsCount)
 			for i := 0; i < elementsCount; i++ {
 				elements[i] = vm.stack[vm.sp-elementsCount+i]
@@ -173,59 +531,185 @@ func (vm *VM) Run() error {
 			vm.sp -= elementsCount
 
 			array := &object.Array{Elements: elements}
-			err := vm.push(array)
+			err = vm.push(array)
 			if err != nil {
 				return err
 			}
 
+		case code.OpYield:
+			yielded, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			vm.yielded = yielded
+
+			// The yield expression evaluates to null once the generator is
+			// resumed, which continues right after this instruction.
+			err = vm.push(Null)
+			if err != nil {
+				return err
+			}
+
+			return nil
+
+		case code.OpImport:
+			name, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			module, ok := vm.modules[name.(*object.String).Value]
+			if !ok {
+				return errors.Errorf("unknown module %s", name.(*object.String).Value)
+			}
+
+			err = vm.push(module)
+			if err != nil {
+				return err
+			}
+
+		case code.OpMember:
+			name, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			value, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			member, err := object.Member(value, name.(*object.String).Value)
+			if err != nil {
+				return err
+			}
+
+			err = vm.push(member)
+			if err != nil {
+				return err
+			}
+
+		case code.OpTuple:
+			elementsCount := int(binary.BigEndian.Uint16(instructions[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			err := vm.requireStack(elementsCount)
+			if err != nil {
+				return err
+			}
+
+			elements := make([]object.Object, elementsCount)
+			copy(elements, vm.stack[vm.sp-elementsCount:vm.sp])
+			vm.sp -= elementsCount
+
+			err = vm.push(&object.Tuple{Elements: elements})
+			if err != nil {
+				return err
+			}
+
+		case code.OpDestructure:
+			namesCount := int(binary.BigEndian.Uint16(instructions[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			value, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			elements, err := object.Destructure(value, namesCount)
+			if err != nil {
+				return err
+			}
+
+			// Pushed in reverse so the following set instructions, one per
+			// name, pop them in order.
+			for i := len(elements) - 1; i >= 0; i-- {
+				err = vm.push(elements[i])
+				if err != nil {
+					return err
+				}
+			}
+
 		case code.OpHash:
 			elementsCount := int(binary.BigEndian.Uint16(instructions[ip+1:]))
 			vm.currentFrame().ip += 2
 
-			pairs := make(map[object.HashKey]object.HashPair)
+			err := vm.requireStack(elementsCount)
+			if err != nil {
+				return err
+			}
+
+			hash := object.NewHash(elementsCount / 2)
 
 			for i := 0; i < elementsCount; i += 2 {
-				key := vm.stack[vm.sp-elementsCount+i].(object.Hashable)
+				key := vm.stack[vm.sp-elementsCount+i]
 				value := vm.stack[vm.sp-elementsCount+i+1]
 
-				pairs[key.GetHashKey()] = object.HashPair{
-					Key:   key.(object.Object),
-					Value: value,
+				_, err := object.HashKeyOf(key)
+				if err != nil {
+					return err
 				}
+
+				hash.Set(key, value)
 			}
 
-			hash := &object.Hash{Pairs: pairs}
-			err := vm.push(hash)
+			vm.sp -= elementsCount
+
+			err = vm.push(hash)
 			if err != nil {
 				return err
 			}
 
 		case code.OpIndex:
-			index := vm.pop()
-			array := vm.pop()
+			array, index, err := vm.popTwo()
+			if err != nil {
+				return err
+			}
 
 			switch array := array.(type) {
+			case *object.String:
+				position, ok := index.(*object.Integer)
+				if !ok {
+					return errors.Errorf("String index must be an integer, got: %s", index.Type())
+				}
+
+				character, err := array.Character(position.Value)
+				if err != nil {
+					return err
+				}
+
+				err = vm.push(character)
+				if err != nil {
+					return err
+				}
 			case *object.Array:
-				index, ok := index.(*object.Integer)
+				position, ok := index.(*object.Integer)
 				if !ok {
 					return errors.Errorf("Array index must be an integer, got: %s", index.Type())
 				}
 
-				if index.Value < 0 || index.Value >= int64(len(array.Elements)) {
-					err := vm.push(Null)
-					if err != nil {
-						return err
-					}
-				} else {
-					err := vm.push(array.Elements[index.Value])
-					if err != nil {
-						return err
-					}
+				if position.Value < 0 || position.Value >= int64(len(array.Elements)) {
+					return object.IndexOutOfRange(position.Value, len(array.Elements))
+				}
+
+				err := vm.push(array.Elements[position.Value])
+				if err != nil {
+					return err
+				}
+			case *object.Bytes:
+				position, ok := index.(*object.Integer)
+				if !ok {
+					return errors.Errorf("Bytes index must be an integer, got: %s", index.Type())
+				}
+
+				if position.Value < 0 || position.Value >= int64(len(array.Value)) {
+					return object.IndexOutOfRange(position.Value, len(array.Value))
+				}
+
+				err := vm.push(&object.Integer{Value: int64(array.Value[position.Value])})
+				if err != nil {
+					return err
 				}
 			case *object.Hash:
 				hashKey, ok := index.(object.Hashable)
 				if !ok {
-					return errors.Errorf("Object of type %s can not be used as a hash key", index.Type())
+					return errors.Errorf("unusable as hash key: %s", index.Type())
 				}
 
 				value, err := array.Get(hashKey)
@@ -240,59 +724,84 @@ func (vm *VM) Run() error {
 						return err
 					}
 				}
+			default:
+				return errors.Errorf("index operator not supported: %s", array.Type())
+			}
+
+		case code.OpSetIndex:
+			value, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			container, index, err := vm.popTwo()
+			if err != nil {
+				return err
+			}
+
+			err = object.SetIndex(container, index, value)
+			if err != nil {
+				return err
+			}
+
+			err = vm.push(value)
+			if err != nil {
+				return err
 			}
 
 		case code.OpCall:
 			argumentsCount := int(instructions[ip+1])
 			vm.currentFrame().ip++
+			err := vm.requireStack(argumentsCount + 1)
+			if err != nil {
+				return err
+			}
 			callee := vm.stack[vm.sp-1-argumentsCount]
 
 			switch callee := callee.(type) {
 			case *object.Closure:
-				if callee.Function.ParametersCount != argumentsCount {
-					return errors.Errorf(
-						"mismatched number of function call arguments. Expected %d, got %d",
-						callee.Function.ParametersCount,
-						argumentsCount,
-					)
+				err := vm.callClosure(callee, argumentsCount)
+				if err != nil {
+					return err
 				}
 
-				frame := NewFrame(callee, vm.sp-argumentsCount)
-				vm.pushFrame(frame)
-				vm.sp = frame.basePointer + callee.Function.LocalsCount
-
 			case *object.BuiltinFunction:
-				args := vm.stack[vm.sp-argumentsCount : vm.sp]
+				err := vm.policy.CheckBuiltin(callee.Name)
+				if err != nil {
+					return err
+				}
 
-				result, err := callee.Function(args...)
+				args := vm.stack[vm.sp-argumentsCount : vm.sp]
+				result, err := callee.Function(vm, args...)
 				if err != nil {
 					return err
 				}
+				if result == nil {
+					result = Null
+				}
+
+				vm.sp = vm.sp - argumentsCount - 1
 				err = vm.push(result)
 				if err != nil {
 					return err
 				}
 
 			default:
-				return errors.Errorf("Calling non-function %T", callee)
+				return errors.Errorf("calling non-function: %s", callee.Type())
 			}
 
 		case code.OpReturnValue:
-			returnValue := vm.pop()
-
-			frame := vm.popFrame()
-			vm.sp = frame.basePointer - 1
+			returnValue, err := vm.pop()
+			if err != nil {
+				return err
+			}
 
-			err := vm.push(returnValue)
+			err = vm.returnFrom(vm.popFrame(), returnValue)
 			if err != nil {
 				return err
 			}
 
 		case code.OpReturn:
-			frame := vm.popFrame()
-			vm.sp = frame.basePointer - 1
-
-			err := vm.push(Null)
+			err := vm.returnFrom(vm.popFrame(), Null)
 			if err != nil {
 				return err
 			}
@@ -301,13 +810,21 @@ func (vm *VM) Run() error {
 			index := int(instructions[ip+1])
 			vm.currentFrame().ip++
 
-			vm.stack[vm.currentFrame().basePointer+index] = vm.pop()
+			value, err := vm.pop()
+			if err != nil {
+				return err
+			}
+			vm.stack[vm.currentFrame().basePointer+index] = value
 
 		case code.OpGetLocal:
 			index := int(instructions[ip+1])
 			vm.currentFrame().ip++
 
 			value := vm.stack[vm.currentFrame().basePointer+index]
+			if value == nil {
+				return errUndefined
+			}
+
 			err := vm.push(value)
 			if err != nil {
 				return err
@@ -317,7 +834,7 @@ func (vm *VM) Run() error {
 			index := int(instructions[ip+1])
 			vm.currentFrame().ip++
 
-			definition := object.Builtins[index]
+			definition := vm.builtins[index]
 
 			err := vm.push(definition)
 			if err != nil {
@@ -334,6 +851,11 @@ func (vm *VM) Run() error {
 				return errors.Errorf("%+v is not a function", vm.constants[functionIndex])
 			}
 
+			err := vm.requireStack(freeVarsCount)
+			if err != nil {
+				return err
+			}
+
 			freeVariables := make([]object.Object, freeVarsCount)
 			for i := 0; i < freeVarsCount; i++ {
 				freeVariables[i] = vm.stack[vm.sp-freeVarsCount+i]
@@ -344,7 +866,7 @@ func (vm *VM) Run() error {
 				Function:      function,
 				FreeVariables: freeVariables,
 			}
-			err := vm.push(closure)
+			err = vm.push(closure)
 			if err != nil {
 				return err
 			}
@@ -364,110 +886,62 @@ func (vm *VM) Run() error {
 	return nil
 }
 
-func (vm *VM) executePlusOperation() error {
-	right := vm.pop()
-	left := vm.pop()
-
-	if left.Type() == object.IntegerType && right.Type() == object.IntegerType {
-		leftValue := left.(*object.Integer).Value
-		rightValue := right.(*object.Integer).Value
-
-		result := &object.Integer{Value: leftValue + rightValue}
-		return vm.push(result)
-	} else if left.Type() == object.StringType && right.Type() == object.StringType {
-		leftValue := left.(*object.String).Value
-		rightValue := right.(*object.String).Value
-
-		result := &object.String{Value: leftValue + rightValue}
-		return vm.push(result)
-	}
-
-	return nil
-}
-
-func (vm *VM) executeBinaryIntegerOperation(opcode code.Opcode) error {
-	right := vm.pop()
-	left := vm.pop()
-	leftValue := left.(*object.Integer).Value
-	rightValue := right.(*object.Integer).Value
-
-	var result int64
-	switch opcode {
-	case code.OpSub:
-		result = leftValue - rightValue
-	case code.OpMul:
-		result = leftValue * rightValue
-	case code.OpDiv:
-		result = leftValue / rightValue
+func (vm *VM) callClosure(closure *object.Closure, argumentsCount int) error {
+	if closure.Function.ParametersCount != argumentsCount {
+		return object.ArityError(closure.Function.ParametersCount, argumentsCount, closure.Function.Line)
 	}
-	return vm.push(&object.Integer{Value: result})
-}
 
-func (vm *VM) executeComparison(op code.Opcode) error {
-	right := vm.pop()
-	left := vm.pop()
+	if closure.Function.Generator {
+		args := vm.stack[vm.sp-argumentsCount : vm.sp]
+		generator, err := vm.newGenerator(closure, args)
+		if err != nil {
+			return err
+		}
 
-	if right.Type() != left.Type() {
-		return errors.Errorf("both operands must have same type, had: %s and %s", left.Type(), right.Type())
+		vm.sp -= argumentsCount + 1
+		return vm.push(generator)
 	}
 
-	if right.Type() == object.IntegerType {
-		return vm.executeIntegerComparison(left, right, op)
+	frame := NewFrame(closure, vm.sp-argumentsCount)
+	err := vm.pushFrame(frame)
+	if err != nil {
+		return err
 	}
 
-	if right.Type() == object.BooleanType {
-		return vm.executeBooleanComparison(left, right, op)
+	// Locals a call has not set yet read as undefined, not as what an
+	// earlier call left in their slots.
+	for i := vm.sp; i < frame.basePointer+closure.Function.LocalsCount; i++ {
+		vm.stack[i] = nil
 	}
+	vm.sp = frame.basePointer + closure.Function.LocalsCount
 
-	return errors.Errorf("unable to compare variables of type %s and %s", left.Type(), right.Type())
+	return nil
 }
 
-func (vm *VM) executeIntegerComparison(left object.Object, right object.Object, op code.Opcode) error {
-	leftInt := left.(*object.Integer).Value
-	rightInt := right.(*object.Integer).Value
-
-	switch op {
-	case code.OpEqual:
-		return vm.push(nativeBoolToBoolean(leftInt == rightInt))
-	case code.OpNotEqual:
-		return vm.push(nativeBoolToBoolean(leftInt != rightInt))
-	case code.OpGreaterThan:
-		return vm.push(nativeBoolToBoolean(leftInt > rightInt))
+func (vm *VM) executeBangOperator() error {
+	operand, err := vm.pop()
+	if err != nil {
+		return err
 	}
 
-	return errors.Errorf("unexpected operation: %d", op)
+	return vm.push(nativeBoolToBoolean(!object.Truthy(operand)))
 }
 
-func (vm *VM) executeBooleanComparison(left object.Object, right object.Object, op code.Opcode) error {
-	leftBool := left.(*object.Boolean).Value
-	rightBool := right.(*object.Boolean).Value
-
-	switch op {
-	case code.OpEqual:
-		return vm.push(nativeBoolToBoolean(leftBool == rightBool))
-	case code.OpNotEqual:
-		return vm.push(nativeBoolToBoolean(leftBool != rightBool))
+func (vm *VM) executeMinusOperator() error {
+	operand, err := vm.pop()
+	if err != nil {
+		return err
+	}
+	if bigInt, ok := operand.(*object.BigInt); ok {
+		return vm.push(&object.BigInt{Value: new(big.Int).Neg(bigInt.Value)})
 	}
 
-	return errors.Errorf("unexpected operation: %d", op)
-}
-
-func (vm *VM) executeBangOperator() error {
-	operand := vm.pop()
-
-	switch operand {
-	case True:
-		return vm.push(False)
-	case False:
-		return vm.push(True)
-	default:
-		return errors.Errorf("invalid operand for bang prefix operator: %#v", operand)
+	integer, ok := operand.(*object.Integer)
+	if !ok {
+		return errors.Errorf("type mismatch: -%s", operand.Type())
 	}
-}
 
-func (vm *VM) executeMinusOperator() error {
-	value := vm.pop().(*object.Integer).Value
-	return vm.push(&object.Integer{Value: -value})
+	return vm.push(&object.Integer{Value: -integer.Value})
 }
 
 func nativeBoolToBoolean(nativeBool bool) object.Object {
@@ -497,18 +971,102 @@ func (vm *VM) currentFrame() *Frame {
 	return vm.frames[vm.framesIndex-1]
 }
 
-func (vm *VM) pushFrame(frame *Frame) {
+// pushFrame fails with a stack overflow when there are too many nested calls
+// or their locals do not fit on the stack.
+func (vm *VM) pushFrame(frame *Frame) error {
+	if vm.framesIndex >= MaxFrames || frame.basePointer+frame.closure.Function.LocalsCount > StackSize {
+		return errors.New("stack overflow")
+	}
+
 	vm.frames[vm.framesIndex] = frame
 	vm.framesIndex++
+
+	if vm.trace != nil {
+		vm.trace.begin(vm.traceThread, object.FrameName(frame.closure.Function.Name))
+	}
+
+	return nil
+}
+
+// returnFrom leaves value where the call of frame was. Returning from the
+// main frame, which has no call, ends the program with value as the last one
+// popped.
+func (vm *VM) returnFrom(frame *Frame, value object.Object) error {
+	if frame.basePointer == 0 {
+		vm.sp = 0
+		vm.stack[0] = value
+		return nil
+	}
+
+	vm.sp = frame.basePointer - 1
+
+	return vm.push(value)
 }
 
 func (vm *VM) popFrame() *Frame {
+	if vm.trace != nil {
+		vm.trace.end(vm.traceThread)
+	}
+
 	vm.framesIndex--
 	return vm.frames[vm.framesIndex]
 }
 
-func (vm *VM) pop() object.Object {
+func (vm *VM) pop() (object.Object, error) {
+	err := vm.requireStack(1)
+	if err != nil {
+		return nil, err
+	}
+
 	result := vm.stack[vm.sp-1]
 	vm.sp--
-	return result
+	return result, nil
+}
+
+// popTwo pops the operands of a binary operation, the right one first.
+func (vm *VM) popTwo() (object.Object, object.Object, error) {
+	err := vm.requireStack(2)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	right := vm.stack[vm.sp-1]
+	left := vm.stack[vm.sp-2]
+	vm.sp -= 2
+	return left, right, nil
+}
+
+// requireStack fails unless count values were pushed in the current frame,
+// above its locals. Compiled code never takes more, so running out means
+// the bytecode is malformed.
+func (vm *VM) requireStack(count int) error {
+	floor := 0
+	if vm.framesIndex > 0 {
+		frame := vm.currentFrame()
+		floor = frame.basePointer + frame.closure.Function.LocalsCount
+	}
+
+	if vm.sp-count < floor {
+		return &StackUnderflowError{}
+	}
+
+	return nil
+}
+
+// StackUnderflowError stops an instruction taking more values from the stack
+// than it holds. IP and Op tell the instruction.
+type StackUnderflowError struct {
+	IP int
+	Op code.Opcode
+	// located is set once the instruction is known.
+	located bool
+}
+
+func (err *StackUnderflowError) Error() string {
+	name := fmt.Sprintf("%d", err.Op)
+	if definition, lookupErr := code.Lookup(err.Op); lookupErr == nil {
+		name = definition.Name
+	}
+
+	return fmt.Sprintf("stack underflow at ip=%d op=%s", err.IP, name)
 }
@@ -1,8 +1,16 @@
 package vm
 
 import (
-	"github.com/stretchr/testify/assert"
+	"spike-interpreter-go/spike/code"
+	"spike-interpreter-go/spike/compiler"
+	"spike-interpreter-go/spike/lexer"
+	"spike-interpreter-go/spike/object"
+	"spike-interpreter-go/spike/parser"
+	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_Run_withError(t *testing.T) {
@@ -12,7 +20,295 @@ func Test_Run_withError(t *testing.T) {
 	}{
 		{
 			code:          `let f = fn(a) { a }; f(1, 2)`,
-			expectedError: "mismatched number of function call arguments. Expected 1, got 2",
+			expectedError: "wrong number of arguments: want 1, got 2 (function defined at line 1)",
+		},
+		{
+			code:          "let add = fn(a, b) {\n  a + b\n};\nadd(1, 2, 3)",
+			expectedError: "wrong number of arguments: want 2, got 3 (function defined at line 1)",
+		},
+		{
+			code:          "let f = 1;\nlet g = fn*(a) { yield a };\ng()",
+			expectedError: "wrong number of arguments: want 1, got 0 (function defined at line 2)",
+		},
+		{
+			code:          `5(1, 2)`,
+			expectedError: "calling non-function: integer",
+		},
+		{
+			code:          `reduce([1], 0, "f")`,
+			expectedError: "calling non-function: string",
+		},
+		{
+			code:          `len(1)`,
+			expectedError: "len: argument of type integer is not supported",
+		},
+		{
+			code:          `len("a", "b")`,
+			expectedError: "len: expected 1 argument, got 2",
+		},
+		{
+			code:          `push(1, 2)`,
+			expectedError: "push: argument 1 must be array, got integer",
+		},
+		{
+			code:          `map([1], fn(a, b) { a })`,
+			expectedError: "wrong number of arguments: want 2, got 1 (function defined at line 1)",
+		},
+		{
+			code:          `callDepth(1)`,
+			expectedError: "callDepth: expected 0 arguments, got 1",
+		},
+		{
+			code:          `randInt(2, 1)`,
+			expectedError: "randInt: min 2 is greater than max 1",
+		},
+		{
+			code:          `seed("x")`,
+			expectedError: "seed: argument 1 must be integer, got string",
+		},
+		{
+			code:          `has({}, [])`,
+			expectedError: "has: unusable as hash key: array",
+		},
+		{
+			code:          `{fn() { 1 }: 1}`,
+			expectedError: "unusable as hash key: closure",
+		},
+		{
+			code:          `{"a": 1, [1]: 2}`,
+			expectedError: "unusable as hash key: array",
+		},
+		{
+			code:          `{1: 2}[[1]]`,
+			expectedError: "unusable as hash key: array",
+		},
+		{
+			code:          `let x, y = 1`,
+			expectedError: "cannot destructure integer into 2 names",
+		},
+		{
+			code:          `let f = fn() { return 1, 2, 3 }; let x, y = f()`,
+			expectedError: "cannot destructure tuple of 3 elements into 2 names",
+		},
+		{
+			code:          `enum Color { Red }; Color.Blue`,
+			expectedError: "Color has no member Blue",
+		},
+		{
+			code:          `let a = 1; a.b`,
+			expectedError: "integer has no members",
+		},
+		{
+			code:          `let g = fn*() { yield 1; len(1) }; let it = g(); next(it); next(it)`,
+			expectedError: "len: argument of type integer is not supported",
+		},
+		{
+			code:          `doc(1)`,
+			expectedError: "doc: argument 1 must be function, got integer",
+		},
+		{
+			code:          `1 + 2.5`,
+			expectedError: "float literals are not supported yet: 2.5 at 1:5",
+		},
+		{
+			code:          `let a = freeze([1]); a[0] = 2`,
+			expectedError: "cannot modify frozen array",
+		},
+		{
+			code:          `let a = [1]; a[1] = 2`,
+			expectedError: "index 1 out of range [0..0]",
+		},
+		{
+			code:          "let xs = [1, 2, 3, 4];\nxs[7]",
+			expectedError: "index 7 out of range [0..3]",
+		},
+		{
+			code:          `bytes("hé")[3]`,
+			expectedError: "index 3 out of range [0..2]",
+		},
+		{
+			code:          `[][0]`,
+			expectedError: "index 0 out of range, length is 0",
+		},
+		{
+			code:          `"żółw"[-1]`,
+			expectedError: "index -1 out of range [0..3]",
+		},
+		{
+			code:          `let f = fn(x) { fn() { x = 1 } }; f(1)()`,
+			expectedError: "cannot assign to captured variable x at 1:24",
+		},
+		{
+			code:          `len = 1`,
+			expectedError: "cannot assign to builtin len at 1:1",
+		},
+		{
+			code:          `"abc"[0] = "x"`,
+			expectedError: "index assignment not supported: string",
+		},
+		{
+			code:          `let h = {}; h[[]] = 1`,
+			expectedError: "unusable as hash key: array",
+		},
+		{
+			code:          `y = 1`,
+			expectedError: "unable to resolve identifier: y at 1:1",
+		},
+		{
+			code:          `let x = x + 1;`,
+			expectedError: "unable to resolve identifier: x at 1:9",
+		},
+		{
+			code:          `let f = fn() { let x = len(x); x }; f()`,
+			expectedError: "unable to resolve identifier: x at 1:28",
+		},
+		{
+			code:          `if (false) { let y = 1 }; y`,
+			expectedError: "identifier used before it was defined",
+		},
+		{
+			code:          `let f = fn(n) { if (n > 0) { let y = n }; y }; f(1); f(0)`,
+			expectedError: "identifier used before it was defined",
+		},
+		{
+			code:          `keys([])`,
+			expectedError: "keys: argument 1 must be hash, got array",
+		},
+		{
+			code:          `sleep(-1)`,
+			expectedError: "sleep: duration must not be negative, got -1",
+		},
+		{
+			code:          `input("a", "b")`,
+			expectedError: "input: expected at most 1 argument, got 2",
+		},
+		{
+			code:          `assert(1 == 1); assert(2 > 3, "2 is not greater than 3")`,
+			expectedError: "assertion failed: 2 is not greater than 3",
+		},
+		{
+			code:          `let f = fn() { assert(false) }; f()`,
+			expectedError: "assertion failed",
+		},
+		{
+			code:          `assert(1)`,
+			expectedError: "assert: argument 1 must be boolean, got integer",
+		},
+		{
+			code:          `forAll({"x": "integer"}, fn(x) { x < 10 })`,
+			expectedError: `forAll: property failed for {"x": 10}`,
+		},
+		{
+			code:          `let check = fn(x) { assert(x < 10, "too big") }; forAll({"x": "integer"}, fn(x) { check(x) })`,
+			expectedError: `forAll: property failed for {"x": 10}: assertion failed: too big`,
+		},
+		{
+			code:          `forAll({"xs": {"type": "array", "of": "integer"}}, fn(xs) { len(xs) < 3 })`,
+			expectedError: `forAll: property failed for {"xs": [0, 0, 0]}`,
+		},
+		{
+			code:          `forAll({"x": "float"}, fn(x) { true })`,
+			expectedError: `forAll: generator "x": unknown type float`,
+		},
+		{
+			code:          `forAll({"x": {"type": "integer", "min": 2, "max": 1}}, fn(x) { true })`,
+			expectedError: `forAll: generator "x": min 2 is greater than max 1`,
+		},
+		{
+			code:          `exit("1")`,
+			expectedError: "exit: argument 1 must be integer, got string",
+		},
+		{
+			code:          `exit(256)`,
+			expectedError: "exit: code must be between 0 and 255, got 256",
+		},
+		{
+			code:          `exit(-1)`,
+			expectedError: "exit: code must be between 0 and 255, got -1",
+		},
+		{
+			code:          `iter(1)`,
+			expectedError: "iter: argument of type integer is not iterable",
+		},
+		{
+			code:          `next([1])`,
+			expectedError: "next: argument 1 must be iterator, got array",
+		},
+		{
+			code:          `range(1, 2, 0)`,
+			expectedError: "range: step must not be zero",
+		},
+		{
+			code:          `slice("abc", 2, 4)`,
+			expectedError: "slice: bounds [2:4] out of range for length 3",
+		},
+		{
+			code:          `bytes([256])`,
+			expectedError: "bytes: element 0 must be an integer between 0 and 255, got 256",
+		},
+		{
+			code:          `bigint("12x")`,
+			expectedError: `bigint: invalid integer "12x"`,
+		},
+		{
+			code:          `bigint(1) / 0`,
+			expectedError: "division by zero",
+		},
+		{
+			code:          `bigint(1) + "a"`,
+			expectedError: "type mismatch: bigint + string",
+		},
+		{
+			code:          `1 / 0`,
+			expectedError: "division by zero",
+		},
+		{
+			code:          `"a" - 1`,
+			expectedError: "type mismatch: string - integer",
+		},
+		{
+			code:          `1 + true`,
+			expectedError: "type mismatch: integer + boolean",
+		},
+		{
+			code:          `-true`,
+			expectedError: "type mismatch: -boolean",
+		},
+		{
+			code:          `"a" < "b"`,
+			expectedError: "unable to compare variables of type string and string",
+		},
+		{
+			code:          `[1] * [2]`,
+			expectedError: "type mismatch: array * array",
+		},
+		{
+			code:          `{} == {}`,
+			expectedError: "unable to compare variables of type hash and hash",
+		},
+		{
+			code:          `true < false`,
+			expectedError: "unable to compare variables of type boolean and boolean",
+		},
+		{
+			code:          `1[0]`,
+			expectedError: "index operator not supported: integer",
+		},
+		{
+			code:          `"abc"["a"]`,
+			expectedError: "String index must be an integer, got: string",
+		},
+		{
+			code:          `[1][true]`,
+			expectedError: "Array index must be an integer, got: boolean",
+		},
+		{
+			code:          `let f = fn(n) { f(n + 1) + 1 }; f(0)`,
+			expectedError: "stack overflow",
+		},
+		{
+			code:          `let g = fn*() { let it = g(); next(it) }; next(g())`,
+			expectedError: "stack overflow",
 		},
 	}
 
@@ -24,3 +320,123 @@ func Test_Run_withError(t *testing.T) {
 		})
 	}
 }
+
+func Test_Run_errorPosition(t *testing.T) {
+	testCases := []struct {
+		code             string
+		expectedPosition lexer.Position
+	}{
+		{
+			code:             "let x = {};\n1 + x[[1]]",
+			expectedPosition: lexer.Position{Line: 2, Column: 5, Offset: 16},
+		},
+		{
+			code:             "let f = fn(a) {\n  len(a)\n};\nf(1)",
+			expectedPosition: lexer.Position{Line: 2, Column: 3, Offset: 18},
+		},
+		{
+			code:             "map([1, 2], fn(x) { len(x) })",
+			expectedPosition: lexer.Position{Line: 1, Column: 21, Offset: 20},
+		},
+		{
+			code:             "let xs = [1, 2, 3, 4];\n1 + xs[7]",
+			expectedPosition: lexer.Position{Line: 2, Column: 5, Offset: 27},
+		},
+		{
+			code:             "let f = fn(a) { a };\n\nf()",
+			expectedPosition: lexer.Position{Line: 3, Column: 1, Offset: 22},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			program, err := parser.New(lexer.New(strings.NewReader(testCase.code))).ParseProgram()
+			assert.NoError(t, err)
+
+			c := compiler.New()
+			assert.NoError(t, c.Compile(program))
+
+			machine := New(c.Bytecode())
+			assert.Error(t, machine.Run())
+
+			position, ok := machine.ErrorPosition()
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expectedPosition, position)
+		})
+	}
+}
+
+func Test_Run_maxSteps(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(
+		"let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } };\nf(500)",
+	))).ParseProgram()
+	assert.NoError(t, err)
+
+	c := compiler.New()
+	assert.NoError(t, c.Compile(program))
+
+	assert.EqualError(t, New(c.Bytecode(), WithMaxSteps(100)).Run(), "step limit of 100 exceeded")
+	assert.NoError(t, New(c.Bytecode(), WithMaxSteps(100000)).Run())
+}
+
+func Test_Run_maxStepsInGenerator(t *testing.T) {
+	_, err := runInVM("let f = fn(n) { if (n == 0) { 0 } else { f(n - 1) } };\nlet g = fn*() { yield f(500) };\nnext(g())", WithMaxSteps(100))
+
+	assert.EqualError(t, err, "step limit of 100 exceeded")
+}
+
+func Test_Run_stackUnderflow(t *testing.T) {
+	testCases := []struct {
+		instructions  [][]byte
+		expectedError string
+	}{
+		{
+			instructions:  [][]byte{instruction(code.OpPop)},
+			expectedError: "stack underflow at ip=0 op=OpPop",
+		},
+		{
+			instructions: [][]byte{
+				instruction(code.OpConstant, 0),
+				instruction(code.OpAdd),
+			},
+			expectedError: "stack underflow at ip=3 op=OpAdd",
+		},
+		{
+			instructions: [][]byte{
+				instruction(code.OpConstant, 0),
+				instruction(code.OpArray, 2),
+			},
+			expectedError: "stack underflow at ip=3 op=OpArray",
+		},
+		{
+			instructions:  [][]byte{instruction(code.OpMinus)},
+			expectedError: "stack underflow at ip=0 op=OpMinus",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expectedError, func(t *testing.T) {
+			var instructions code.Instructions
+			for _, instruction := range testCase.instructions {
+				instructions = append(instructions, instruction...)
+			}
+
+			err := New(&compiler.Bytecode{
+				Instructions: instructions,
+				Constants:    []object.Object{&object.Integer{Value: 1}},
+			}).Run()
+
+			assert.EqualError(t, err, testCase.expectedError)
+			assert.IsType(t, &StackUnderflowError{}, errors.Cause(err))
+		})
+	}
+}
+
+func instruction(opcode code.Opcode, operands ...int) []byte {
+	instruction, err := code.Make(opcode, operands...)
+	if err != nil {
+		panic(err)
+	}
+
+	return instruction
+}
@@ -1,17 +1,29 @@
 package vm
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"spike-interpreter-go/spike/compiler"
 	"spike-interpreter-go/spike/lexer"
 	"spike-interpreter-go/spike/object"
 	"spike-interpreter-go/spike/parser"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_Run(t *testing.T) {
+	factorial25, _ := new(big.Int).SetString("15511210043330985984000000", 10)
+
 	testCases := []struct {
 		code             string
 		expectedStackTop object.Object
@@ -32,6 +44,18 @@ func Test_Run(t *testing.T) {
 			code:             "100 / (5 - 6) * 2",
 			expectedStackTop: &object.Integer{Value: -200},
 		},
+		{
+			code:             "return 1",
+			expectedStackTop: &object.Integer{Value: 1},
+		},
+		{
+			code:             "return;",
+			expectedStackTop: Null,
+		},
+		{
+			code:             "let f = fn() { 2 }; if (true) { return f() + 3 }; 4",
+			expectedStackTop: &object.Integer{Value: 5},
+		},
 		{
 			code:             "true",
 			expectedStackTop: True,
@@ -44,6 +68,10 @@ func Test_Run(t *testing.T) {
 			code:             "1 < 2",
 			expectedStackTop: True,
 		},
+		{
+			code:             "2 < 1",
+			expectedStackTop: False,
+		},
 		{
 			code:             "1 > 2",
 			expectedStackTop: False,
@@ -60,6 +88,30 @@ func Test_Run(t *testing.T) {
 			code:             "1 != 2",
 			expectedStackTop: True,
 		},
+		{
+			code:             `"spi" + "ke" == "spike"`,
+			expectedStackTop: True,
+		},
+		{
+			code:             `"a" != "a"`,
+			expectedStackTop: False,
+		},
+		{
+			code:             "[1, 2] + [3]",
+			expectedStackTop: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}, &object.Integer{Value: 3}}},
+		},
+		{
+			code:             "let a = [1]; let b = a + []; b[0] = 2; a",
+			expectedStackTop: &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+		},
+		{
+			code:             `[1, ["a"]] == [1, ["a"]]`,
+			expectedStackTop: True,
+		},
+		{
+			code:             "[1, 2] != [1]",
+			expectedStackTop: True,
+		},
 		{
 			code:             "-5",
 			expectedStackTop: &object.Integer{Value: -5},
@@ -92,6 +144,14 @@ func Test_Run(t *testing.T) {
 			code:             "if (false) { 10 };",
 			expectedStackTop: Null,
 		},
+		{
+			code:             "if (true) {}",
+			expectedStackTop: Null,
+		},
+		{
+			code:             "if (false) { 10 } else { let a = 20; }",
+			expectedStackTop: Null,
+		},
 		{
 			code:             "let one = 1; one;",
 			expectedStackTop: &object.Integer{Value: 1},
@@ -104,6 +164,10 @@ func Test_Run(t *testing.T) {
 			code:             "let one = 1; let two = one + one; one + two;",
 			expectedStackTop: &object.Integer{Value: 3},
 		},
+		{
+			code:             "let one = 1; let one = one + 1; one;",
+			expectedStackTop: &object.Integer{Value: 2},
+		},
 		{
 			code:             `"spike"`,
 			expectedStackTop: &object.String{Value: "spike"},
@@ -146,6 +210,9 @@ func Test_Run(t *testing.T) {
 					Key:   &object.Integer{Value: 2},
 					Value: &object.Integer{Value: 3},
 				},
+			}, Keys: []object.HashKey{
+				(&object.Integer{Value: 1}).GetHashKey(),
+				(&object.Integer{Value: 2}).GetHashKey(),
 			}},
 		},
 		{
@@ -155,6 +222,15 @@ func Test_Run(t *testing.T) {
 					Key:   &object.Integer{Value: 3},
 					Value: &object.Integer{Value: -1},
 				},
+			}, Keys: []object.HashKey{(&object.Integer{Value: 3}).GetHashKey()}},
+		},
+		{
+			code: "let order = []; let f = fn(x) { order = push(order, x); x }; {f(\"b\"): f(1), f(\"a\"): f(2)}; order",
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "b"},
+				&object.Integer{Value: 1},
+				&object.String{Value: "a"},
+				&object.Integer{Value: 2},
 			}},
 		},
 		{
@@ -165,10 +241,6 @@ func Test_Run(t *testing.T) {
 			code:             `[1, 2, 3][1 + 1]`,
 			expectedStackTop: &object.Integer{Value: 3},
 		},
-		{
-			code:             `[][1]`,
-			expectedStackTop: Null,
-		},
 		{
 			code:             `{"name": "kenny", "age": 31}["age"]`,
 			expectedStackTop: &object.Integer{Value: 31},
@@ -185,6 +257,10 @@ func Test_Run(t *testing.T) {
 			code:             `let f = fn () { return 5 + 10 }; f();`,
 			expectedStackTop: &object.Integer{Value: 15},
 		},
+		{
+			code:             `let f = fn () { return; 5 }; f();`,
+			expectedStackTop: Null,
+		},
 		{
 			code:             `let f = fn () { }; f();`,
 			expectedStackTop: Null,
@@ -253,6 +329,366 @@ func Test_Run(t *testing.T) {
 			code:             `len([1, 2, 3, 4])`,
 			expectedStackTop: &object.Integer{Value: 4},
 		},
+		{
+			code:             `len({1: 2, "a": [], true: 3})`,
+			expectedStackTop: &object.Integer{Value: 3},
+		},
+		{
+			code:             `len("") + len([len("ab"), len([])])`,
+			expectedStackTop: &object.Integer{Value: 2},
+		},
+		{
+			code: `let a = [1, 2]; let b = push(a, 3); [len(a), last(b), first(rest(b)), len(pop(b))]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 2},
+			}},
+		},
+		{
+			code: `
+			let double = fn(x) { x * 2 };
+			let isBig = fn(x) { x > 2 };
+			let sum = fn(acc, x) { acc + x };
+			let values = map([1, 2, 3], double);
+			[values, filter(values, isBig), reduce(values, 0, sum), map(["a", [1, 2]], len)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 6},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 6},
+				}},
+				&object.Integer{Value: 12},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+				}},
+			}},
+		},
+		{
+			code: `
+			let outer = fn(xs) { map(xs, fn(x) { reduce(x, 0, fn(a, b) { a + b }) }) };
+			outer([[1, 2], [], [3]])`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 0},
+				&object.Integer{Value: 3},
+			}},
+		},
+		{
+			code: `
+			let inner = fn() { [callDepth(), stackTrace()] };
+			let outer = fn() { first(map([1], fn(x) { inner() })) };
+			[callDepth(), outer()]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 0},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 3},
+					&object.Array{Elements: []object.Object{
+						&object.String{Value: "inner"},
+						&object.String{Value: "<anonymous>"},
+						&object.String{Value: "outer"},
+						&object.String{Value: "<main>"},
+					}},
+				}},
+			}},
+		},
+		{
+			code:             `join(map(split(" a, b ,c ", ","), fn(s) { upper(trim(s)) }), "-")`,
+			expectedStackTop: &object.String{Value: "A-B-C"},
+		},
+		{
+			code:             `seed(3); let a = randInt(0, 1000000); seed(3); a == randInt(0, 1000000)`,
+			expectedStackTop: &object.Boolean{Value: true},
+		},
+		{
+			code: `
+			let sorted = fn(xs) { reduce(xs, [], fn(acc, x) { filter(acc, fn(y) { y < x }) + [x] + filter(acc, fn(y) { !(y < x) }) }) };
+			forAll({"xs": {"type": "array", "of": {"type": "integer", "min": 0}, "max": 5}, "b": "boolean"}, fn(xs, b) {
+				assert(len(sorted(xs)) == len(xs));
+				b == b
+			})`,
+			expectedStackTop: Null,
+		},
+		{
+			code: `
+			let h = merge({"b": 2, "a": 1}, {"c": 3, "a": 0});
+			let d = delete(h, "b");
+			[keys(d), values(d), has(h, "b"), has(d, "b"), len(h)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "a"},
+					&object.String{Value: "c"},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 0},
+					&object.Integer{Value: 3},
+				}},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+				&object.Integer{Value: 3},
+			}},
+		},
+		{
+			code: `map([1, "a", true, [], {}, len, type(1)], type)`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "integer"},
+				&object.String{Value: "string"},
+				&object.String{Value: "boolean"},
+				&object.String{Value: "array"},
+				&object.String{Value: "hash"},
+				&object.String{Value: "builtinFunction"},
+				&object.String{Value: "string"},
+			}},
+		},
+		{
+			code:             `type(fn() {})`,
+			expectedStackTop: &object.String{Value: "closure"},
+		},
+		{
+			code:             `assert(true, "never shown")`,
+			expectedStackTop: Null,
+		},
+		{
+			code: `let digits = regex("[0-9]+"); filter(["a1", "b", "22"], fn(s) { regexMatch(digits, s) })`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "a1"},
+				&object.String{Value: "22"},
+			}},
+		},
+		{
+			code: `filter([0, first([]), "", false, [], 1], fn(x) { x })`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 0},
+				&object.String{Value: ""},
+				&object.Array{Elements: []object.Object{}},
+				&object.Integer{Value: 1},
+			}},
+		},
+		{
+			code: `let a = [1, {"b": [2]}]; deepCopy(a)`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 1},
+				&object.Hash{Pairs: map[object.HashKey]object.HashPair{
+					(&object.String{Value: "b"}).GetHashKey(): {
+						Key:   &object.String{Value: "b"},
+						Value: &object.Array{Elements: []object.Object{&object.Integer{Value: 2}}},
+					},
+				}, Keys: []object.HashKey{(&object.String{Value: "b"}).GetHashKey()}},
+			}},
+		},
+		{
+			code: `
+			let it = iter([1, 2]);
+			[next(it), next(it), next(it), map(range(3), fn(x) { x * x }), map("hé", upper), filter({"b": 1, "a": 2}, fn(k) { true })]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 1},
+				&object.Integer{Value: 2},
+				&object.NullObject,
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 0},
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 4},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "H"},
+					&object.String{Value: "É"},
+				}},
+				&object.Array{Elements: []object.Object{
+					&object.String{Value: "b"},
+					&object.String{Value: "a"},
+				}},
+			}},
+		},
+		{
+			code:             `range(10, 0, -3)`,
+			expectedStackTop: &object.Range{Start: 10, End: 0, Step: -3},
+		},
+		{
+			code: `let b = bytes("hé"); [len(b), b[0], b[2], slice(b, 1, 3), slice([1, 2, 3], 1, 3), slice("spike", 0, 2), bytes([0, 255])]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 3},
+				&object.Integer{Value: 104},
+				&object.Integer{Value: 169},
+				&object.Bytes{Value: []byte{195, 169}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+				&object.String{Value: "sp"},
+				&object.Bytes{Value: []byte{0, 255}},
+			}},
+		},
+		{
+			code: `
+			let factorial = fn(n) { if (n == 0) { bigint(1) } else { n * factorial(n - 1) } };
+			let big = factorial(25);
+			[big, -big / bigint("1000000000000000000000000") - 1, big > 1, 2 == bigint(2), type(big)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.BigInt{Value: factorial25},
+				&object.BigInt{Value: big.NewInt(-16)},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: true},
+				&object.String{Value: "bigint"},
+			}},
+		},
+		{
+			code: `[!first([]), !0, !"", if (first([])) { 1 } else { 2 }, if (0) { 1 } else { 2 },
+			first([]) == first([]), first([]) != 1, 1 == first([])]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+				&object.Boolean{Value: false},
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 1},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: true},
+				&object.Boolean{Value: false},
+			}},
+		},
+		{
+			code:             `if (false) { 1 }`,
+			expectedStackTop: &object.NullObject,
+		},
+		{
+			code: `let h = merge({"b": 1, "a": 2}, {"c": 3, "b": 4}); [keys(delete(h, "a")), values(h)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{&object.String{Value: "b"}, &object.String{Value: "c"}}},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 4},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+			}},
+		},
+		{
+			code: `let h = {true: "yes", false: "no", 1: "one"}; [h[1 == 1], h[false], h[1], keys(h)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "yes"},
+				&object.String{Value: "no"},
+				&object.String{Value: "one"},
+				&object.Array{Elements: []object.Object{&object.True, &object.False, &object.Integer{Value: 1}}},
+			}},
+		},
+		{
+			code: `let letter = fn(i) { ["a", "b"][i] + {"x": "c"}["x"] }; let a = [[1]][0]; a[0] = 2;
+			let frozen = fn() { freeze([[1]]) };
+			[letter(0), letter(1), [1] + [2], a, [[1]][0], frozen() == frozen(), frozen()]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "ac"},
+				&object.String{Value: "bc"},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 2}}},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}},
+				&object.Boolean{Value: true},
+				&object.Array{Elements: []object.Object{
+					&object.Array{Elements: []object.Object{&object.Integer{Value: 1}}, Frozen: true},
+				}, Frozen: true},
+			}},
+		},
+		{
+			code: `let config = fn() { {"ports": [80]} }; let frozen = freeze(config());
+			[frozen["ports"], config()["ports"]]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 80}}, Frozen: true},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 80}}},
+			}},
+		},
+		{
+			code: `let s = "żółw"; [len(s), s[1], s[3], slice(s, 1, 3), len(bytes(s)), indexOf(s, "w")]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 4},
+				&object.String{Value: "ó"},
+				&object.String{Value: "w"},
+				&object.String{Value: "ół"},
+				&object.Integer{Value: 7},
+				&object.Integer{Value: 3},
+			}},
+		},
+		{
+			code: `
+			let swap = fn(a, b) { return b, a };
+			let x, y = swap(1, 2);
+			let g = fn() { let p, q = swap(3, 4); [p, q] };
+			[x, y, g(), len(swap(1, 2)), swap(5, 6)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 2},
+				&object.Integer{Value: 1},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 4}, &object.Integer{Value: 3}}},
+				&object.Integer{Value: 2},
+				&object.Tuple{Elements: []object.Object{&object.Integer{Value: 6}, &object.Integer{Value: 5}}},
+			}},
+		},
+		{
+			code: `
+			enum Color { Red, Green, Blue };
+			let isRed = fn(c) { c == Color.Red };
+			[isRed(Color.Red), isRed(Color.Blue), Color.Green != Color.Green, Color.Blue == 2, type(Color.Red)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.True,
+				&object.False,
+				&object.False,
+				&object.False,
+				&object.String{Value: "enumMember"},
+			}},
+		},
+		{
+			code: `
+			let step = 2;
+			let count = fn*(n) { let doubled = n * 2; yield n; yield doubled; yield doubled + step; };
+			let g = count(10);
+			let first = next(g);
+			[first, map(g, fn(x) { x + 1 }), next(g), map(count(1), fn(x) { x })]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 10},
+				&object.Array{Elements: []object.Object{&object.Integer{Value: 21}, &object.Integer{Value: 23}}},
+				&object.NullObject,
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 1},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 4},
+				}},
+			}},
+		},
+		{
+			code: `let f = fn(x) { "Doubles x."; x * 2 }; [doc(f), f(2), doc(fn() { 1 }), doc(len)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.String{Value: "Doubles x."},
+				&object.Integer{Value: 4},
+				&object.NullObject,
+				&object.NullObject,
+			}},
+		},
+		{
+			code: `
+			let total = 1;
+			let add = fn(x) { let local = x; local = local + 1; total = total + local; total };
+			let a = [1, 2, 3];
+			let h = {"a": 1};
+			let r = a[0] = h["b"] = 5;
+			add(1);
+			[total, a, h["b"], r, add(10)]`,
+			expectedStackTop: &object.Array{Elements: []object.Object{
+				&object.Integer{Value: 3},
+				&object.Array{Elements: []object.Object{
+					&object.Integer{Value: 5},
+					&object.Integer{Value: 2},
+					&object.Integer{Value: 3},
+				}},
+				&object.Integer{Value: 5},
+				&object.Integer{Value: 5},
+				&object.Integer{Value: 14},
+			}},
+		},
+		{
+			code:             `equalsIgnoreCase("ǅungla", "Ǆungla")`,
+			expectedStackTop: &object.Boolean{Value: true},
+		},
 		{
 			code: `
 			let createClosure = fn (a) {
@@ -287,7 +723,292 @@ func Test_Run(t *testing.T) {
 	}
 }
 
-func runInVM(input string) (object.Object, error) {
+func Test_Run_printToConfiguredStdout(t *testing.T) {
+	code := `print("a", 1); println([true], "b"); println(); printf("%s=%d", "x", 5)`
+	stdout := &strings.Builder{}
+
+	program, err := parser.New(lexer.New(strings.NewReader(code))).ParseProgram()
+	assert.NoError(t, err)
+
+	c := compiler.New()
+	err = c.Compile(program)
+	assert.NoError(t, err)
+
+	vm := New(c.Bytecode(), WithStdout(stdout))
+	err = vm.Run()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a 1[true] b\n\nx=5", stdout.String())
+	assert.Equal(t, Null, vm.LastPoppedStackElement())
+}
+
+func Test_Run_randomWithConfiguredSource(t *testing.T) {
+	code := `[rand(), randInt(-3, 3), randInt(5, 5)]`
+
+	first, err := runInVM(code, WithRand(rand.New(rand.NewSource(7))))
+	assert.NoError(t, err)
+
+	second, err := runInVM(code, WithRand(rand.New(rand.NewSource(7))))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	elements := first.(*object.Array).Elements
+	assert.True(t, elements[0].(*object.Integer).Value >= 0)
+	assert.InDelta(t, 0, elements[1].(*object.Integer).Value, 3)
+	assert.Equal(t, &object.Integer{Value: 5}, elements[2])
+}
+
+func Test_Run_fileBuiltins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spike")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	code := fmt.Sprintf(`
+		let path = "%s/notes.txt";
+		writeFile(path, "one");
+		appendFile(path, ", two");
+		writeFileBytes("%s/a.txt", bytes([0, 1]));
+		[readFile(path), listDir("%s"), readFileBytes("%s/a.txt")]
+	`, dir, dir, dir, dir)
+
+	result, err := runInVM(code)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.String{Value: "one, two"},
+		&object.Array{Elements: []object.Object{
+			&object.String{Value: "a.txt"},
+			&object.String{Value: "notes.txt"},
+		}},
+		&object.Bytes{Value: []byte{0, 1}},
+	}}, result)
+
+	_, err = runInVM(code, WithPolicy(object.DefaultPolicy().Deny(object.FileWriteCapability)))
+	assert.EqualError(t, err, "writeFile: file write is not permitted by the sandbox policy")
+
+	_, err = runInVM(`readFile("missing")`)
+	assert.EqualError(t, err, "readFile: open missing: no such file or directory")
+}
+
+func Test_Run_printToConfiguredStderr(t *testing.T) {
+	code := `let g = fn*() { eprint("in generator;"); yield 1 }; next(g()); eprintln("x", 1); println("out")`
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+
+	program, err := parser.New(lexer.New(strings.NewReader(code))).ParseProgram()
+	assert.NoError(t, err)
+
+	c := compiler.New()
+	err = c.Compile(program)
+	assert.NoError(t, err)
+
+	vm := New(c.Bytecode(), WithStdout(stdout), WithStderr(stderr))
+	err = vm.Run()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "out\n", stdout.String())
+	assert.Equal(t, "in generator;x 1\n", stderr.String())
+}
+
+func Test_Run_inputFromConfiguredStdin(t *testing.T) {
+	stdout := &strings.Builder{}
+	stdin := strings.NewReader("alice\r\n  word rest\nbob")
+
+	result, err := runInVM(`[input("name? "), read(), input(), input(), input()]`, WithStdin(stdin), WithStdout(stdout))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.String{Value: "alice"},
+		&object.String{Value: "word"},
+		&object.String{Value: " rest"},
+		&object.String{Value: "bob"},
+		Null,
+	}}, result)
+	assert.Equal(t, "name? ", stdout.String())
+}
+
+func Test_Run_exitUnwindsNestedCalls(t *testing.T) {
+	stdout := &strings.Builder{}
+
+	_, err := runInVM(`
+		let f = fn() { map([1, 2], fn(x) { exit(x + 2) }) };
+		f();
+		print("not reached")
+	`, WithStdout(stdout))
+
+	assert.Equal(t, &object.ExitError{Code: 3}, err)
+	assert.Empty(t, stdout.String())
+}
+
+func Test_Run_argsBuiltin(t *testing.T) {
+	result, err := runInVM(`[args(), len(args())]`, WithArgs([]string{"a", "b"}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.Array{Elements: []object.Object{
+			&object.String{Value: "a"},
+			&object.String{Value: "b"},
+		}},
+		&object.Integer{Value: 2},
+	}}, result)
+
+	result, err = runInVM(`let g = fn*() { yield args() }; next(g())`, WithArgs([]string{"a"}))
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{&object.String{Value: "a"}}}, result)
+
+	result, err = runInVM(`args()`)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{}}, result)
+}
+
+func Test_Run_httpBuiltins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Test", r.Header.Get("X-Token"))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.Path, body)
+	}))
+	defer server.Close()
+
+	code := fmt.Sprintf(`
+		let get = httpGet("%s/a");
+		let post = httpRequest({"url": "%s/b", "method": "post", "headers": {"X-Token": "t"}, "body": "data"});
+		[get["status"], get["body"], post["body"], post["headers"]["X-Test"]]
+	`, server.URL, server.URL)
+
+	result, err := runInVM(code)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.Integer{Value: 201},
+		&object.String{Value: "GET /a "},
+		&object.String{Value: "POST /b data"},
+		&object.String{Value: "t"},
+	}}, result)
+
+	_, err = runInVM(code, WithPolicy(object.DefaultPolicy().Deny(object.NetworkCapability)))
+	assert.EqualError(t, err, "httpGet: network access is not permitted by the sandbox policy")
+
+	_, err = runInVM(`httpRequest({"method": "GET"})`)
+	assert.EqualError(t, err, `httpRequest: missing "url"`)
+}
+
+func Test_Run_execBuiltin(t *testing.T) {
+	code := `let result = exec("sh", "-c", "echo out; echo err >&2; exit 3"); [result["stdout"], result["stderr"], result["exitCode"]]`
+
+	_, err := runInVM(code)
+	assert.EqualError(t, err, "exec: running processes is not permitted by the sandbox policy")
+
+	result, err := runInVM(code, WithPolicy(object.DefaultPolicy().Allow(object.ProcessCapability)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.String{Value: "out\n"},
+		&object.String{Value: "err\n"},
+		&object.Integer{Value: 3},
+	}}, result)
+}
+
+func Test_Run_modules(t *testing.T) {
+	modules := WithModules(map[string]*object.Module{
+		"text": {Name: "text", Members: map[string]object.Object{"upper": object.GetBuiltinByName("upper")}},
+	})
+
+	result, err := runInVM(`import "text"; let f = fn() { import "text"; text.upper("a") }; [text.upper("b"), f(), type(text)]`, modules)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{
+		&object.String{Value: "B"},
+		&object.String{Value: "A"},
+		&object.String{Value: "module"},
+	}}, result)
+
+	_, err = runInVM(`import "text"; text.lower("A")`, modules)
+	assert.EqualError(t, err, "module text has no member lower")
+
+	_, err = runInVM(`import "text"`)
+	assert.EqualError(t, err, "unknown module text")
+}
+
+func Test_Run_policyDeniesBuiltins(t *testing.T) {
+	policy := WithPolicy(object.PurePolicy())
+
+	result, err := runInVM(`map(["a", "bc"], len)`, policy)
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}}, result)
+
+	_, err = runInVM(`sleep(1)`, policy)
+	assert.EqualError(t, err, "sleep is not permitted by the sandbox policy")
+
+	_, err = runInVM(`map([1], clock)`, policy)
+	assert.EqualError(t, err, "clock is not permitted by the sandbox policy")
+
+	_, err = runInVM(`len("a") + first([1])`, WithPolicy(object.DefaultPolicy().AllowOnlyBuiltins("len")))
+	assert.EqualError(t, err, "first is not permitted by the sandbox policy")
+}
+
+func Test_Run_timeBuiltins(t *testing.T) {
+	result, err := runInVM(`let start = clock(); sleep(5); [clock() - start > 4999999, now() > 1500000000000]`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &object.Array{Elements: []object.Object{True, True}}, result)
+}
+
+// Run with -race to check VMs running the same bytecode share no mutable
+// state.
+func Test_Run_concurrentlyOnSharedBytecode(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`
+		let numbers = fn*() { yield 1; yield 2 };
+		let it = numbers();
+		let h = {"items": [1, 2]};
+		h["items"] = push(h["items"], next(it) + next(it));
+		h["items"]
+	`))).ParseProgram()
+	assert.NoError(t, err)
+	c := compiler.New()
+	assert.NoError(t, c.Compile(program))
+	bytecode := c.Bytecode()
+
+	var wg sync.WaitGroup
+	results := make([]object.Object, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vm := New(bytecode)
+			if vm.Run() == nil {
+				results[i] = vm.LastPoppedStackElement()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		assert.Equal(t, &object.Array{Elements: []object.Object{
+			&object.Integer{Value: 1},
+			&object.Integer{Value: 2},
+			&object.Integer{Value: 3},
+		}}, result)
+	}
+}
+
+func Test_RunContext_sleepStopsWhenCancelled(t *testing.T) {
+	program, err := parser.New(lexer.New(strings.NewReader(`sleep(60000)`))).ParseProgram()
+	assert.NoError(t, err)
+
+	c := compiler.New()
+	assert.NoError(t, c.Compile(program))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err = New(c.Bytecode()).RunContext(ctx)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(started) < 10*time.Second)
+}
+
+func runInVM(input string, options ...Option) (object.Object, error) {
 	l := lexer.New(strings.NewReader(input))
 	p := parser.New(l)
 	c := compiler.New()
@@ -302,7 +1023,7 @@ func runInVM(input string) (object.Object, error) {
 		return nil, err
 	}
 
-	vm := New(c.Bytecode())
+	vm := New(c.Bytecode(), options...)
 
 	err = vm.Run()
 	if err != nil {